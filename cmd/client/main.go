@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
@@ -57,22 +58,34 @@ func main() {
 		model = val
 	}
 
-	if config.DetectModelType(model) == config.ModelTypeEmbedder {
+	switch modelType(ctx, client, model) {
+	case config.ModelTypeEmbedder:
 		embed(ctx, client, model)
-		return
-	}
 
-	if config.DetectModelType(model) == config.ModelTypeRenderer {
+	case config.ModelTypeRenderer:
 		render(ctx, client, model)
-		return
-	}
 
-	if config.DetectModelType(model) == config.ModelTypeSynthesizer {
+	case config.ModelTypeSynthesizer:
 		synthesize(ctx, client, model)
-		return
+
+	default:
+		chat(ctx, client, model)
+	}
+}
+
+// modelType asks the server for the kind of a model and only guesses it from its id if the server does not tell
+func modelType(ctx context.Context, client *openai.Client, model string) config.ModelType {
+	if m, err := client.Models.Get(ctx, model); err == nil {
+		var metadata struct {
+			Type config.ModelType `json:"type"`
+		}
+
+		if err := json.Unmarshal([]byte(m.JSON.RawJSON()), &metadata); err == nil && metadata.Type != "" {
+			return metadata.Type
+		}
 	}
 
-	chat(ctx, client, model)
+	return config.DetectModelType(model)
 }
 
 func selectModel(ctx context.Context, client *openai.Client) (string, error) {
@@ -1,8 +1,12 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/adrianliechti/wingman/config"
 	"github.com/adrianliechti/wingman/server"
@@ -35,7 +39,10 @@ func main() {
 		panic(err)
 	}
 
-	if err := s.ListenAndServe(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := s.ListenAndServe(ctx); err != nil {
 		panic(err)
 	}
 }
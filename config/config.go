@@ -3,6 +3,7 @@ package config
 import (
 	"bytes"
 	"os"
+	"path/filepath"
 
 	"github.com/adrianliechti/wingman/pkg/api"
 	"github.com/adrianliechti/wingman/pkg/authorizer"
@@ -22,12 +23,16 @@ import (
 type Config struct {
 	Address string
 
+	// Data is the absolute directory for files and batches kept on local disk
+	Data string
+
 	Authorizers []authorizer.Provider
 
 	models map[string]provider.Model
 
 	completer   map[string]provider.Completer
 	embedder    map[string]provider.Embedder
+	moderator   map[string]provider.Moderator
 	renderer    map[string]provider.Renderer
 	reranker    map[string]provider.Reranker
 	synthesizer map[string]provider.Synthesizer
@@ -53,8 +58,16 @@ func Parse(path string) (*Config, error) {
 		return nil, err
 	}
 
+	data, err := dataDir(path, file.Data)
+
+	if err != nil {
+		return nil, err
+	}
+
 	c := &Config{
 		Address: ":8080",
+
+		Data: data,
 	}
 
 	if err := c.registerAuthorizer(file); err != nil {
@@ -104,7 +117,14 @@ func Parse(path string) (*Config, error) {
 	return c, nil
 }
 
+// Close releases the resources of the configured providers
+func (cfg *Config) Close() error {
+	return cfg.closeIndexes()
+}
+
 type configFile struct {
+	Data string `yaml:"data"`
+
 	Authorizers []authorizerConfig `yaml:"authorizers"`
 
 	Providers []providerConfig `yaml:"providers"`
@@ -144,6 +164,19 @@ func parseFile(path string) (*configFile, error) {
 	return &config, nil
 }
 
+// dataDir resolves the data directory against the directory of the config file, so it does not depend on the working directory
+func dataDir(path, dir string) (string, error) {
+	if dir == "" {
+		dir = "data"
+	}
+
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(filepath.Dir(path), dir)
+	}
+
+	return filepath.Abs(dir)
+}
+
 func createLimiter(limit *int) *rate.Limiter {
 	if limit == nil {
 		return nil
@@ -2,7 +2,9 @@ package config
 
 import (
 	"errors"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/adrianliechti/wingman/pkg/index"
 	"github.com/adrianliechti/wingman/pkg/limiter"
@@ -22,7 +24,7 @@ import (
 )
 
 func (cfg *Config) RegisterChain(id string, p chain.Provider) {
-	cfg.RegisterModel(id)
+	cfg.RegisterModel(provider.Model{ID: id, Type: ModelTypeCompleter})
 
 	if cfg.chains == nil {
 		cfg.chains = make(map[string]chain.Provider)
@@ -31,9 +33,25 @@ func (cfg *Config) RegisterChain(id string, p chain.Provider) {
 	cfg.chains[id] = p
 }
 
+// Chains returns the ids of the configured chains
+func (cfg *Config) Chains() []string {
+	var result []string
+
+	for id := range cfg.chains {
+		result = append(result, id)
+	}
+
+	slices.Sort(result)
+
+	return result
+}
+
 type chainConfig struct {
 	Type string `yaml:"type"`
 
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+
 	Index string `yaml:"index"`
 
 	Model  string `yaml:"model"`
@@ -46,6 +64,11 @@ type chainConfig struct {
 
 	Limit       *int     `yaml:"limit"`
 	Temperature *float32 `yaml:"temperature"`
+
+	MaxIterations *int `yaml:"max_iterations"`
+
+	ToolTimeout     *time.Duration `yaml:"tool_timeout"`
+	ToolConcurrency *int           `yaml:"tool_concurrency"`
 }
 
 type chainContext struct {
@@ -152,6 +175,23 @@ func (cfg *Config) registerChains(f *configFile) error {
 			chain = otel.NewChain(config.Type, id, chain)
 		}
 
+		model := provider.Model{
+			ID:   id,
+			Type: ModelTypeCompleter,
+
+			Name:        config.Name,
+			Description: config.Description,
+
+			Provider:     config.Type,
+			Capabilities: []provider.Capability{},
+		}
+
+		// chains pass the request options on, so they support what their model supports
+		if m, err := cfg.Model(config.Model); err == nil {
+			model.Capabilities = m.Capabilities
+		}
+
+		cfg.RegisterModel(model)
 		cfg.RegisterChain(id, chain)
 	}
 
@@ -193,6 +233,18 @@ func agentChain(cfg chainConfig, context chainContext) (chain.Provider, error) {
 		options = append(options, agent.WithEffort(context.Effort))
 	}
 
+	if cfg.MaxIterations != nil {
+		options = append(options, agent.WithMaxIterations(*cfg.MaxIterations))
+	}
+
+	if cfg.ToolTimeout != nil {
+		options = append(options, agent.WithToolTimeout(*cfg.ToolTimeout))
+	}
+
+	if cfg.ToolConcurrency != nil {
+		options = append(options, agent.WithToolConcurrency(*cfg.ToolConcurrency))
+	}
+
 	return agent.New(options...)
 }
 
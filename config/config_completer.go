@@ -9,6 +9,7 @@ import (
 	"github.com/adrianliechti/wingman/pkg/provider/azure"
 	"github.com/adrianliechti/wingman/pkg/provider/bedrock"
 	"github.com/adrianliechti/wingman/pkg/provider/cohere"
+	"github.com/adrianliechti/wingman/pkg/provider/custom"
 	"github.com/adrianliechti/wingman/pkg/provider/google"
 	"github.com/adrianliechti/wingman/pkg/provider/groq"
 	"github.com/adrianliechti/wingman/pkg/provider/huggingface"
@@ -21,7 +22,7 @@ import (
 )
 
 func (cfg *Config) RegisterCompleter(id string, p provider.Completer) {
-	cfg.RegisterModel(id)
+	cfg.RegisterModel(provider.Model{ID: id, Type: ModelTypeCompleter})
 
 	if cfg.completer == nil {
 		cfg.completer = make(map[string]provider.Completer)
@@ -64,6 +65,9 @@ func createCompleter(cfg providerConfig, model modelContext) (provider.Completer
 	case "cohere":
 		return cohereCompleter(cfg, model)
 
+	case "custom":
+		return customCompleter(cfg, model)
+
 	case "github":
 		return azureCompleter(cfg, model)
 
@@ -135,6 +139,12 @@ func cohereCompleter(cfg providerConfig, model modelContext) (provider.Completer
 	return cohere.NewCompleter(model.ID, options...)
 }
 
+func customCompleter(cfg providerConfig, model modelContext) (provider.Completer, error) {
+	var options []custom.Option
+
+	return custom.NewCompleter(cfg.URL, model.ID, options...)
+}
+
 func googleCompleter(cfg providerConfig, model modelContext) (provider.Completer, error) {
 	var options []google.Option
 
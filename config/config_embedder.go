@@ -7,6 +7,7 @@ import (
 	"github.com/adrianliechti/wingman/pkg/provider"
 	"github.com/adrianliechti/wingman/pkg/provider/azure"
 	"github.com/adrianliechti/wingman/pkg/provider/cohere"
+	"github.com/adrianliechti/wingman/pkg/provider/custom"
 	"github.com/adrianliechti/wingman/pkg/provider/google"
 	"github.com/adrianliechti/wingman/pkg/provider/huggingface"
 	"github.com/adrianliechti/wingman/pkg/provider/jina"
@@ -16,7 +17,7 @@ import (
 )
 
 func (cfg *Config) RegisterEmbedder(id string, p provider.Embedder) {
-	cfg.RegisterModel(id)
+	cfg.RegisterModel(provider.Model{ID: id, Type: ModelTypeEmbedder})
 
 	if cfg.embedder == nil {
 		cfg.embedder = make(map[string]provider.Embedder)
@@ -47,6 +48,9 @@ func createEmbedder(cfg providerConfig, model modelContext) (provider.Embedder,
 	case "cohere":
 		return cohereEmbedder(cfg, model)
 
+	case "custom":
+		return customEmbedder(cfg, model)
+
 	case "github":
 		return azureEmbedder(cfg, model)
 
@@ -93,6 +97,12 @@ func cohereEmbedder(cfg providerConfig, model modelContext) (provider.Embedder,
 	return cohere.NewEmbedder(model.ID, options...)
 }
 
+func customEmbedder(cfg providerConfig, model modelContext) (provider.Embedder, error) {
+	var options []custom.Option
+
+	return custom.NewEmbedder(cfg.URL, model.ID, options...)
+}
+
 func googleEmbedder(cfg providerConfig, model modelContext) (provider.Embedder, error) {
 	var options []google.Option
 
@@ -2,6 +2,8 @@ package config
 
 import (
 	"errors"
+	"io"
+	"slices"
 	"strings"
 
 	"github.com/adrianliechti/wingman/pkg/index"
@@ -10,7 +12,10 @@ import (
 	"github.com/adrianliechti/wingman/pkg/index/custom"
 	"github.com/adrianliechti/wingman/pkg/index/elasticsearch"
 	"github.com/adrianliechti/wingman/pkg/index/memory"
+	"github.com/adrianliechti/wingman/pkg/index/pgvector"
+	"github.com/adrianliechti/wingman/pkg/index/postgrest"
 	"github.com/adrianliechti/wingman/pkg/index/qdrant"
+	"github.com/adrianliechti/wingman/pkg/index/reranker"
 	"github.com/adrianliechti/wingman/pkg/index/weaviate"
 	"github.com/adrianliechti/wingman/pkg/otel"
 )
@@ -37,16 +42,70 @@ func (cfg *Config) Index(id string) (index.Provider, error) {
 	return nil, errors.New("index not found: " + id)
 }
 
+// closeIndexes closes the indexes holding resources, like the snapshots of memory indexes
+func (cfg *Config) closeIndexes() error {
+	var result error
+
+	closed := make(map[index.Provider]bool)
+
+	for _, p := range cfg.indexes {
+		if closed[p] {
+			continue
+		}
+
+		closed[p] = true
+
+		if c, ok := p.(io.Closer); ok {
+			result = errors.Join(result, c.Close())
+		}
+	}
+
+	return result
+}
+
+// Indexes returns the ids of the configured indexes
+func (cfg *Config) Indexes() []string {
+	var result []string
+
+	for id := range cfg.indexes {
+		if id == "" {
+			continue
+		}
+
+		result = append(result, id)
+	}
+
+	slices.Sort(result)
+
+	return result
+}
+
 type indexConfig struct {
 	Type string `yaml:"type"`
 
 	URL   string `yaml:"url"`
 	Token string `yaml:"token"`
 
+	Path      string `yaml:"path"`
 	Namespace string `yaml:"namespace"`
 
 	Embedder string `yaml:"embedder"`
 	Reranker string `yaml:"reranker"`
+
+	HNSW    *indexHNSWConfig    `yaml:"hnsw"`
+	IVFFlat *indexIVFFlatConfig `yaml:"ivfflat"`
+}
+
+type indexHNSWConfig struct {
+	M int `yaml:"m"`
+
+	EfConstruction int `yaml:"ef_construction"`
+	EfSearch       int `yaml:"ef_search"`
+}
+
+type indexIVFFlatConfig struct {
+	Lists  int `yaml:"lists"`
+	Probes int `yaml:"probes"`
 }
 
 type indexContext struct {
@@ -98,6 +157,14 @@ func (cfg *Config) registerIndexes(f *configFile) error {
 			return err
 		}
 
+		if context.Reranker != nil {
+			index, err = reranker.New(index, context.Reranker)
+
+			if err != nil {
+				return err
+			}
+		}
+
 		if _, ok := index.(otel.Index); !ok {
 			index = otel.NewIndex(config.Type, id, index)
 		}
@@ -122,6 +189,12 @@ func createIndex(cfg indexConfig, context indexContext) (index.Provider, error)
 	case "memory":
 		return memoryIndex(cfg, context)
 
+	case "pgvector":
+		return pgvectorIndex(cfg, context)
+
+	case "postgrest":
+		return postgrestIndex(cfg, context)
+
 	case "qdrant":
 		return qdrantIndex(cfg, context)
 
@@ -149,10 +222,6 @@ func chromaIndex(cfg indexConfig, context indexContext) (index.Provider, error)
 		options = append(options, chroma.WithEmbedder(context.Embedder))
 	}
 
-	if context.Reranker != nil {
-		options = append(options, chroma.WithReranker(context.Reranker))
-	}
-
 	return chroma.New(cfg.URL, cfg.Namespace, options...)
 }
 
@@ -165,15 +234,47 @@ func elasticsearchIndex(cfg indexConfig) (index.Provider, error) {
 func memoryIndex(cfg indexConfig, context indexContext) (index.Provider, error) {
 	var options []memory.Option
 
+	if cfg.Path != "" {
+		options = append(options, memory.WithPath(cfg.Path))
+	}
+
+	if cfg.HNSW != nil {
+		options = append(options, memory.WithHNSW(cfg.HNSW.M, cfg.HNSW.EfConstruction, cfg.HNSW.EfSearch))
+	}
+
 	if context.Embedder != nil {
 		options = append(options, memory.WithEmbedder(context.Embedder))
 	}
 
-	if context.Reranker != nil {
-		options = append(options, memory.WithReranker(context.Reranker))
+	return memory.New(options...)
+}
+
+func pgvectorIndex(cfg indexConfig, context indexContext) (index.Provider, error) {
+	var options []pgvector.Option
+
+	if cfg.HNSW != nil {
+		options = append(options, pgvector.WithHNSW(cfg.HNSW.M, cfg.HNSW.EfConstruction, cfg.HNSW.EfSearch))
 	}
 
-	return memory.New(options...)
+	if cfg.IVFFlat != nil {
+		options = append(options, pgvector.WithIVFFlat(cfg.IVFFlat.Lists, cfg.IVFFlat.Probes))
+	}
+
+	if context.Embedder != nil {
+		options = append(options, pgvector.WithEmbedder(context.Embedder))
+	}
+
+	return pgvector.New(cfg.URL, cfg.Namespace, options...)
+}
+
+func postgrestIndex(cfg indexConfig, context indexContext) (index.Provider, error) {
+	var options []postgrest.Option
+
+	if context.Embedder != nil {
+		options = append(options, postgrest.WithEmbedder(context.Embedder))
+	}
+
+	return postgrest.New(cfg.URL, cfg.Namespace, options...)
 }
 
 func qdrantIndex(cfg indexConfig, context indexContext) (index.Provider, error) {
@@ -183,10 +284,6 @@ func qdrantIndex(cfg indexConfig, context indexContext) (index.Provider, error)
 		options = append(options, qdrant.WithEmbedder(context.Embedder))
 	}
 
-	if context.Reranker != nil {
-		options = append(options, qdrant.WithReranker(context.Reranker))
-	}
-
 	return qdrant.New(cfg.URL, cfg.Namespace, options...)
 }
 
@@ -197,10 +294,6 @@ func weaviateIndex(cfg indexConfig, context indexContext) (index.Provider, error
 		options = append(options, weaviate.WithEmbedder(context.Embedder))
 	}
 
-	if context.Reranker != nil {
-		options = append(options, weaviate.WithReranker(context.Reranker))
-	}
-
 	return weaviate.New(cfg.URL, cfg.Namespace, options...)
 }
 
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+	"github.com/adrianliechti/wingman/pkg/index/reranker"
+	"github.com/adrianliechti/wingman/pkg/otel"
+	"github.com/adrianliechti/wingman/pkg/provider"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloseIndexes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.json")
+
+	i, err := memoryIndex(indexConfig{Path: path}, indexContext{Embedder: &staticEmbedder{}})
+	require.NoError(t, err)
+
+	// the wrappers of the configured indexes forward the close to the memory index
+	i, err = reranker.New(i, &staticReranker{})
+	require.NoError(t, err)
+
+	i = otel.NewIndex("memory", "docs", i)
+
+	cfg := &Config{}
+	cfg.RegisterIndex("docs", i)
+
+	err = i.Index(context.Background(), index.Document{ID: "1", Content: "first"})
+	require.NoError(t, err)
+
+	require.NoError(t, cfg.Close())
+
+	_, err = os.Stat(path)
+	require.NoError(t, err)
+}
+
+type staticEmbedder struct{}
+
+func (e *staticEmbedder) Embed(ctx context.Context, texts []string) (*provider.Embedding, error) {
+	result := &provider.Embedding{}
+
+	for range texts {
+		result.Embeddings = append(result.Embeddings, []float32{1, 0})
+	}
+
+	return result, nil
+}
+
+type staticReranker struct{}
+
+func (r *staticReranker) Rerank(ctx context.Context, query string, texts []string, options *provider.RerankOptions) ([]provider.Ranking, error) {
+	return nil, nil
+}
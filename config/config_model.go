@@ -10,18 +10,40 @@ import (
 	"golang.org/x/time/rate"
 )
 
-func (cfg *Config) RegisterModel(id string) {
+// RegisterModel adds a model to the model list; metadata of an already registered model is only completed, never replaced
+func (cfg *Config) RegisterModel(model provider.Model) {
 	if cfg.models == nil {
 		cfg.models = make(map[string]provider.Model)
 	}
 
-	if _, ok := cfg.models[id]; ok {
+	m, ok := cfg.models[model.ID]
+
+	if !ok {
+		cfg.models[model.ID] = model
 		return
 	}
 
-	cfg.models[id] = provider.Model{
-		ID: id,
+	if m.Type == "" {
+		m.Type = model.Type
+	}
+
+	if m.Name == "" {
+		m.Name = model.Name
+	}
+
+	if m.Description == "" {
+		m.Description = model.Description
 	}
+
+	if m.Provider == "" {
+		m.Provider = model.Provider
+	}
+
+	if m.Capabilities == nil {
+		m.Capabilities = model.Capabilities
+	}
+
+	cfg.models[model.ID] = m
 }
 
 func (cfg *Config) Models() []provider.Model {
@@ -46,16 +68,17 @@ func (cfg *Config) Model(id string) (*provider.Model, error) {
 	return nil, errors.New("model not found: " + id)
 }
 
-type ModelType string
+type ModelType = provider.ModelType
 
 const (
-	ModelTypeAuto        ModelType = ""
-	ModelTypeCompleter   ModelType = "completer"
-	ModelTypeEmbedder    ModelType = "embedder"
-	ModelTypeRenderer    ModelType = "renderer"
-	ModelTypeReranker    ModelType = "reranker"
-	ModelTypeSynthesizer ModelType = "synthesizer"
-	ModelTypeTranscriber ModelType = "transcriber"
+	ModelTypeAuto        = provider.ModelTypeAuto
+	ModelTypeCompleter   = provider.ModelTypeCompleter
+	ModelTypeEmbedder    = provider.ModelTypeEmbedder
+	ModelTypeModerator   = provider.ModelTypeModerator
+	ModelTypeRenderer    = provider.ModelTypeRenderer
+	ModelTypeReranker    = provider.ModelTypeReranker
+	ModelTypeSynthesizer = provider.ModelTypeSynthesizer
+	ModelTypeTranscriber = provider.ModelTypeTranscriber
 )
 
 type modelConfig struct {
@@ -66,6 +89,11 @@ type modelConfig struct {
 	Name        string `yaml:"name"`
 	Description string `yaml:"description"`
 
+	Capabilities []provider.Capability `yaml:"capabilities"`
+
+	// Categories are the classes a completer used as moderator rates
+	Categories []string `yaml:"categories"`
+
 	Limit *int `yaml:"limit"`
 }
 
@@ -77,9 +105,56 @@ type modelContext struct {
 	Name        string
 	Description string
 
+	Capabilities []provider.Capability
+
+	Categories []string
+
 	Limiter *rate.Limiter
 }
 
+// detectCapabilities returns the capabilities a provider type supports for every model of a type.
+// Tools, vision and json_schema depend on the model, so they are only reported if set in the model config.
+func detectCapabilities(providerType string, modelType ModelType) []provider.Capability {
+	if modelType != ModelTypeCompleter {
+		return []provider.Capability{}
+	}
+
+	switch strings.ToLower(providerType) {
+	case "anthropic", "azure", "bedrock", "cohere", "github", "google", "groq", "huggingface", "llama", "mistral", "mistralrs", "ollama", "openai", "xai":
+		return []provider.Capability{
+			provider.CapabilityStreaming,
+		}
+	}
+
+	return []provider.Capability{}
+}
+
+// commonCapabilities returns the capabilities shared by all given models
+func commonCapabilities(models []provider.Model) []provider.Capability {
+	result := []provider.Capability{}
+
+	if len(models) == 0 {
+		return result
+	}
+
+	for _, c := range models[0].Capabilities {
+		shared := true
+
+		for _, m := range models[1:] {
+			if !m.HasCapability(c) {
+				shared = false
+				break
+			}
+		}
+
+		if shared {
+			result = append(result, c)
+		}
+	}
+
+	return result
+}
+
 func DetectModelType(id string) ModelType {
 	completers := []string{
 		"aya",
@@ -125,6 +200,10 @@ func DetectModelType(id string) ModelType {
 		"reranker",
 	}
 
+	moderators := []string{
+		"moderation",
+	}
+
 	renderers := []string{
 		"dall-e",
 		"flux-dev",
@@ -148,6 +227,12 @@ func DetectModelType(id string) ModelType {
 		"whisper",
 	}
 
+	for _, val := range moderators {
+		if strings.Contains(strings.ToLower(id), strings.ToLower(val)) {
+			return ModelTypeModerator
+		}
+	}
+
 	for _, val := range synthesizers {
 		if strings.Contains(strings.ToLower(id), strings.ToLower(val)) {
 			return ModelTypeSynthesizer
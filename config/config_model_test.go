@@ -0,0 +1,17 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectCapabilities(t *testing.T) {
+	require.Equal(t, []provider.Capability{provider.CapabilityStreaming}, detectCapabilities("openai", ModelTypeCompleter))
+	require.Equal(t, []provider.Capability{provider.CapabilityStreaming}, detectCapabilities("Ollama", ModelTypeCompleter))
+
+	require.Empty(t, detectCapabilities("openai", ModelTypeEmbedder))
+	require.Empty(t, detectCapabilities("custom", ModelTypeCompleter))
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/provider/openai"
+
+	moderator "github.com/adrianliechti/wingman/pkg/provider/adapter/moderator"
+)
+
+func (cfg *Config) RegisterModerator(id string, p provider.Moderator) {
+	cfg.RegisterModel(provider.Model{ID: id, Type: ModelTypeModerator})
+
+	if cfg.moderator == nil {
+		cfg.moderator = make(map[string]provider.Moderator)
+	}
+
+	if _, ok := cfg.moderator[""]; !ok {
+		cfg.moderator[""] = p
+	}
+
+	cfg.moderator[id] = p
+}
+
+func (cfg *Config) Moderator(id string) (provider.Moderator, error) {
+	if cfg.moderator != nil {
+		if m, ok := cfg.moderator[id]; ok {
+			return m, nil
+		}
+	}
+
+	return nil, errors.New("moderator not found: " + id)
+}
+
+func createModerator(cfg providerConfig, model modelContext) (provider.Moderator, error) {
+	switch strings.ToLower(cfg.Type) {
+	case "openai":
+		// dedicated moderation models, other models classify through the completer adapter
+		if strings.Contains(strings.ToLower(model.ID), "moderation") {
+			return openaiModerator(cfg, model)
+		}
+	}
+
+	completer, err := createCompleter(cfg, model)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid moderator: %w", err)
+	}
+
+	return moderator.FromCompleter(completer, model.Categories), nil
+}
+
+func openaiModerator(cfg providerConfig, model modelContext) (provider.Moderator, error) {
+	var options []openai.Option
+
+	if cfg.Token != "" {
+		options = append(options, openai.WithToken(cfg.Token))
+	}
+
+	return openai.NewModerator(cfg.URL, model.ID, options...)
+}
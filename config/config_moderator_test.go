@@ -0,0 +1,13 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateModeratorError(t *testing.T) {
+	_, err := createModerator(providerConfig{Type: "unknown"}, modelContext{ID: "test"})
+
+	require.ErrorContains(t, err, "invalid completer type: unknown")
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/adrianliechti/wingman/pkg/limiter"
 	"github.com/adrianliechti/wingman/pkg/otel"
+	"github.com/adrianliechti/wingman/pkg/provider"
 
 	reranker "github.com/adrianliechti/wingman/pkg/provider/adapter/reranker"
 	summarizer "github.com/adrianliechti/wingman/pkg/summarizer/adapter"
@@ -55,6 +56,10 @@ func (cfg *Config) registerProviders(f *configFile) error {
 				m.Type = DetectModelType(id)
 			}
 
+			if m.Capabilities == nil {
+				m.Capabilities = detectCapabilities(p.Type, m.Type)
+			}
+
 			limit := m.Limit
 
 			if limit == nil {
@@ -69,9 +74,24 @@ func (cfg *Config) registerProviders(f *configFile) error {
 				Name:        m.Name,
 				Description: m.Description,
 
+				Capabilities: m.Capabilities,
+
+				Categories: m.Categories,
+
 				Limiter: createLimiter(limit),
 			}
 
+			cfg.RegisterModel(provider.Model{
+				ID:   id,
+				Type: context.Type,
+
+				Name:        context.Name,
+				Description: context.Description,
+
+				Provider:     p.Type,
+				Capabilities: context.Capabilities,
+			})
+
 			switch context.Type {
 			case ModelTypeCompleter:
 				completer, err := createCompleter(p, context)
@@ -126,6 +146,23 @@ func (cfg *Config) registerProviders(f *configFile) error {
 
 				cfg.RegisterReranker(id, reranker)
 
+			case ModelTypeModerator:
+				moderator, err := createModerator(p, context)
+
+				if err != nil {
+					return err
+				}
+
+				if _, ok := moderator.(limiter.Moderator); !ok {
+					moderator = limiter.NewModerator(context.Limiter, moderator)
+				}
+
+				if _, ok := moderator.(otel.Moderator); !ok {
+					moderator = otel.NewModerator(p.Type, id, moderator)
+				}
+
+				cfg.RegisterModerator(id, moderator)
+
 			case ModelTypeRenderer:
 				renderer, err := createRenderer(p, context)
 
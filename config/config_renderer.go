@@ -12,7 +12,7 @@ import (
 )
 
 func (cfg *Config) RegisterRenderer(id string, p provider.Renderer) {
-	cfg.RegisterModel(id)
+	cfg.RegisterModel(provider.Model{ID: id, Type: ModelTypeRenderer})
 
 	if cfg.renderer == nil {
 		cfg.renderer = make(map[string]provider.Renderer)
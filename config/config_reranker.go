@@ -5,12 +5,13 @@ import (
 	"strings"
 
 	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/provider/custom"
 	"github.com/adrianliechti/wingman/pkg/provider/huggingface"
 	"github.com/adrianliechti/wingman/pkg/provider/jina"
 )
 
 func (cfg *Config) RegisterReranker(id string, p provider.Reranker) {
-	cfg.RegisterModel(id)
+	cfg.RegisterModel(provider.Model{ID: id, Type: ModelTypeReranker})
 
 	if cfg.reranker == nil {
 		cfg.reranker = make(map[string]provider.Reranker)
@@ -35,6 +36,9 @@ func (cfg *Config) Reranker(id string) (provider.Reranker, error) {
 
 func createReranker(cfg providerConfig, model modelContext) (provider.Reranker, error) {
 	switch strings.ToLower(cfg.Type) {
+	case "custom":
+		return customReranker(cfg, model)
+
 	case "huggingface":
 		return huggingfaceReranker(cfg, model)
 
@@ -46,6 +50,12 @@ func createReranker(cfg providerConfig, model modelContext) (provider.Reranker,
 	}
 }
 
+func customReranker(cfg providerConfig, model modelContext) (provider.Reranker, error) {
+	var options []custom.Option
+
+	return custom.NewReranker(cfg.URL, model.ID, options...)
+}
+
 func huggingfaceReranker(cfg providerConfig, model modelContext) (provider.Reranker, error) {
 	var options []huggingface.Option
 
@@ -3,20 +3,39 @@ package config
 import (
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/adrianliechti/wingman/pkg/otel"
 	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/router/failover"
+	"github.com/adrianliechti/wingman/pkg/router/latency"
 	"github.com/adrianliechti/wingman/pkg/router/roundrobin"
+	"github.com/adrianliechti/wingman/pkg/router/weighted"
+
+	"gopkg.in/yaml.v3"
 )
 
 type routerConfig struct {
 	Type string `yaml:"type"`
 
-	Models []string `yaml:"models"`
+	Models yaml.Node `yaml:"models"`
+
+	Cooldown *time.Duration `yaml:"cooldown"`
+}
+
+type routerModelConfig struct {
+	Weight *int `yaml:"weight"`
 }
 
 type routerContext struct {
-	Completers []provider.Completer
+	Weights []int
+
+	Completers   []provider.Completer
+	Embedders    []provider.Embedder
+	Renderers    []provider.Renderer
+	Rerankers    []provider.Reranker
+	Synthesizers []provider.Synthesizer
+	Transcribers []provider.Transcriber
 }
 
 func (cfg *Config) registerRouters(f *configFile) error {
@@ -35,46 +54,446 @@ func (cfg *Config) registerRouters(f *configFile) error {
 			continue
 		}
 
-		context := routerContext{}
+		models, weights, err := parseRouterModels(config.Models)
 
-		for _, m := range config.Models {
-			completer, err := cfg.Completer(m)
+		if err != nil {
+			return err
+		}
 
-			if err != nil {
-				return err
-			}
+		context := routerContext{
+			Weights: weights,
 
-			context.Completers = append(context.Completers, completer)
+			Completers:   resolveModels(models, cfg.Completer),
+			Embedders:    resolveModels(models, cfg.Embedder),
+			Renderers:    resolveModels(models, cfg.Renderer),
+			Rerankers:    resolveModels(models, cfg.Reranker),
+			Synthesizers: resolveModels(models, cfg.Synthesizer),
+			Transcribers: resolveModels(models, cfg.Transcriber),
 		}
 
-		router, err := createRouter(config, context)
+		routers, err := createRouter(config, context)
 
 		if err != nil {
 			return err
 		}
 
-		if completer, ok := router.(provider.Completer); ok {
-			if _, ok := completer.(otel.Completer); !ok {
-				completer = otel.NewCompleter(config.Type, id, completer)
+		if len(routers) == 0 {
+			return errors.New("no matching models for router: " + id)
+		}
+
+		var members []provider.Model
+
+		for _, m := range models {
+			if model, err := cfg.Model(m); err == nil {
+				members = append(members, *model)
 			}
+		}
+
+		capabilities := commonCapabilities(members)
 
-			cfg.RegisterCompleter(id, completer)
+		for _, router := range routers {
+			switch router := router.(type) {
+			case provider.Completer:
+				if _, ok := router.(otel.Completer); !ok {
+					router = otel.NewCompleter(config.Type, id, router)
+				}
+
+				cfg.RegisterModel(provider.Model{ID: id, Type: ModelTypeCompleter, Provider: config.Type, Capabilities: capabilities})
+				cfg.RegisterCompleter(id, router)
+
+			case provider.Embedder:
+				if _, ok := router.(otel.Embedder); !ok {
+					router = otel.NewEmbedder(config.Type, id, router)
+				}
+
+				cfg.RegisterModel(provider.Model{ID: id, Type: ModelTypeEmbedder, Provider: config.Type, Capabilities: capabilities})
+				cfg.RegisterEmbedder(id, router)
+
+			case provider.Renderer:
+				if _, ok := router.(otel.Renderer); !ok {
+					router = otel.NewRenderer(config.Type, id, router)
+				}
+
+				cfg.RegisterModel(provider.Model{ID: id, Type: ModelTypeRenderer, Provider: config.Type, Capabilities: capabilities})
+				cfg.RegisterRenderer(id, router)
+
+			case provider.Reranker:
+				if _, ok := router.(otel.Reranker); !ok {
+					router = otel.NewReranker(config.Type, id, router)
+				}
+
+				cfg.RegisterModel(provider.Model{ID: id, Type: ModelTypeReranker, Provider: config.Type, Capabilities: capabilities})
+				cfg.RegisterReranker(id, router)
+
+			case provider.Synthesizer:
+				if _, ok := router.(otel.Synthesizer); !ok {
+					router = otel.NewSynthesizer(config.Type, id, router)
+				}
+
+				cfg.RegisterModel(provider.Model{ID: id, Type: ModelTypeSynthesizer, Provider: config.Type, Capabilities: capabilities})
+				cfg.RegisterSynthesizer(id, router)
+
+			case provider.Transcriber:
+				if _, ok := router.(otel.Transcriber); !ok {
+					router = otel.NewTranscriber(config.Type, id, router)
+				}
+
+				cfg.RegisterModel(provider.Model{ID: id, Type: ModelTypeTranscriber, Provider: config.Type, Capabilities: capabilities})
+				cfg.RegisterTranscriber(id, router)
+			}
 		}
 	}
 
 	return nil
 }
 
-func createRouter(cfg routerConfig, context routerContext) (any, error) {
+// parseRouterModels accepts either a list of model ids or a map of model ids with settings
+func parseRouterModels(node yaml.Node) ([]string, []int, error) {
+	configs := map[string]routerModelConfig{}
+
+	if err := node.Decode(&configs); err != nil {
+		var ids []string
+
+		if err := node.Decode(&ids); err != nil {
+			return nil, nil, err
+		}
+
+		for _, id := range ids {
+			configs[id] = routerModelConfig{}
+		}
+	}
+
+	var models []string
+	var weights []int
+
+	for _, n := range node.Content {
+		id := n.Value
+
+		if id == "" {
+			continue
+		}
+
+		m, ok := configs[id]
+
+		if !ok {
+			continue
+		}
+
+		weight := 1
+
+		if m.Weight != nil {
+			weight = *m.Weight
+		}
+
+		models = append(models, id)
+		weights = append(weights, weight)
+	}
+
+	return models, weights, nil
+}
+
+// resolveModels returns the providers of one kind, or nil unless every model provides it
+func resolveModels[T any](models []string, lookup func(id string) (T, error)) []T {
+	var result []T
+
+	for _, m := range models {
+		p, err := lookup(m)
+
+		if err != nil {
+			return nil
+		}
+
+		result = append(result, p)
+	}
+
+	return result
+}
+
+func createRouter(cfg routerConfig, context routerContext) ([]any, error) {
 	switch strings.ToLower(cfg.Type) {
+	case "failover":
+		return failoverRouter(cfg, context)
+
+	case "latency":
+		return latencyRouter(cfg, context)
+
 	case "roundrobin":
 		return roundrobinRouter(cfg, context)
 
+	case "weighted":
+		return weightedRouter(cfg, context)
+
 	default:
 		return nil, errors.New("invalid router type: " + cfg.Type)
 	}
 }
 
-func roundrobinRouter(cfg routerConfig, context routerContext) (any, error) {
-	return roundrobin.NewCompleter(context.Completers...)
+func failoverRouter(cfg routerConfig, context routerContext) ([]any, error) {
+	var options []failover.Option
+
+	if cfg.Cooldown != nil {
+		options = append(options, failover.WithCooldown(*cfg.Cooldown))
+	}
+
+	var result []any
+
+	if len(context.Completers) > 0 {
+		r, err := failover.NewCompleter(context.Completers, options...)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, r)
+	}
+
+	if len(context.Embedders) > 0 {
+		r, err := failover.NewEmbedder(context.Embedders, options...)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, r)
+	}
+
+	if len(context.Renderers) > 0 {
+		r, err := failover.NewRenderer(context.Renderers, options...)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, r)
+	}
+
+	if len(context.Rerankers) > 0 {
+		r, err := failover.NewReranker(context.Rerankers, options...)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, r)
+	}
+
+	if len(context.Synthesizers) > 0 {
+		r, err := failover.NewSynthesizer(context.Synthesizers, options...)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, r)
+	}
+
+	if len(context.Transcribers) > 0 {
+		r, err := failover.NewTranscriber(context.Transcribers, options...)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, r)
+	}
+
+	return result, nil
+}
+
+func latencyRouter(cfg routerConfig, context routerContext) ([]any, error) {
+	var result []any
+
+	if len(context.Completers) > 0 {
+		r, err := latency.NewCompleter(context.Completers)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, r)
+	}
+
+	if len(context.Embedders) > 0 {
+		r, err := latency.NewEmbedder(context.Embedders)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, r)
+	}
+
+	if len(context.Renderers) > 0 {
+		r, err := latency.NewRenderer(context.Renderers)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, r)
+	}
+
+	if len(context.Rerankers) > 0 {
+		r, err := latency.NewReranker(context.Rerankers)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, r)
+	}
+
+	if len(context.Synthesizers) > 0 {
+		r, err := latency.NewSynthesizer(context.Synthesizers)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, r)
+	}
+
+	if len(context.Transcribers) > 0 {
+		r, err := latency.NewTranscriber(context.Transcribers)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, r)
+	}
+
+	return result, nil
+}
+
+func roundrobinRouter(cfg routerConfig, context routerContext) ([]any, error) {
+	var result []any
+
+	if len(context.Completers) > 0 {
+		r, err := roundrobin.NewCompleter(context.Completers...)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, r)
+	}
+
+	if len(context.Embedders) > 0 {
+		r, err := roundrobin.NewEmbedder(context.Embedders...)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, r)
+	}
+
+	if len(context.Renderers) > 0 {
+		r, err := roundrobin.NewRenderer(context.Renderers...)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, r)
+	}
+
+	if len(context.Rerankers) > 0 {
+		r, err := roundrobin.NewReranker(context.Rerankers...)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, r)
+	}
+
+	if len(context.Synthesizers) > 0 {
+		r, err := roundrobin.NewSynthesizer(context.Synthesizers...)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, r)
+	}
+
+	if len(context.Transcribers) > 0 {
+		r, err := roundrobin.NewTranscriber(context.Transcribers...)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, r)
+	}
+
+	return result, nil
+}
+
+func weightedRouter(cfg routerConfig, context routerContext) ([]any, error) {
+	var result []any
+
+	if len(context.Completers) > 0 {
+		r, err := weighted.NewCompleter(context.Completers, context.Weights)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, r)
+	}
+
+	if len(context.Embedders) > 0 {
+		r, err := weighted.NewEmbedder(context.Embedders, context.Weights)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, r)
+	}
+
+	if len(context.Renderers) > 0 {
+		r, err := weighted.NewRenderer(context.Renderers, context.Weights)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, r)
+	}
+
+	if len(context.Rerankers) > 0 {
+		r, err := weighted.NewReranker(context.Rerankers, context.Weights)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, r)
+	}
+
+	if len(context.Synthesizers) > 0 {
+		r, err := weighted.NewSynthesizer(context.Synthesizers, context.Weights)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, r)
+	}
+
+	if len(context.Transcribers) > 0 {
+		r, err := weighted.NewTranscriber(context.Transcribers, context.Weights)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, r)
+	}
+
+	return result, nil
 }
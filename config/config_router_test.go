@@ -0,0 +1,40 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseRouterModels(t *testing.T) {
+	for _, test := range []struct {
+		input string
+
+		models  []string
+		weights []int
+	}{
+		{
+			input: "[gpt-4o, gpt-4o-mini]",
+
+			models:  []string{"gpt-4o", "gpt-4o-mini"},
+			weights: []int{1, 1},
+		},
+		{
+			input: "{llama: {weight: 3}, azure: {weight: 1}, mistral: {}}",
+
+			models:  []string{"llama", "azure", "mistral"},
+			weights: []int{3, 1, 1},
+		},
+	} {
+		var node yaml.Node
+		require.NoError(t, yaml.Unmarshal([]byte(test.input), &node))
+
+		models, weights, err := parseRouterModels(*node.Content[0])
+		require.NoError(t, err)
+
+		require.Equal(t, test.models, models)
+		require.Equal(t, test.weights, weights)
+	}
+}
@@ -5,12 +5,13 @@ import (
 	"strings"
 
 	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/provider/custom"
 	"github.com/adrianliechti/wingman/pkg/provider/elevenlabs"
 	"github.com/adrianliechti/wingman/pkg/provider/openai"
 )
 
 func (cfg *Config) RegisterSynthesizer(id string, p provider.Synthesizer) {
-	cfg.RegisterModel(id)
+	cfg.RegisterModel(provider.Model{ID: id, Type: ModelTypeSynthesizer})
 
 	if cfg.synthesizer == nil {
 		cfg.synthesizer = make(map[string]provider.Synthesizer)
@@ -35,6 +36,9 @@ func (cfg *Config) Synthesizer(id string) (provider.Synthesizer, error) {
 
 func createSynthesizer(cfg providerConfig, model modelContext) (provider.Synthesizer, error) {
 	switch strings.ToLower(cfg.Type) {
+	case "custom":
+		return customSynthesizer(cfg, model)
+
 	case "elevenlabs":
 		return elevenlabsSynthesizer(cfg, model)
 
@@ -46,6 +50,12 @@ func createSynthesizer(cfg providerConfig, model modelContext) (provider.Synthes
 	}
 }
 
+func customSynthesizer(cfg providerConfig, model modelContext) (provider.Synthesizer, error) {
+	var options []custom.Option
+
+	return custom.NewSynthesizer(cfg.URL, model.ID, options...)
+}
+
 func elevenlabsSynthesizer(cfg providerConfig, model modelContext) (provider.Synthesizer, error) {
 	var options []elevenlabs.Option
 
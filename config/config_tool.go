@@ -2,6 +2,7 @@ package config
 
 import (
 	"errors"
+	"slices"
 	"strings"
 
 	"github.com/adrianliechti/wingman/pkg/extractor"
@@ -12,6 +13,8 @@ import (
 	"github.com/adrianliechti/wingman/pkg/tool/custom"
 	"github.com/adrianliechti/wingman/pkg/tool/draw"
 	"github.com/adrianliechti/wingman/pkg/tool/genaitoolbox"
+	"github.com/adrianliechti/wingman/pkg/tool/mcp"
+	"github.com/adrianliechti/wingman/pkg/tool/openapi"
 	"github.com/adrianliechti/wingman/pkg/tool/retriever"
 	"github.com/adrianliechti/wingman/pkg/tool/search"
 	"github.com/adrianliechti/wingman/pkg/tool/speak"
@@ -45,12 +48,33 @@ func (cfg *Config) Tool(id string) (tool.Provider, error) {
 	return nil, errors.New("tool not found: " + id)
 }
 
+// Tools returns the ids of the configured tools
+func (cfg *Config) Tools() []string {
+	var result []string
+
+	for id := range cfg.tools {
+		result = append(result, id)
+	}
+
+	slices.Sort(result)
+
+	return result
+}
+
 type toolConfig struct {
 	Type string `yaml:"type"`
 
 	URL   string `yaml:"url"`
 	Token string `yaml:"token"`
 
+	Spec       string            `yaml:"spec"`
+	Headers    map[string]string `yaml:"headers"`
+	Operations []string          `yaml:"operations"`
+
+	Command string            `yaml:"command"`
+	Args    []string          `yaml:"args"`
+	Env     map[string]string `yaml:"env"`
+
 	Model    string `yaml:"model"`
 	Provider string `yaml:"provider"`
 
@@ -167,6 +191,12 @@ func createTool(cfg toolConfig, context toolContext) (tool.Provider, error) {
 	case "genaitoolbox":
 		return genaitoolboxTool(cfg, context)
 
+	case "mcp":
+		return mcpTool(cfg, context)
+
+	case "openapi":
+		return openapiTool(cfg, context)
+
 	case "searxng":
 		return searxngTool(cfg, context)
 
@@ -250,6 +280,46 @@ func genaitoolboxTool(cfg toolConfig, context toolContext) (tool.Provider, error
 	return genaitoolbox.New(cfg.URL, options...)
 }
 
+func mcpTool(cfg toolConfig, context toolContext) (tool.Provider, error) {
+	var options []mcp.Option
+
+	if cfg.Token != "" {
+		options = append(options, mcp.WithToken(cfg.Token))
+	}
+
+	if cfg.Command != "" {
+		options = append(options, mcp.WithCommand(cfg.Command, cfg.Args...))
+	}
+
+	if len(cfg.Env) > 0 {
+		options = append(options, mcp.WithEnv(cfg.Env))
+	}
+
+	return mcp.New(cfg.URL, options...)
+}
+
+func openapiTool(cfg toolConfig, context toolContext) (tool.Provider, error) {
+	var options []openapi.Option
+
+	if cfg.URL != "" {
+		options = append(options, openapi.WithURL(cfg.URL))
+	}
+
+	if cfg.Token != "" {
+		options = append(options, openapi.WithToken(cfg.Token))
+	}
+
+	if len(cfg.Headers) > 0 {
+		options = append(options, openapi.WithHeaders(cfg.Headers))
+	}
+
+	if len(cfg.Operations) > 0 {
+		options = append(options, openapi.WithOperations(cfg.Operations...))
+	}
+
+	return openapi.New(cfg.Spec, options...)
+}
+
 func searxngTool(cfg toolConfig, context toolContext) (tool.Provider, error) {
 	index, err := searxng.New(cfg.Token)
 
@@ -5,13 +5,14 @@ import (
 	"strings"
 
 	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/provider/custom"
 	"github.com/adrianliechti/wingman/pkg/provider/groq"
 	"github.com/adrianliechti/wingman/pkg/provider/openai"
 	"github.com/adrianliechti/wingman/pkg/provider/whisper"
 )
 
 func (cfg *Config) RegisterTranscriber(id string, p provider.Transcriber) {
-	cfg.RegisterModel(id)
+	cfg.RegisterModel(provider.Model{ID: id, Type: ModelTypeTranscriber})
 
 	if cfg.transcriber == nil {
 		cfg.transcriber = make(map[string]provider.Transcriber)
@@ -36,6 +37,9 @@ func (cfg *Config) Transcriber(id string) (provider.Transcriber, error) {
 
 func createTranscriber(cfg providerConfig, model modelContext) (provider.Transcriber, error) {
 	switch strings.ToLower(cfg.Type) {
+	case "custom":
+		return customTranscriber(cfg, model)
+
 	case "groq":
 		return groqTranscriber(cfg, model)
 
@@ -50,6 +54,12 @@ func createTranscriber(cfg providerConfig, model modelContext) (provider.Transcr
 	}
 }
 
+func customTranscriber(cfg providerConfig, model modelContext) (provider.Transcriber, error) {
+	var options []custom.Option
+
+	return custom.NewTranscriber(cfg.URL, model.ID, options...)
+}
+
 func groqTranscriber(cfg providerConfig, model modelContext) (provider.Transcriber, error) {
 	var options []groq.Option
 
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/adrianliechti/wingman/pkg/provider/custom"
+
+	"google.golang.org/grpc"
+)
+
+func main() {
+	l, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", 7777))
+
+	if err != nil {
+		panic(err)
+	}
+
+	s := grpc.NewServer()
+	custom.RegisterProviderServer(s, newServer())
+	s.Serve(l)
+}
+
+type server struct {
+	custom.UnsafeProviderServer
+}
+
+func newServer() *server {
+	return &server{}
+}
+
+// Complete streams the last user message back word by word
+func (s *server) Complete(r *custom.CompleteRequest, stream grpc.ServerStreamingServer[custom.Completion]) error {
+	var input string
+
+	for _, m := range r.Messages {
+		if m.Role == "user" {
+			input = m.Content
+		}
+	}
+
+	println("> " + input)
+
+	for i, word := range strings.Fields(input) {
+		if i > 0 {
+			word = " " + word
+		}
+
+		if err := stream.Send(&custom.Completion{
+			Id: "echo",
+
+			Message: &custom.Message{
+				Role:    "assistant",
+				Content: word,
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	return stream.Send(&custom.Completion{
+		Id:     "echo",
+		Reason: "stop",
+
+		Usage: &custom.Usage{
+			InputTokens:  int32(len(strings.Fields(input))),
+			OutputTokens: int32(len(strings.Fields(input))),
+		},
+	})
+}
+
+// Embed hashes the words of each text into a normalized vector
+func (s *server) Embed(ctx context.Context, r *custom.EmbedRequest) (*custom.Embeddings, error) {
+	result := &custom.Embeddings{}
+
+	for _, text := range r.Texts {
+		result.Embeddings = append(result.Embeddings, &custom.Embedding{
+			Data: embed(text),
+		})
+	}
+
+	return result, nil
+}
+
+// Rerank orders the texts by the similarity of their embeddings to the query
+func (s *server) Rerank(ctx context.Context, r *custom.RerankRequest) (*custom.Rankings, error) {
+	query := embed(r.Query)
+
+	var rankings []*custom.Ranking
+
+	for _, text := range r.Texts {
+		var score float64
+
+		for i, v := range embed(text) {
+			score += float64(v * query[i])
+		}
+
+		rankings = append(rankings, &custom.Ranking{
+			Text:  text,
+			Score: score,
+		})
+	}
+
+	slices.SortStableFunc(rankings, func(a, b *custom.Ranking) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+
+	if r.Limit != nil && int(*r.Limit) < len(rankings) {
+		rankings = rankings[:*r.Limit]
+	}
+
+	return &custom.Rankings{
+		Rankings: rankings,
+	}, nil
+}
+
+// Transcribe receives the audio in chunks and describes it
+func (s *server) Transcribe(stream grpc.ClientStreamingServer[custom.TranscribeRequest, custom.Transcription]) error {
+	var name string
+	var size int
+
+	for {
+		r, err := stream.Recv()
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if name == "" {
+			name = r.Name
+		}
+
+		size += len(r.Data)
+	}
+
+	return stream.SendAndClose(&custom.Transcription{
+		Id:   "transcription",
+		Text: fmt.Sprintf("received %d bytes of audio from %s", size, name),
+	})
+}
+
+// Synthesize streams a wav tone with a beep per word
+func (s *server) Synthesize(r *custom.SynthesizeRequest, stream grpc.ServerStreamingServer[custom.Synthesis]) error {
+	const rate = 24000
+
+	words := len(strings.Fields(r.Input))
+
+	var samples []int16
+
+	for range words {
+		for i := range rate / 5 {
+			samples = append(samples, int16(math.Sin(2*math.Pi*440*float64(i)/rate)*8000))
+		}
+
+		samples = append(samples, make([]int16, rate/10)...)
+	}
+
+	var buf bytes.Buffer
+
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(36+len(samples)*2))
+	buf.WriteString("WAVEfmt ")
+	binary.Write(&buf, binary.LittleEndian, []any{uint32(16), uint16(1), uint16(1), uint32(rate), uint32(rate * 2), uint16(2), uint16(16)})
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(len(samples)*2))
+	binary.Write(&buf, binary.LittleEndian, samples)
+
+	data := buf.Bytes()
+
+	for len(data) > 0 {
+		n := min(len(data), 32*1024)
+
+		if err := stream.Send(&custom.Synthesis{
+			Id:          "speech",
+			Name:        "speech.wav",
+			ContentType: "audio/wav",
+
+			Data: data[:n],
+		}); err != nil {
+			return err
+		}
+
+		data = data[n:]
+	}
+
+	return nil
+}
+
+func embed(text string) []float32 {
+	result := make([]float32, 64)
+
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		h := fnv.New32a()
+		h.Write([]byte(word))
+
+		result[h.Sum32()%64]++
+	}
+
+	var norm float64
+
+	for _, v := range result {
+		norm += float64(v * v)
+	}
+
+	if norm > 0 {
+		for i := range result {
+			result[i] /= float32(math.Sqrt(norm))
+		}
+	}
+
+	return result
+}
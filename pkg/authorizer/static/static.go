@@ -24,6 +24,11 @@ func (p *Provider) Verify(ctx context.Context, r *http.Request) error {
 
 	header := r.Header.Get("Authorization")
 
+	// anthropic clients send the key without a scheme
+	if key := r.Header.Get("X-Api-Key"); header == "" && key != "" {
+		header = "Bearer " + key
+	}
+
 	if header == "" {
 		return errors.New("missing authorization header")
 	}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"slices"
+	"sync"
+	"time"
 
 	"github.com/adrianliechti/wingman/pkg/chain"
 	"github.com/adrianliechti/wingman/pkg/provider"
@@ -15,6 +17,11 @@ import (
 
 var _ chain.Provider = &Chain{}
 
+var ErrMaxIterations = errors.New("agent exceeded max iterations")
+
+// errUnknownTool is returned to the model for calls of tools it was not given
+var errUnknownTool = errors.New("unknown tool")
+
 type Chain struct {
 	completer provider.Completer
 
@@ -23,12 +30,19 @@ type Chain struct {
 
 	effort      provider.ReasoningEffort
 	temperature *float32
+
+	maxIterations int
+
+	toolTimeout     time.Duration
+	toolConcurrency int
 }
 
 type Option func(*Chain)
 
 func New(options ...Option) (*Chain, error) {
-	c := &Chain{}
+	c := &Chain{
+		maxIterations: 10,
+	}
 
 	for _, option := range options {
 		option(c)
@@ -71,6 +85,26 @@ func WithTemperature(temperature float32) Option {
 	}
 }
 
+// WithMaxIterations limits the completions of one request, the last one answers without calling tools. Zero allows any number
+func WithMaxIterations(iterations int) Option {
+	return func(c *Chain) {
+		c.maxIterations = iterations
+	}
+}
+
+func WithToolTimeout(timeout time.Duration) Option {
+	return func(c *Chain) {
+		c.toolTimeout = timeout
+	}
+}
+
+// WithToolConcurrency limits the tool calls of a turn running at the same time, zero allows any number
+func WithToolConcurrency(concurrency int) Option {
+	return func(c *Chain) {
+		c.toolConcurrency = concurrency
+	}
+}
+
 func (c *Chain) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
 	if options == nil {
 		options = new(provider.CompleteOptions)
@@ -112,8 +146,12 @@ func (c *Chain) Complete(ctx context.Context, messages []provider.Message, optio
 		}
 	}
 
+	// tools of the caller are returned to it instead of being run by the agent
+	callerTools := make(map[string]bool)
+
 	for _, t := range options.Tools {
 		inputTools[t.Name] = t
+		callerTools[t.Name] = true
 	}
 
 	var result *provider.Completion
@@ -121,11 +159,25 @@ func (c *Chain) Complete(ctx context.Context, messages []provider.Message, optio
 	inputOptions := &provider.CompleteOptions{
 		Effort: options.Effort,
 
+		ReasoningBudget: options.ReasoningBudget,
+
 		Stop:  options.Stop,
 		Tools: to.Values(inputTools),
 
+		ToolChoice:        options.ToolChoice,
+		ParallelToolCalls: options.ParallelToolCalls,
+
 		MaxTokens:   options.MaxTokens,
 		Temperature: options.Temperature,
+		TopP:        options.TopP,
+
+		PresencePenalty:  options.PresencePenalty,
+		FrequencyPenalty: options.FrequencyPenalty,
+
+		Seed: options.Seed,
+
+		Logprobs:    options.Logprobs,
+		TopLogprobs: options.TopLogprobs,
 
 		Format: options.Format,
 		Schema: options.Schema,
@@ -150,7 +202,7 @@ func (c *Chain) Complete(ctx context.Context, messages []provider.Message, optio
 				continue
 			}
 
-			if _, found := agentTools[lastToolCallName]; !found {
+			if _, found := agentTools[lastToolCallName]; !found && callerTools[lastToolCallName] {
 				call := streamToolCalls[lastToolCallID]
 				call.ID = lastToolCallID
 				call.Name = lastToolCallName
@@ -173,7 +225,16 @@ func (c *Chain) Complete(ctx context.Context, messages []provider.Message, optio
 		inputOptions.Stream = stream
 	}
 
-	for {
+	for iteration := 1; ; iteration++ {
+		last := c.maxIterations > 0 && iteration >= c.maxIterations
+
+		// the last completion has to answer, so the conversation so far is not lost
+		if last {
+			inputOptions.ToolChoice = &provider.ToolChoice{
+				Mode: provider.ToolChoiceModeNone,
+			}
+		}
+
 		completion, err := c.completer.Complete(ctx, input, inputOptions)
 
 		if err != nil {
@@ -182,47 +243,40 @@ func (c *Chain) Complete(ctx context.Context, messages []provider.Message, optio
 
 		input = append(input, completion.Message)
 
-		var loop bool
+		var calls []provider.ToolCall
 
 		for _, t := range completion.Message.ToolCalls {
-			p, found := agentTools[t.Name]
-
-			if !found {
-				continue
-			}
-
-			var params map[string]any
-
-			if err := json.Unmarshal([]byte(t.Arguments), &params); err != nil {
-				return nil, err
+			if _, found := agentTools[t.Name]; found || !callerTools[t.Name] {
+				calls = append(calls, t)
 			}
+		}
 
-			result, err := p.Execute(ctx, t.Name, params)
+		if len(calls) == 0 {
+			result = completion
+			break
+		}
 
-			if err != nil {
-				return nil, err
-			}
+		if last {
+			return nil, ErrMaxIterations
+		}
 
-			data, err := json.Marshal(result)
+		results := c.execute(ctx, agentTools, calls)
 
-			if err != nil {
-				return nil, err
-			}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 
+		for i, t := range calls {
 			input = append(input, provider.Message{
 				Role: provider.MessageRoleTool,
 
 				Tool:    t.ID,
-				Content: string(data),
+				Content: results[i],
 			})
-
-			loop = true
 		}
 
-		if !loop {
-			result = completion
-			break
-		}
+		// a forced tool choice only applies to the first turn, otherwise the agent would never stop calling tools
+		inputOptions.ToolChoice = nil
 	}
 
 	if result == nil {
@@ -231,3 +285,96 @@ func (c *Chain) Complete(ctx context.Context, messages []provider.Message, optio
 
 	return result, nil
 }
+
+// execute runs the tool calls of a turn concurrently and returns their results in order
+func (c *Chain) execute(ctx context.Context, tools map[string]tool.Provider, calls []provider.ToolCall) []string {
+	results := make([]string, len(calls))
+
+	var sem chan struct{}
+
+	if c.toolConcurrency > 0 {
+		sem = make(chan struct{}, c.toolConcurrency)
+	}
+
+	var wg sync.WaitGroup
+
+	for i, t := range calls {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if sem != nil {
+				sem <- struct{}{}
+				defer func() { <-sem }()
+			}
+
+			p, ok := tools[t.Name]
+
+			if !ok {
+				results[i] = toolError(errUnknownTool)
+				return
+			}
+
+			results[i] = c.executeTool(ctx, p, t)
+		}()
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// executeTool returns the result of a tool call, or its error for the model to recover from
+func (c *Chain) executeTool(ctx context.Context, p tool.Provider, call provider.ToolCall) string {
+	result, err := c.runTool(ctx, p, call)
+
+	if err != nil {
+		return toolError(err)
+	}
+
+	data, err := json.Marshal(result)
+
+	if err != nil {
+		return toolError(err)
+	}
+
+	return string(data)
+}
+
+func (c *Chain) runTool(ctx context.Context, p tool.Provider, call provider.ToolCall) (any, error) {
+	var params map[string]any
+
+	if call.Arguments != "" {
+		if err := json.Unmarshal([]byte(call.Arguments), &params); err != nil {
+			return nil, errors.New("invalid tool arguments: " + err.Error())
+		}
+	}
+
+	if params == nil {
+		params = map[string]any{}
+	}
+
+	if c.toolTimeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, c.toolTimeout)
+		defer cancel()
+	}
+
+	result, err := p.Execute(ctx, call.Name, params)
+
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, errors.New("tool timed out after " + c.toolTimeout.String())
+	}
+
+	return result, err
+}
+
+func toolError(err error) string {
+	data, _ := json.Marshal(map[string]string{
+		"error": err.Error(),
+	})
+
+	return string(data)
+}
@@ -0,0 +1,254 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/tool"
+
+	"github.com/stretchr/testify/require"
+)
+
+// completer calls the given tools on the first turn and answers with the received tool results afterwards.
+// A looping completer calls the tools on every turn it is allowed to.
+type completer struct {
+	calls []provider.ToolCall
+	loop  bool
+
+	// ignoreChoice calls tools even if the tool choice forbids it
+	ignoreChoice bool
+
+	mu          sync.Mutex
+	messages    []provider.Message
+	completions int
+}
+
+func (c *completer) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.messages = messages
+	c.completions++
+
+	allowed := c.ignoreChoice || options.ToolChoice == nil || options.ToolChoice.Mode != provider.ToolChoiceModeNone
+
+	if allowed && (c.loop || messages[len(messages)-1].Role != provider.MessageRoleTool) {
+		return &provider.Completion{
+			Reason: provider.CompletionReasonTool,
+
+			Message: provider.Message{
+				Role:      provider.MessageRoleAssistant,
+				ToolCalls: c.calls,
+			},
+		}, nil
+	}
+
+	return &provider.Completion{
+		Reason: provider.CompletionReasonStop,
+
+		Message: provider.Message{
+			Role:    provider.MessageRoleAssistant,
+			Content: "done",
+		},
+	}, nil
+}
+
+type tools struct {
+	delay time.Duration
+
+	mu      sync.Mutex
+	running int
+	peak    int
+}
+
+func (t *tools) Tools(ctx context.Context) ([]tool.Tool, error) {
+	return []tool.Tool{{Name: "sleep"}, {Name: "fail"}}, nil
+}
+
+func (t *tools) Execute(ctx context.Context, name string, parameters map[string]any) (any, error) {
+	if name == "fail" {
+		return nil, errors.New("boom")
+	}
+
+	t.mu.Lock()
+	t.running++
+	t.peak = max(t.peak, t.running)
+	t.mu.Unlock()
+
+	defer func() {
+		t.mu.Lock()
+		t.running--
+		t.mu.Unlock()
+	}()
+
+	select {
+	case <-time.After(t.delay):
+		return parameters, nil
+
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestToolResults(t *testing.T) {
+	c := &completer{
+		calls: []provider.ToolCall{
+			{ID: "1", Name: "sleep", Arguments: `{"n":1}`},
+			{ID: "2", Name: "fail", Arguments: `{}`},
+			{ID: "3", Name: "sleep", Arguments: `{invalid`},
+			{ID: "4", Name: "sleep", Arguments: `{"n":4}`},
+		},
+	}
+
+	p := &tools{delay: 50 * time.Millisecond}
+
+	chain, err := New(WithCompleter(c), WithTools(p))
+	require.NoError(t, err)
+
+	result, err := chain.Complete(context.Background(), []provider.Message{{Role: provider.MessageRoleUser, Content: "go"}}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "done", result.Message.Content)
+
+	messages := c.messages[2:]
+	require.Len(t, messages, 4)
+
+	require.Equal(t, "1", messages[0].Tool)
+	require.Equal(t, `{"n":1}`, messages[0].Content)
+
+	require.Equal(t, "2", messages[1].Tool)
+	require.Equal(t, `{"error":"boom"}`, messages[1].Content)
+
+	require.Equal(t, "3", messages[2].Tool)
+	require.Contains(t, messages[2].Content, "invalid tool arguments")
+
+	require.Equal(t, "4", messages[3].Tool)
+	require.Equal(t, `{"n":4}`, messages[3].Content)
+
+	require.Equal(t, 2, p.peak)
+}
+
+func TestToolConcurrency(t *testing.T) {
+	c := &completer{
+		calls: []provider.ToolCall{
+			{ID: "1", Name: "sleep"},
+			{ID: "2", Name: "sleep"},
+			{ID: "3", Name: "sleep"},
+		},
+	}
+
+	p := &tools{delay: 20 * time.Millisecond}
+
+	chain, err := New(WithCompleter(c), WithTools(p), WithToolConcurrency(1))
+	require.NoError(t, err)
+
+	_, err = chain.Complete(context.Background(), []provider.Message{{Role: provider.MessageRoleUser, Content: "go"}}, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, 1, p.peak)
+}
+
+func TestToolTimeout(t *testing.T) {
+	c := &completer{
+		calls: []provider.ToolCall{
+			{ID: "1", Name: "sleep"},
+		},
+	}
+
+	chain, err := New(WithCompleter(c), WithTools(&tools{delay: time.Second}), WithToolTimeout(10*time.Millisecond))
+	require.NoError(t, err)
+
+	result, err := chain.Complete(context.Background(), []provider.Message{{Role: provider.MessageRoleUser, Content: "go"}}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "done", result.Message.Content)
+
+	require.Equal(t, `{"error":"tool timed out after 10ms"}`, c.messages[len(c.messages)-1].Content)
+}
+
+func TestUnknownTool(t *testing.T) {
+	c := &completer{
+		calls: []provider.ToolCall{
+			{ID: "1", Name: "sleep", Arguments: `{"n":1}`},
+			{ID: "2", Name: "missing", Arguments: `{}`},
+		},
+	}
+
+	chain, err := New(WithCompleter(c), WithTools(&tools{}))
+	require.NoError(t, err)
+
+	result, err := chain.Complete(context.Background(), []provider.Message{{Role: provider.MessageRoleUser, Content: "go"}}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "done", result.Message.Content)
+
+	messages := c.messages[2:]
+	require.Len(t, messages, 2)
+
+	require.Equal(t, `{"n":1}`, messages[0].Content)
+
+	require.Equal(t, "2", messages[1].Tool)
+	require.Equal(t, `{"error":"unknown tool"}`, messages[1].Content)
+}
+
+func TestCallerTools(t *testing.T) {
+	c := &completer{
+		calls: []provider.ToolCall{
+			{ID: "1", Name: "lookup", Arguments: `{}`},
+		},
+	}
+
+	chain, err := New(WithCompleter(c), WithTools(&tools{}))
+	require.NoError(t, err)
+
+	// calls of tools passed by the caller are returned to it
+	result, err := chain.Complete(context.Background(), []provider.Message{{Role: provider.MessageRoleUser, Content: "go"}}, &provider.CompleteOptions{
+		Tools: []provider.Tool{{Name: "lookup"}},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, provider.CompletionReasonTool, result.Reason)
+	require.Equal(t, c.calls, result.Message.ToolCalls)
+	require.Equal(t, 1, c.completions)
+}
+
+func TestMaxIterations(t *testing.T) {
+	c := &completer{
+		calls: []provider.ToolCall{
+			{ID: "1", Name: "sleep"},
+		},
+
+		loop: true,
+	}
+
+	chain, err := New(WithCompleter(c), WithTools(&tools{}), WithMaxIterations(3))
+	require.NoError(t, err)
+
+	// the last completion answers with the tool results gathered so far
+	result, err := chain.Complete(context.Background(), []provider.Message{{Role: provider.MessageRoleUser, Content: "go"}}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "done", result.Message.Content)
+
+	require.Equal(t, 3, c.completions)
+	require.Len(t, c.messages, 5)
+}
+
+func TestMaxIterationsExceeded(t *testing.T) {
+	c := &completer{
+		calls: []provider.ToolCall{
+			{ID: "1", Name: "sleep"},
+		},
+
+		loop:         true,
+		ignoreChoice: true,
+	}
+
+	chain, err := New(WithCompleter(c), WithTools(&tools{}), WithMaxIterations(2))
+	require.NoError(t, err)
+
+	_, err = chain.Complete(context.Background(), []provider.Message{{Role: provider.MessageRoleUser, Content: "go"}}, nil)
+	require.ErrorIs(t, err, ErrMaxIterations)
+
+	require.Equal(t, 2, c.completions)
+}
@@ -11,6 +11,10 @@ import (
 )
 
 func (c *Client) Query(ctx context.Context, query string, options *index.QueryOptions) ([]index.Result, error) {
+	if err := options.CheckMode(index.QueryModeKeyword); err != nil {
+		return nil, err
+	}
+
 	if options == nil {
 		options = new(index.QueryOptions)
 	}
@@ -27,6 +31,16 @@ func (c *Client) Query(ctx context.Context, query string, options *index.QueryOp
 		queries["$top"] = fmt.Sprintf("%d", *options.Limit)
 	}
 
+	if f := options.Expression(); f != nil {
+		filter, err := convertFilter(*f)
+
+		if err != nil {
+			return nil, err
+		}
+
+		queries["$filter"] = filter
+	}
+
 	req, _ := http.NewRequestWithContext(ctx, "GET", c.requestURL("/indexes/"+c.namespace+"/docs", queries), nil)
 	req.Header.Set("api-key", c.token)
 
@@ -0,0 +1,103 @@
+package azure
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+)
+
+// convertFilter translates a filter expression into an OData $filter over the metadata key/value collection
+func convertFilter(f index.Filter) (string, error) {
+	switch {
+	case len(f.And) > 0:
+		return convertOperands("and", f.And)
+
+	case len(f.Or) > 0:
+		return convertOperands("or", f.Or)
+
+	case f.Not != nil:
+		filter, err := convertFilter(*f.Not)
+
+		if err != nil {
+			return "", err
+		}
+
+		return "not (" + filter + ")", nil
+	}
+
+	match := func(condition string) string {
+		expr := "m/key eq " + quote(f.Field)
+
+		if condition != "" {
+			expr += " and " + condition
+		}
+
+		return "metadata/any(m: " + expr + ")"
+	}
+
+	switch f.Operator {
+	case index.FilterOperatorEqual:
+		return match("m/value eq " + quote(f.Value)), nil
+
+	case index.FilterOperatorNotEqual:
+		return "not " + match("m/value eq "+quote(f.Value)), nil
+
+	case index.FilterOperatorIn:
+		return match(valuesCondition(f.Values)), nil
+
+	case index.FilterOperatorNotIn:
+		return "not " + match(valuesCondition(f.Values)), nil
+
+	case index.FilterOperatorExists:
+		return match(""), nil
+
+	case index.FilterOperatorGreater, index.FilterOperatorGreaterEqual, index.FilterOperatorLess, index.FilterOperatorLessEqual:
+		// values are stored as text, which orders ISO dates but not numbers
+		if _, err := strconv.ParseFloat(f.Value, 64); err == nil {
+			return "", fmt.Errorf("%w: %s on %s", index.ErrUnsupportedFilter, f.Operator, f.Field)
+		}
+
+		operator := map[index.FilterOperator]string{
+			index.FilterOperatorGreater:      "gt",
+			index.FilterOperatorGreaterEqual: "ge",
+			index.FilterOperatorLess:         "lt",
+			index.FilterOperatorLessEqual:    "le",
+		}[f.Operator]
+
+		return match("m/value " + operator + " " + quote(f.Value)), nil
+	}
+
+	return "", index.ErrUnsupportedFilter
+}
+
+func convertOperands(operator string, filters []index.Filter) (string, error) {
+	var operands []string
+
+	for _, f := range filters {
+		operand, err := convertFilter(f)
+
+		if err != nil {
+			return "", err
+		}
+
+		operands = append(operands, "("+operand+")")
+	}
+
+	return strings.Join(operands, " "+operator+" "), nil
+}
+
+func valuesCondition(values []string) string {
+	var conditions []string
+
+	for _, v := range values {
+		conditions = append(conditions, "m/value eq "+quote(v))
+	}
+
+	return "(" + strings.Join(conditions, " or ") + ")"
+}
+
+func quote(val string) string {
+	return "'" + strings.ReplaceAll(val, "'", "''") + "'"
+}
@@ -0,0 +1,31 @@
+package azure
+
+import (
+	"testing"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertFilter(t *testing.T) {
+	result, err := convertFilter(index.Filter{
+		And: []index.Filter{
+			{Field: "date", Operator: index.FilterOperatorLess, Value: "2024-07-01"},
+			{Field: "department", Operator: index.FilterOperatorNotIn, Values: []string{"x", "y"}},
+		},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "(metadata/any(m: m/key eq 'date' and m/value lt '2024-07-01')) and (not metadata/any(m: m/key eq 'department' and (m/value eq 'x' or m/value eq 'y')))", result)
+
+	result, err = convertFilter(index.Filter{Field: "owner", Operator: index.FilterOperatorEqual, Value: "o'neil"})
+
+	require.NoError(t, err)
+	require.Equal(t, "metadata/any(m: m/key eq 'owner' and m/value eq 'o''neil')", result)
+}
+
+func TestConvertFilterNumber(t *testing.T) {
+	_, err := convertFilter(index.Filter{Field: "pages", Operator: index.FilterOperatorGreater, Value: "9"})
+	require.ErrorIs(t, err, index.ErrUnsupportedFilter)
+}
@@ -24,7 +24,6 @@ type Client struct {
 	namespace string
 
 	embedder index.Embedder
-	reranker index.Reranker
 }
 
 func New(url, namespace string, options ...Option) (*Client, error) {
@@ -217,6 +216,10 @@ func (c *Client) Delete(ctx context.Context, ids ...string) error {
 }
 
 func (c *Client) Query(ctx context.Context, query string, options *index.QueryOptions) ([]index.Result, error) {
+	if err := options.CheckMode(index.QueryModeVector); err != nil {
+		return nil, err
+	}
+
 	if options == nil {
 		options = &index.QueryOptions{}
 	}
@@ -247,8 +250,14 @@ func (c *Client) Query(ctx context.Context, query string, options *index.QueryOp
 		},
 	}
 
-	if len(options.Filters) > 0 {
-		body["where"] = options.Filters
+	if f := options.Expression(); f != nil {
+		where, err := convertFilter(*f)
+
+		if err != nil {
+			return nil, err
+		}
+
+		body["where"] = where
 	}
 
 	if options.Limit != nil {
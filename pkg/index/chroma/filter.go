@@ -0,0 +1,66 @@
+package chroma
+
+import (
+	"fmt"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+)
+
+// convertFilter translates a filter expression into a chroma where clause
+func convertFilter(f index.Filter) (map[string]any, error) {
+	switch {
+	case len(f.And) > 0:
+		return convertOperands("$and", f.And)
+
+	case len(f.Or) > 0:
+		return convertOperands("$or", f.Or)
+
+	case f.Not != nil:
+		// chroma has no negation operator, so it is pushed down to the conditions
+		n, err := f.Not.Negate()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return convertFilter(n)
+	}
+
+	switch f.Operator {
+	case index.FilterOperatorEqual:
+		return map[string]any{f.Field: map[string]any{"$eq": f.Value}}, nil
+
+	case index.FilterOperatorNotEqual:
+		return map[string]any{f.Field: map[string]any{"$ne": f.Value}}, nil
+
+	case index.FilterOperatorIn:
+		return map[string]any{f.Field: map[string]any{"$in": f.Values}}, nil
+
+	case index.FilterOperatorNotIn:
+		return map[string]any{f.Field: map[string]any{"$nin": f.Values}}, nil
+	}
+
+	// chroma compares numbers only, but metadata is stored as text
+	return nil, fmt.Errorf("%w: %s on %s", index.ErrUnsupportedFilter, f.Operator, f.Field)
+}
+
+func convertOperands(operator string, filters []index.Filter) (map[string]any, error) {
+	var operands []any
+
+	for _, f := range filters {
+		operand, err := convertFilter(f)
+
+		if err != nil {
+			return nil, err
+		}
+
+		operands = append(operands, operand)
+	}
+
+	// chroma requires at least two operands for logical operators
+	if len(operands) == 1 {
+		return operands[0].(map[string]any), nil
+	}
+
+	return map[string]any{operator: operands}, nil
+}
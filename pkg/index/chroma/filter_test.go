@@ -0,0 +1,45 @@
+package chroma
+
+import (
+	"testing"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertFilter(t *testing.T) {
+	result, err := convertFilter(index.Filter{
+		And: []index.Filter{
+			{Field: "department", Operator: index.FilterOperatorIn, Values: []string{"x", "y"}},
+			{Field: "status", Operator: index.FilterOperatorNotEqual, Value: "draft"},
+		},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"$and": []any{
+			map[string]any{"department": map[string]any{"$in": []string{"x", "y"}}},
+			map[string]any{"status": map[string]any{"$ne": "draft"}},
+		},
+	}, result)
+
+	// a single operand is not wrapped, and negations are pushed down to the conditions
+	result, err = convertFilter(index.Filter{
+		Or: []index.Filter{
+			{Not: &index.Filter{Field: "department", Operator: index.FilterOperatorIn, Values: []string{"x"}}},
+		},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"department": map[string]any{"$nin": []string{"x"}}}, result)
+}
+
+func TestConvertFilterRange(t *testing.T) {
+	// chroma compares numbers only, but metadata is stored as text
+	_, err := convertFilter(index.Filter{Field: "date", Operator: index.FilterOperatorGreaterEqual, Value: "2024-07-01"})
+	require.ErrorIs(t, err, index.ErrUnsupportedFilter)
+
+	_, err = convertFilter(index.Filter{Field: "pages", Operator: index.FilterOperatorLess, Value: "9"})
+	require.ErrorIs(t, err, index.ErrUnsupportedFilter)
+}
@@ -32,6 +32,10 @@ func New(options ...Option) (*Client, error) {
 }
 
 func (c *Client) Query(ctx context.Context, query string, options *index.QueryOptions) ([]index.Result, error) {
+	if err := options.CheckMode(index.QueryModeKeyword); err != nil {
+		return nil, err
+	}
+
 	url, _ := url.Parse("https://duckduckgo.com/html/")
 
 	values := url.Query()
@@ -161,16 +161,37 @@ func (c *Client) Delete(ctx context.Context, ids ...string) error {
 }
 
 func (c *Client) Query(ctx context.Context, query string, options *index.QueryOptions) ([]index.Result, error) {
+	if err := options.CheckMode(index.QueryModeKeyword); err != nil {
+		return nil, err
+	}
+
 	u, _ := url.JoinPath(c.url, "/"+c.namespace+"/_search")
 
+	match := map[string]any{
+		"multi_match": map[string]any{
+			"query":    query,
+			"fields":   []string{"content", "metadata.*"},
+			"analyzer": "english",
+		},
+	}
+
 	body := map[string]any{
-		"query": map[string]any{
-			"multi_match": map[string]any{
-				"query":    query,
-				"fields":   []string{"content", "metadata.*"},
-				"analyzer": "english",
+		"query": match,
+	}
+
+	if f := options.Expression(); f != nil {
+		filter, err := convertFilter(*f)
+
+		if err != nil {
+			return nil, err
+		}
+
+		body["query"] = map[string]any{
+			"bool": map[string]any{
+				"must":   []any{match},
+				"filter": []any{filter},
 			},
-		},
+		}
 	}
 
 	req, _ := http.NewRequestWithContext(ctx, "GET", u, jsonReader(body))
@@ -0,0 +1,100 @@
+package elasticsearch
+
+import (
+	"fmt"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+)
+
+// convertFilter translates a filter expression into an elasticsearch query clause
+func convertFilter(f index.Filter) (map[string]any, error) {
+	switch {
+	case len(f.And) > 0:
+		clauses, err := convertFilters(f.And)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]any{"bool": map[string]any{"filter": clauses}}, nil
+
+	case len(f.Or) > 0:
+		clauses, err := convertFilters(f.Or)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]any{"bool": map[string]any{"should": clauses, "minimum_should_match": 1}}, nil
+
+	case f.Not != nil:
+		clause, err := convertFilter(*f.Not)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]any{"bool": map[string]any{"must_not": []any{clause}}}, nil
+	}
+
+	field := "metadata." + f.Field
+
+	// dynamic mapping indexes strings as text with a keyword sub-field for exact matches
+	keyword := field + ".keyword"
+
+	switch f.Operator {
+	case index.FilterOperatorEqual:
+		return map[string]any{"term": map[string]any{keyword: map[string]any{"value": f.Value, "case_insensitive": true}}}, nil
+
+	case index.FilterOperatorNotEqual:
+		return map[string]any{"bool": map[string]any{"must_not": []any{
+			map[string]any{"term": map[string]any{keyword: map[string]any{"value": f.Value, "case_insensitive": true}}},
+		}}}, nil
+
+	// terms has no case_insensitive option, so the values are matched one by one like eq
+	case index.FilterOperatorIn:
+		return map[string]any{"bool": map[string]any{"should": terms(keyword, f.Values), "minimum_should_match": 1}}, nil
+
+	case index.FilterOperatorNotIn:
+		return map[string]any{"bool": map[string]any{"must_not": terms(keyword, f.Values)}}, nil
+
+	case index.FilterOperatorExists:
+		return map[string]any{"exists": map[string]any{"field": field}}, nil
+
+	case index.FilterOperatorGreater, index.FilterOperatorGreaterEqual, index.FilterOperatorLess, index.FilterOperatorLessEqual:
+		// dynamic mapping detects dates, but indexes numbers in strings as text, which range does not compare
+		if _, ok := index.ParseDate(f.Value); !ok {
+			return nil, fmt.Errorf("%w: %s on %s", index.ErrUnsupportedFilter, f.Operator, f.Field)
+		}
+
+		return map[string]any{"range": map[string]any{field: map[string]any{string(f.Operator): f.Value}}}, nil
+	}
+
+	return nil, index.ErrUnsupportedFilter
+}
+
+func convertFilters(filters []index.Filter) ([]any, error) {
+	var result []any
+
+	for _, f := range filters {
+		clause, err := convertFilter(f)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, clause)
+	}
+
+	return result, nil
+}
+
+func terms(field string, values []string) []any {
+	var result []any
+
+	for _, v := range values {
+		result = append(result, map[string]any{"term": map[string]any{field: map[string]any{"value": v, "case_insensitive": true}}})
+	}
+
+	return result
+}
@@ -0,0 +1,37 @@
+package elasticsearch
+
+import (
+	"testing"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertFilter(t *testing.T) {
+	result, err := convertFilter(index.Filter{
+		And: []index.Filter{
+			{Field: "date", Operator: index.FilterOperatorLessEqual, Value: "2024-07-01"},
+			{Field: "department", Operator: index.FilterOperatorIn, Values: []string{"X", "y"}},
+		},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"bool": map[string]any{"filter": []any{
+			map[string]any{"range": map[string]any{"metadata.date": map[string]any{"lte": "2024-07-01"}}},
+			map[string]any{"bool": map[string]any{
+				"should": []any{
+					map[string]any{"term": map[string]any{"metadata.department.keyword": map[string]any{"value": "X", "case_insensitive": true}}},
+					map[string]any{"term": map[string]any{"metadata.department.keyword": map[string]any{"value": "y", "case_insensitive": true}}},
+				},
+				"minimum_should_match": 1,
+			}},
+		}},
+	}, result)
+}
+
+func TestConvertFilterNumber(t *testing.T) {
+	_, err := convertFilter(index.Filter{Field: "pages", Operator: index.FilterOperatorGreater, Value: "9"})
+	require.ErrorIs(t, err, index.ErrUnsupportedFilter)
+}
@@ -0,0 +1,321 @@
+package index
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Filter is a boolean expression over the document metadata.
+// A filter is either a condition on a single field, or a combination of filters using And, Or or Not.
+// The zero value matches every document.
+type Filter struct {
+	Field    string
+	Operator FilterOperator
+
+	Value  string
+	Values []string
+
+	And []Filter
+	Or  []Filter
+	Not *Filter
+}
+
+type FilterOperator string
+
+const (
+	FilterOperatorEqual    FilterOperator = "eq"
+	FilterOperatorNotEqual FilterOperator = "ne"
+
+	FilterOperatorIn    FilterOperator = "in"
+	FilterOperatorNotIn FilterOperator = "nin"
+
+	FilterOperatorGreater      FilterOperator = "gt"
+	FilterOperatorGreaterEqual FilterOperator = "gte"
+	FilterOperatorLess         FilterOperator = "lt"
+	FilterOperatorLessEqual    FilterOperator = "lte"
+
+	FilterOperatorExists FilterOperator = "exists"
+)
+
+var ErrUnsupportedFilter = errors.New("unsupported filter")
+
+// Expression combines the equality Filters and the Filter of the options into a single filter, or nil if there are none
+func (o *QueryOptions) Expression() *Filter {
+	if o == nil {
+		return nil
+	}
+
+	var filters []Filter
+
+	keys := make([]string, 0, len(o.Filters))
+
+	for k := range o.Filters {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		filters = append(filters, Filter{
+			Field:    k,
+			Operator: FilterOperatorEqual,
+			Value:    o.Filters[k],
+		})
+	}
+
+	if o.Filter != nil && !o.Filter.IsEmpty() {
+		filters = append(filters, *o.Filter)
+	}
+
+	switch len(filters) {
+	case 0:
+		return nil
+
+	case 1:
+		return &filters[0]
+
+	default:
+		return &Filter{And: filters}
+	}
+}
+
+func (f Filter) IsEmpty() bool {
+	return f.Operator == "" && len(f.And) == 0 && len(f.Or) == 0 && f.Not == nil
+}
+
+func (f Filter) Validate() error {
+	switch {
+	case len(f.And) > 0:
+		for _, c := range f.And {
+			if err := c.Validate(); err != nil {
+				return err
+			}
+		}
+
+	case len(f.Or) > 0:
+		for _, c := range f.Or {
+			if err := c.Validate(); err != nil {
+				return err
+			}
+		}
+
+	case f.Not != nil:
+		return f.Not.Validate()
+
+	case f.Operator == "":
+		return nil
+
+	default:
+		if f.Field == "" {
+			return errors.New("filter field is required")
+		}
+
+		switch f.Operator {
+		case FilterOperatorEqual, FilterOperatorNotEqual, FilterOperatorExists:
+		case FilterOperatorGreater, FilterOperatorGreaterEqual, FilterOperatorLess, FilterOperatorLessEqual:
+		case FilterOperatorIn, FilterOperatorNotIn:
+			if len(f.Values) == 0 {
+				return errors.New("filter values are required: " + f.Field)
+			}
+
+		default:
+			return errors.New("invalid filter operator: " + string(f.Operator))
+		}
+	}
+
+	return nil
+}
+
+// Negate returns the filter matching the opposite, with the negation pushed down to the conditions.
+// Negating exists is not expressible without Not and returns ErrUnsupportedFilter.
+func (f Filter) Negate() (Filter, error) {
+	switch {
+	case len(f.And) > 0:
+		var filters []Filter
+
+		for _, c := range f.And {
+			n, err := c.Negate()
+
+			if err != nil {
+				return Filter{}, err
+			}
+
+			filters = append(filters, n)
+		}
+
+		return Filter{Or: filters}, nil
+
+	case len(f.Or) > 0:
+		var filters []Filter
+
+		for _, c := range f.Or {
+			n, err := c.Negate()
+
+			if err != nil {
+				return Filter{}, err
+			}
+
+			filters = append(filters, n)
+		}
+
+		return Filter{And: filters}, nil
+
+	case f.Not != nil:
+		return *f.Not, nil
+	}
+
+	negated := map[FilterOperator]FilterOperator{
+		FilterOperatorEqual:    FilterOperatorNotEqual,
+		FilterOperatorNotEqual: FilterOperatorEqual,
+
+		FilterOperatorIn:    FilterOperatorNotIn,
+		FilterOperatorNotIn: FilterOperatorIn,
+
+		FilterOperatorGreater:      FilterOperatorLessEqual,
+		FilterOperatorGreaterEqual: FilterOperatorLess,
+		FilterOperatorLess:         FilterOperatorGreaterEqual,
+		FilterOperatorLessEqual:    FilterOperatorGreater,
+	}
+
+	op, ok := negated[f.Operator]
+
+	if !ok {
+		return Filter{}, ErrUnsupportedFilter
+	}
+
+	f.Operator = op
+	return f, nil
+}
+
+// Match evaluates the filter against the metadata of a document.
+// Equality is case-insensitive; range comparisons compare numbers and dates by value and anything else as text.
+func (f Filter) Match(metadata map[string]string) bool {
+	switch {
+	case len(f.And) > 0:
+		for _, c := range f.And {
+			if !c.Match(metadata) {
+				return false
+			}
+		}
+
+		return true
+
+	case len(f.Or) > 0:
+		for _, c := range f.Or {
+			if c.Match(metadata) {
+				return true
+			}
+		}
+
+		return false
+
+	case f.Not != nil:
+		return !f.Not.Match(metadata)
+
+	case f.Operator == "":
+		return true
+	}
+
+	value, ok := metadata[f.Field]
+
+	switch f.Operator {
+	case FilterOperatorExists:
+		return ok
+
+	case FilterOperatorEqual:
+		return ok && strings.EqualFold(value, f.Value)
+
+	case FilterOperatorNotEqual:
+		return !ok || !strings.EqualFold(value, f.Value)
+
+	case FilterOperatorIn:
+		if !ok {
+			return false
+		}
+
+		for _, v := range f.Values {
+			if strings.EqualFold(value, v) {
+				return true
+			}
+		}
+
+		return false
+
+	case FilterOperatorNotIn:
+		if !ok {
+			return true
+		}
+
+		for _, v := range f.Values {
+			if strings.EqualFold(value, v) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	if !ok {
+		return false
+	}
+
+	c := CompareValues(value, f.Value)
+
+	switch f.Operator {
+	case FilterOperatorGreater:
+		return c > 0
+
+	case FilterOperatorGreaterEqual:
+		return c >= 0
+
+	case FilterOperatorLess:
+		return c < 0
+
+	case FilterOperatorLessEqual:
+		return c <= 0
+	}
+
+	return false
+}
+
+// CompareValues compares two metadata values as numbers, as dates or as text, whichever applies to both
+func CompareValues(a, b string) int {
+	if x, err := strconv.ParseFloat(a, 64); err == nil {
+		if y, err := strconv.ParseFloat(b, 64); err == nil {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			default:
+				return 0
+			}
+		}
+	}
+
+	if x, ok := ParseDate(a); ok {
+		if y, ok := ParseDate(b); ok {
+			return x.Compare(y)
+		}
+	}
+
+	return strings.Compare(a, b)
+}
+
+// ParseDate parses RFC 3339 timestamps and plain dates
+func ParseDate(val string) (time.Time, bool) {
+	for _, layout := range []string{
+		time.RFC3339Nano,
+		"2006-01-02T15:04:05",
+		"2006-01-02",
+	} {
+		if t, err := time.Parse(layout, val); err == nil {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,70 @@
+package index_test
+
+import (
+	"testing"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterMatch(t *testing.T) {
+	// documents from the last 90 days in department X or Y
+	filter := index.Filter{
+		And: []index.Filter{
+			{Field: "date", Operator: index.FilterOperatorGreaterEqual, Value: "2024-07-01"},
+			{Field: "department", Operator: index.FilterOperatorIn, Values: []string{"x", "y"}},
+			{Not: &index.Filter{Field: "archived", Operator: index.FilterOperatorExists}},
+		},
+	}
+
+	require.NoError(t, filter.Validate())
+
+	require.True(t, filter.Match(map[string]string{"date": "2024-08-15", "department": "X"}))
+	require.True(t, filter.Match(map[string]string{"date": "2024-07-01T10:00:00Z", "department": "y"}))
+
+	require.False(t, filter.Match(map[string]string{"date": "2024-06-30", "department": "X"}))
+	require.False(t, filter.Match(map[string]string{"date": "2024-08-15", "department": "Z"}))
+	require.False(t, filter.Match(map[string]string{"date": "2024-08-15", "department": "X", "archived": "true"}))
+	require.False(t, filter.Match(map[string]string{"department": "X"}))
+}
+
+func TestFilterCompareNumbers(t *testing.T) {
+	filter := index.Filter{Field: "pages", Operator: index.FilterOperatorGreater, Value: "9"}
+
+	require.True(t, filter.Match(map[string]string{"pages": "10"}))
+	require.False(t, filter.Match(map[string]string{"pages": "9"}))
+}
+
+func TestFilterNegate(t *testing.T) {
+	filter := index.Filter{
+		Or: []index.Filter{
+			{Field: "a", Operator: index.FilterOperatorEqual, Value: "1"},
+			{Field: "b", Operator: index.FilterOperatorLess, Value: "5"},
+		},
+	}
+
+	negated, err := filter.Negate()
+	require.NoError(t, err)
+
+	require.Equal(t, index.Filter{
+		And: []index.Filter{
+			{Field: "a", Operator: index.FilterOperatorNotEqual, Value: "1"},
+			{Field: "b", Operator: index.FilterOperatorGreaterEqual, Value: "5"},
+		},
+	}, negated)
+
+	_, err = index.Filter{Field: "a", Operator: index.FilterOperatorExists}.Negate()
+	require.ErrorIs(t, err, index.ErrUnsupportedFilter)
+}
+
+func TestQueryOptionsExpression(t *testing.T) {
+	require.Nil(t, (&index.QueryOptions{}).Expression())
+
+	options := &index.QueryOptions{
+		Filters: map[string]string{"a": "1"},
+		Filter:  &index.Filter{Field: "b", Operator: index.FilterOperatorExists},
+	}
+
+	require.Len(t, options.Expression().And, 2)
+}
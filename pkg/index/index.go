@@ -2,6 +2,9 @@ package index
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
 
 	"github.com/adrianliechti/wingman/pkg/provider"
 )
@@ -23,7 +26,32 @@ type ListOptions struct {
 type QueryOptions struct {
 	Limit *int
 
+	Mode QueryMode
+
+	// Filters restricts the results to documents with matching metadata values
 	Filters map[string]string
+
+	// Filter restricts the results using a filter expression, combined with Filters
+	Filter *Filter
+}
+
+type QueryMode string
+
+const (
+	QueryModeVector  QueryMode = "vector"
+	QueryModeKeyword QueryMode = "keyword"
+	QueryModeHybrid  QueryMode = "hybrid"
+)
+
+var ErrUnsupportedMode = errors.New("unsupported query mode")
+
+// CheckMode returns ErrUnsupportedMode if a mode is requested that is not one of the supported ones
+func (o *QueryOptions) CheckMode(supported ...QueryMode) error {
+	if o == nil || o.Mode == "" || slices.Contains(supported, o.Mode) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", ErrUnsupportedMode, o.Mode)
 }
 
 type Page[T Document] struct {
@@ -0,0 +1,24 @@
+package index_test
+
+import (
+	"testing"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckMode(t *testing.T) {
+	var options *index.QueryOptions
+	require.NoError(t, options.CheckMode(index.QueryModeVector))
+
+	options = &index.QueryOptions{}
+	require.NoError(t, options.CheckMode(index.QueryModeVector))
+
+	options.Mode = index.QueryModeVector
+	require.NoError(t, options.CheckMode(index.QueryModeVector))
+
+	options.Mode = index.QueryModeHybrid
+	require.ErrorIs(t, options.CheckMode(index.QueryModeVector), index.ErrUnsupportedMode)
+	require.EqualError(t, options.CheckMode(index.QueryModeVector, index.QueryModeKeyword), "unsupported query mode: hybrid")
+}
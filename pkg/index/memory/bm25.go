@@ -0,0 +1,141 @@
+package memory
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// bm25 is an inverted keyword index scoring documents with Okapi BM25
+type bm25 struct {
+	postings map[string]map[string]int
+
+	terms   map[string][]string
+	lengths map[string]int
+
+	total int
+}
+
+func newBM25() *bm25 {
+	return &bm25{
+		postings: make(map[string]map[string]int),
+
+		terms:   make(map[string][]string),
+		lengths: make(map[string]int),
+	}
+}
+
+func (b *bm25) Insert(id string, text string) {
+	b.Delete(id)
+
+	tokens := tokenize(text)
+
+	for _, t := range tokens {
+		docs, ok := b.postings[t]
+
+		if !ok {
+			docs = make(map[string]int)
+			b.postings[t] = docs
+		}
+
+		if docs[id] == 0 {
+			b.terms[id] = append(b.terms[id], t)
+		}
+
+		docs[id]++
+	}
+
+	b.lengths[id] = len(tokens)
+	b.total += len(tokens)
+}
+
+func (b *bm25) Delete(id string) {
+	length, ok := b.lengths[id]
+
+	if !ok {
+		return
+	}
+
+	for _, t := range b.terms[id] {
+		docs := b.postings[t]
+
+		delete(docs, id)
+
+		if len(docs) == 0 {
+			delete(b.postings, t)
+		}
+	}
+
+	delete(b.terms, id)
+	delete(b.lengths, id)
+	b.total -= length
+}
+
+// Search returns the BM25 score of every document containing at least one query term
+func (b *bm25) Search(query string) map[string]float32 {
+	scores := make(map[string]float32)
+
+	n := len(b.lengths)
+
+	if n == 0 {
+		return scores
+	}
+
+	avgdl := float64(b.total) / float64(n)
+
+	seen := make(map[string]bool)
+
+	for _, t := range tokenize(query) {
+		if seen[t] {
+			continue
+		}
+
+		seen[t] = true
+
+		docs, ok := b.postings[t]
+
+		if !ok {
+			continue
+		}
+
+		idf := math.Log(1 + (float64(n)-float64(len(docs))+0.5)/(float64(len(docs))+0.5))
+
+		for id, tf := range docs {
+			f := float64(tf)
+			dl := float64(b.lengths[id])
+
+			scores[id] += float32(idf * (f * (bm25K1 + 1)) / (f + bm25K1*(1-bm25B+bm25B*dl/avgdl)))
+		}
+	}
+
+	return scores
+}
+
+// tokenize splits text into lower-case words. Words joined by '-', '_', '.' or '/' are kept
+// as a whole in addition to their parts, so identifiers like error codes match exactly.
+func tokenize(text string) []string {
+	var result []string
+
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("-_./", r)
+	})
+
+	for _, f := range fields {
+		parts := strings.FieldsFunc(f, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+
+		result = append(result, parts...)
+
+		if len(parts) > 1 {
+			result = append(result, strings.Trim(f, "-_./"))
+		}
+	}
+
+	return result
+}
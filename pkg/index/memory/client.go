@@ -5,9 +5,10 @@ import (
 	"errors"
 	"math"
 	"sort"
-	"strings"
+	"sync"
 
 	"github.com/adrianliechti/wingman/pkg/index"
+	"github.com/adrianliechti/wingman/pkg/to"
 
 	"github.com/google/uuid"
 )
@@ -15,15 +16,34 @@ import (
 var _ index.Provider = &Provider{}
 
 type Provider struct {
+	path string
+
 	embedder index.Embedder
-	reranker index.Reranker
 
+	hnsw *hnswConfig
+
+	mu        sync.RWMutex
 	documents map[string]index.Document
+
+	// changes counts the modifications, saved the ones already in the snapshot file
+	changes uint64
+	saved   uint64
+
+	dirty   chan struct{}
+	done    chan struct{}
+	stopped chan struct{}
+
+	closeOnce sync.Once
+
+	graph    *hnsw
+	keywords *bm25
 }
 
 func New(options ...Option) (*Provider, error) {
 	p := &Provider{
 		documents: make(map[string]index.Document),
+
+		keywords: newBM25(),
 	}
 
 	for _, option := range options {
@@ -34,10 +54,39 @@ func New(options ...Option) (*Provider, error) {
 		return nil, errors.New("embedder is required")
 	}
 
+	if p.hnsw != nil {
+		p.graph = newHNSW(p.hnsw.m, p.hnsw.efConstruction, p.hnsw.efSearch)
+	}
+
+	if p.path != "" {
+		if err := p.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, d := range p.documents {
+		p.keywords.Insert(d.ID, d.Title+"\n"+d.Content)
+
+		if p.graph != nil {
+			p.graph.Insert(d.ID, d.Embedding)
+		}
+	}
+
+	if p.path != "" {
+		p.dirty = make(chan struct{}, 1)
+		p.done = make(chan struct{})
+		p.stopped = make(chan struct{})
+
+		go p.snapshots()
+	}
+
 	return p, nil
 }
 
 func (p *Provider) List(ctx context.Context, options *index.ListOptions) (*index.Page[index.Document], error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
 	items := make([]index.Document, 0, len(p.documents))
 
 	for _, d := range p.documents {
@@ -52,6 +101,8 @@ func (p *Provider) List(ctx context.Context, options *index.ListOptions) (*index
 }
 
 func (p *Provider) Index(ctx context.Context, documents ...index.Document) error {
+	result := make([]index.Document, 0, len(documents))
+
 	for _, d := range documents {
 		if d.ID == "" {
 			d.ID = uuid.NewString()
@@ -71,17 +122,41 @@ func (p *Provider) Index(ctx context.Context, documents ...index.Document) error
 			continue
 		}
 
+		result = append(result, d)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, d := range result {
 		p.documents[d.ID] = d
+		p.keywords.Insert(d.ID, d.Title+"\n"+d.Content)
+
+		if p.graph != nil {
+			p.graph.Insert(d.ID, d.Embedding)
+		}
 	}
 
+	p.schedule()
+
 	return nil
 }
 
 func (p *Provider) Delete(ctx context.Context, ids ...string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	for _, id := range ids {
 		delete(p.documents, id)
+		p.keywords.Delete(id)
+
+		if p.graph != nil {
+			p.graph.Delete(id)
+		}
 	}
 
+	p.schedule()
+
 	return nil
 }
 
@@ -90,57 +165,212 @@ func (p *Provider) Query(ctx context.Context, query string, options *index.Query
 		options = &index.QueryOptions{}
 	}
 
-	if p.embedder == nil {
-		return nil, errors.New("no embedder configured")
+	var embedding []float32
+
+	if options.Mode != index.QueryModeKeyword {
+		if p.embedder == nil {
+			return nil, errors.New("no embedder configured")
+		}
+
+		result, err := p.embedder.Embed(ctx, []string{query})
+
+		if err != nil {
+			return nil, err
+		}
+
+		embedding = result.Embeddings[0]
 	}
 
-	embedding, err := p.embedder.Embed(ctx, []string{query})
+	filter := options.Expression()
+
+	if filter != nil {
+		if err := filter.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var results []index.Result
+
+	switch options.Mode {
+	case "", index.QueryModeVector:
+		results = p.vectorSearch(embedding, options.Limit, filter)
+
+	case index.QueryModeKeyword:
+		results = p.keywordSearch(query, filter)
+
+	case index.QueryModeHybrid:
+		// each ranking contributes more candidates than requested, so documents ranked well by both win
+		var depth *int
+
+		if options.Limit != nil {
+			depth = to.Ptr(max(*options.Limit*4, 50))
+		}
+
+		vector := truncate(p.vectorSearch(embedding, depth, filter), depth)
+		keyword := truncate(p.keywordSearch(query, filter), depth)
+
+		results = fuseRankings(vector, keyword)
+
+	default:
+		return nil, errors.New("unsupported query mode: " + string(options.Mode))
+	}
+
+	return truncate(results, options.Limit), nil
+}
+
+// vectorSearch returns the documents sorted by cosine similarity to the embedding
+func (p *Provider) vectorSearch(embedding []float32, limit *int, filter *index.Filter) []index.Result {
+	var results []index.Result
+
+	if p.graph != nil && limit != nil {
+		results = p.search(embedding, *limit, filter)
+	}
 
-	if err != nil {
-		return nil, err
+	if results == nil {
+		results = p.scan(embedding, filter)
 	}
 
+	sortResults(results)
+
+	return results
+}
+
+// keywordSearch returns the documents matching the query terms sorted by their BM25 score
+func (p *Provider) keywordSearch(query string, filter *index.Filter) []index.Result {
 	results := make([]index.Result, 0)
 
-DOCUMENTS:
-	for _, d := range p.documents {
-		score := cosineSimilarity(embedding.Embeddings[0], d.Embedding)
+	for id, score := range p.keywords.Search(query) {
+		d, ok := p.documents[id]
 
-		r := index.Result{
+		if !ok || !matchFilter(d, filter) {
+			continue
+		}
+
+		results = append(results, index.Result{
 			Score:    score,
 			Document: d,
+		})
+	}
+
+	sortResults(results)
+
+	return results
+}
+
+// scan compares the query against every document
+func (p *Provider) scan(embedding []float32, filter *index.Filter) []index.Result {
+	results := make([]index.Result, 0)
+
+	for _, d := range p.documents {
+		if !matchFilter(d, filter) {
+			continue
 		}
 
-		for k, v := range options.Filters {
-			val, ok := d.Metadata[k]
+		results = append(results, index.Result{
+			Score:    cosineSimilarity(embedding, d.Embedding),
+			Document: d,
+		})
+	}
+
+	return results
+}
+
+// search looks up the nearest neighbours in the graph. Deleted and filtered out nodes shorten the result,
+// so the search is widened until enough documents match and returns nil to fall back to a scan otherwise.
+func (p *Provider) search(embedding []float32, limit int, filter *index.Filter) []index.Result {
+	k := limit
 
-			if !ok {
-				continue DOCUMENTS
-			}
+	if filter != nil {
+		k = limit * 10
+	}
+
+	for range 3 {
+		ids, scores := p.graph.Search(embedding, k)
+
+		results := make([]index.Result, 0, limit)
 
-			if !strings.EqualFold(v, val) {
-				continue DOCUMENTS
+		for i, id := range ids {
+			d, ok := p.documents[id]
+
+			if !ok || !matchFilter(d, filter) {
+				continue
 			}
+
+			results = append(results, index.Result{
+				Score:    scores[i],
+				Document: d,
+			})
+		}
+
+		if len(results) >= limit || len(results) == len(p.documents) {
+			return results
 		}
 
-		results = append(results, r)
+		if k >= len(p.documents) {
+			break
+		}
+
+		k *= 4
+	}
+
+	return nil
+}
+
+// fuseRankings merges sorted result lists using reciprocal rank fusion
+func fuseRankings(rankings ...[]index.Result) []index.Result {
+	const k = 60
+
+	scores := make(map[string]float32)
+	documents := make(map[string]index.Document)
+
+	for _, ranking := range rankings {
+		for rank, r := range ranking {
+			scores[r.ID] += 1 / float32(k+rank+1)
+			documents[r.ID] = r.Document
+		}
 	}
 
-	sort.Slice(results, func(i, j int) bool {
+	results := make([]index.Result, 0, len(scores))
+
+	for id, score := range scores {
+		results = append(results, index.Result{
+			Score:    score,
+			Document: documents[id],
+		})
+	}
+
+	sortResults(results)
+
+	return results
+}
+
+func sortResults(results []index.Result) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score == results[j].Score {
+			return results[i].ID < results[j].ID
+		}
+
 		return results[i].Score > results[j].Score
 	})
+}
 
-	if options.Limit != nil {
-		limit := *options.Limit
+func truncate(results []index.Result, limit *int) []index.Result {
+	if limit == nil || *limit >= len(results) {
+		return results
+	}
 
-		if limit > len(results) {
-			limit = len(results)
-		}
+	return results[:*limit]
+}
 
-		results = results[:limit]
+func matchFilter(d index.Document, filter *index.Filter) bool {
+	if filter == nil {
+		return true
 	}
 
-	return results, nil
+	return filter.Match(d.Metadata)
 }
 
 func cosineSimilarity(a []float32, b []float32) float32 {
@@ -159,8 +389,8 @@ func cosineSimilarity(a []float32, b []float32) float32 {
 
 		dotproduct += valA * valB
 
-		magnitudeA += math.Pow(valA, 2)
-		magnitudeB += math.Pow(valB, 2)
+		magnitudeA += valA * valA
+		magnitudeB += valB * valB
 	}
 
 	if magnitudeA == 0 || magnitudeB == 0 {
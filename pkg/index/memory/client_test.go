@@ -1,9 +1,19 @@
 package memory_test
 
 import (
+	"context"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
 	"testing"
+	"time"
 
+	"github.com/adrianliechti/wingman/pkg/index"
 	"github.com/adrianliechti/wingman/pkg/index/memory"
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/to"
 	"github.com/adrianliechti/wingman/test"
 
 	"github.com/stretchr/testify/require"
@@ -17,3 +27,183 @@ func TestMemory(t *testing.T) {
 
 	test.TestIndex(t, context, c)
 }
+
+func TestSnapshot(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "index.json")
+
+	c, err := memory.New(memory.WithEmbedder(&staticEmbedder{}), memory.WithPath(path))
+	require.NoError(t, err)
+
+	err = c.Index(ctx,
+		index.Document{ID: "1", Content: "first", Embedding: []float32{1, 0}},
+		index.Document{ID: "2", Content: "second", Embedding: []float32{0, 1}},
+	)
+	require.NoError(t, err)
+
+	err = c.Delete(ctx, "2")
+	require.NoError(t, err)
+
+	err = c.Close()
+	require.NoError(t, err)
+
+	r, err := memory.New(memory.WithEmbedder(&staticEmbedder{}), memory.WithPath(path))
+	require.NoError(t, err)
+
+	page, err := r.List(ctx, nil)
+	require.NoError(t, err)
+
+	require.Len(t, page.Items, 1)
+	require.Equal(t, "first", page.Items[0].Content)
+}
+
+func TestSnapshotBackground(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "index.json")
+
+	c, err := memory.New(memory.WithEmbedder(&staticEmbedder{}), memory.WithPath(path))
+	require.NoError(t, err)
+
+	defer c.Close()
+
+	err = c.Index(ctx, index.Document{ID: "1", Content: "first", Embedding: []float32{1, 0}})
+	require.NoError(t, err)
+
+	// the file is read directly, a second instance on the same path would race the first one
+	require.Eventually(t, func() bool {
+		data, err := os.ReadFile(path)
+
+		if err != nil {
+			return false
+		}
+
+		var s struct {
+			Documents []index.Document `json:"documents"`
+		}
+
+		return json.Unmarshal(data, &s) == nil && len(s.Documents) == 1
+	}, 5*time.Second, 100*time.Millisecond)
+}
+
+func TestSnapshotUnchanged(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "index.json")
+
+	c, err := memory.New(memory.WithEmbedder(&staticEmbedder{}), memory.WithPath(path))
+	require.NoError(t, err)
+
+	// an instance without changes must not overwrite the snapshot of another one on close
+	r, err := memory.New(memory.WithEmbedder(&staticEmbedder{}), memory.WithPath(path))
+	require.NoError(t, err)
+
+	err = c.Index(ctx, index.Document{ID: "1", Content: "first", Embedding: []float32{1, 0}})
+	require.NoError(t, err)
+
+	require.NoError(t, c.Close())
+	require.NoError(t, r.Close())
+
+	r, err = memory.New(memory.WithEmbedder(&staticEmbedder{}), memory.WithPath(path))
+	require.NoError(t, err)
+
+	defer r.Close()
+
+	page, err := r.List(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+}
+
+type staticEmbedder struct{}
+
+func (e *staticEmbedder) Embed(ctx context.Context, texts []string) (*provider.Embedding, error) {
+	result := &provider.Embedding{}
+
+	for range texts {
+		result.Embeddings = append(result.Embeddings, []float32{1, 0})
+	}
+
+	return result, nil
+}
+
+func TestHybrid(t *testing.T) {
+	ctx := context.Background()
+
+	c, err := memory.New(memory.WithEmbedder(&staticEmbedder{}))
+	require.NoError(t, err)
+
+	err = c.Index(ctx,
+		index.Document{ID: "1", Content: "The printer reports an unknown failure", Embedding: []float32{1, 0}},
+		index.Document{ID: "2", Content: "Error ERR-4711 occurs when the toner is empty", Embedding: []float32{0, 1}},
+		index.Document{ID: "3", Content: "Restart the printer to clear the queue", Embedding: []float32{1, 1}},
+	)
+	require.NoError(t, err)
+
+	results, err := c.Query(ctx, "what does err-4711 mean", &index.QueryOptions{
+		Limit: to.Ptr(1),
+		Mode:  index.QueryModeKeyword,
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "2", results[0].ID)
+
+	results, err = c.Query(ctx, "what does err-4711 mean", &index.QueryOptions{
+		Mode: index.QueryModeHybrid,
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	require.Equal(t, "2", results[0].ID)
+	require.Equal(t, "1", results[1].ID)
+}
+
+func TestHNSWDeleted(t *testing.T) {
+	ctx := context.Background()
+
+	c, err := memory.New(memory.WithEmbedder(&staticEmbedder{}), memory.WithHNSW(4, 16, 8))
+	require.NoError(t, err)
+
+	var documents []index.Document
+
+	for i := range 200 {
+		angle := float64(i) / 200 * math.Pi / 2
+
+		documents = append(documents, index.Document{
+			ID:      strconv.Itoa(i),
+			Content: strconv.Itoa(i),
+
+			Metadata: map[string]string{
+				"even": strconv.FormatBool(i%2 == 0),
+			},
+
+			Embedding: []float32{float32(math.Cos(angle)), float32(math.Sin(angle))},
+		})
+	}
+
+	err = c.Index(ctx, documents...)
+	require.NoError(t, err)
+
+	// tombstones the documents closest to the query
+	var deleted []string
+
+	for i := range 80 {
+		deleted = append(deleted, strconv.Itoa(i))
+	}
+
+	err = c.Delete(ctx, deleted...)
+	require.NoError(t, err)
+
+	results, err := c.Query(ctx, "query", &index.QueryOptions{
+		Limit: to.Ptr(10),
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 10)
+
+	results, err = c.Query(ctx, "query", &index.QueryOptions{
+		Limit:   to.Ptr(10),
+		Filters: map[string]string{"even": "true"},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 10)
+
+	for _, r := range results {
+		require.Equal(t, "true", r.Metadata["even"])
+	}
+}
@@ -12,8 +12,27 @@ func WithEmbedder(embedder index.Embedder) Option {
 	}
 }
 
-func WithReranker(reranker index.Reranker) Option {
+func WithPath(path string) Option {
 	return func(p *Provider) {
-		p.reranker = reranker
+		p.path = path
+	}
+}
+
+type hnswConfig struct {
+	m              int
+	efConstruction int
+	efSearch       int
+}
+
+// WithHNSW enables approximate nearest neighbour search using a HNSW graph.
+// m is the number of connections per node, efConstruction and efSearch the size of the candidate lists
+// while building and querying the graph. Larger values improve recall at the cost of speed; zero selects the default.
+func WithHNSW(m, efConstruction, efSearch int) Option {
+	return func(p *Provider) {
+		p.hnsw = &hnswConfig{
+			m:              m,
+			efConstruction: efConstruction,
+			efSearch:       efSearch,
+		}
 	}
 }
@@ -0,0 +1,384 @@
+package memory
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+)
+
+// hnsw is a hierarchical navigable small world graph for approximate nearest neighbour search.
+// Vectors are normalized on insert so the cosine distance reduces to a dot product.
+// Deleted nodes are only marked and stay in the graph for traversal until it is rebuilt.
+type hnsw struct {
+	m              int
+	efConstruction int
+	efSearch       int
+
+	ml float64
+
+	nodes []*hnswNode
+	ids   map[string]int
+
+	entry    int
+	maxLevel int
+
+	deleted int
+}
+
+type hnswNode struct {
+	id     string
+	vector []float32
+
+	deleted bool
+
+	neighbors [][]int
+}
+
+func newHNSW(m, efConstruction, efSearch int) *hnsw {
+	if m <= 1 {
+		m = 16
+	}
+
+	if efConstruction <= 0 {
+		efConstruction = 200
+	}
+
+	if efSearch <= 0 {
+		efSearch = 64
+	}
+
+	return &hnsw{
+		m:              m,
+		efConstruction: efConstruction,
+		efSearch:       efSearch,
+
+		ml: 1 / math.Log(float64(m)),
+
+		ids: make(map[string]int),
+
+		entry: -1,
+	}
+}
+
+func (h *hnsw) Insert(id string, vector []float32) {
+	// an update leaves a tombstone of the previous vector, just like a delete
+	h.remove(id)
+	h.compact()
+
+	level := int(math.Floor(-math.Log(1-rand.Float64()) * h.ml))
+
+	n := len(h.nodes)
+
+	node := &hnswNode{
+		id:     id,
+		vector: normalize(vector),
+
+		neighbors: make([][]int, level+1),
+	}
+
+	h.nodes = append(h.nodes, node)
+	h.ids[id] = n
+
+	if h.entry < 0 {
+		h.entry = n
+		h.maxLevel = level
+		return
+	}
+
+	ep := h.entry
+
+	for l := h.maxLevel; l > level; l-- {
+		ep = h.searchLayer(node.vector, ep, 1, l)[0].node
+	}
+
+	for l := min(level, h.maxLevel); l >= 0; l-- {
+		candidates := h.searchLayer(node.vector, ep, h.efConstruction, l)
+
+		neighbors := make([]int, 0, h.m)
+
+		for _, c := range candidates {
+			if len(neighbors) >= h.m {
+				break
+			}
+
+			neighbors = append(neighbors, c.node)
+		}
+
+		node.neighbors[l] = neighbors
+
+		for _, nb := range neighbors {
+			h.link(nb, n, l)
+		}
+
+		ep = candidates[0].node
+	}
+
+	if level > h.maxLevel {
+		h.entry = n
+		h.maxLevel = level
+	}
+}
+
+func (h *hnsw) Delete(id string) {
+	h.remove(id)
+	h.compact()
+}
+
+// compact rebuilds the graph once most of it consists of tombstones
+func (h *hnsw) compact() {
+	if h.deleted > 0 && h.deleted*2 > len(h.nodes) {
+		h.rebuild()
+	}
+}
+
+// Search returns up to k document ids with their cosine similarity, best first
+func (h *hnsw) Search(vector []float32, k int) ([]string, []float32) {
+	if h.entry < 0 || k <= 0 {
+		return nil, nil
+	}
+
+	query := normalize(vector)
+
+	ep := h.entry
+
+	for l := h.maxLevel; l > 0; l-- {
+		ep = h.searchLayer(query, ep, 1, l)[0].node
+	}
+
+	candidates := h.searchLayer(query, ep, max(h.efSearch, k), 0)
+
+	var ids []string
+	var scores []float32
+
+	for _, c := range candidates {
+		if len(ids) >= k {
+			break
+		}
+
+		node := h.nodes[c.node]
+
+		if node.deleted {
+			continue
+		}
+
+		ids = append(ids, node.id)
+		scores = append(scores, 1-c.distance)
+	}
+
+	return ids, scores
+}
+
+func (h *hnsw) remove(id string) {
+	n, ok := h.ids[id]
+
+	if !ok {
+		return
+	}
+
+	delete(h.ids, id)
+
+	h.nodes[n].deleted = true
+	h.deleted++
+}
+
+func (h *hnsw) rebuild() {
+	nodes := h.nodes
+
+	h.nodes = nil
+	h.ids = make(map[string]int)
+
+	h.entry = -1
+	h.maxLevel = 0
+
+	h.deleted = 0
+
+	for _, node := range nodes {
+		if node.deleted {
+			continue
+		}
+
+		h.Insert(node.id, node.vector)
+	}
+}
+
+// link adds a connection from node a to node b and prunes a's neighbours to the closest ones
+func (h *hnsw) link(a, b, level int) {
+	node := h.nodes[a]
+
+	if level >= len(node.neighbors) {
+		return
+	}
+
+	node.neighbors[level] = append(node.neighbors[level], b)
+
+	limit := h.m
+
+	if level == 0 {
+		limit = h.m * 2
+	}
+
+	if len(node.neighbors[level]) <= limit {
+		return
+	}
+
+	candidates := &hnswQueue{
+		items: make([]hnswItem, 0, len(node.neighbors[level])),
+	}
+
+	for _, nb := range node.neighbors[level] {
+		candidates.items = append(candidates.items, hnswItem{
+			node:     nb,
+			distance: distance(node.vector, h.nodes[nb].vector),
+		})
+	}
+
+	heap.Init(candidates)
+
+	neighbors := make([]int, 0, limit)
+
+	for len(neighbors) < limit {
+		neighbors = append(neighbors, heap.Pop(candidates).(hnswItem).node)
+	}
+
+	node.neighbors[level] = neighbors
+}
+
+// searchLayer performs a greedy beam search on one layer and returns the ef closest nodes, closest first
+func (h *hnsw) searchLayer(query []float32, entry int, ef int, level int) []hnswItem {
+	visited := map[int]bool{
+		entry: true,
+	}
+
+	start := hnswItem{
+		node:     entry,
+		distance: distance(query, h.nodes[entry].vector),
+	}
+
+	candidates := &hnswQueue{
+		items: []hnswItem{start},
+	}
+
+	results := &hnswQueue{
+		items: []hnswItem{start},
+		max:   true,
+	}
+
+	for candidates.Len() > 0 {
+		c := heap.Pop(candidates).(hnswItem)
+
+		if c.distance > results.peek().distance && results.Len() >= ef {
+			break
+		}
+
+		node := h.nodes[c.node]
+
+		if level >= len(node.neighbors) {
+			continue
+		}
+
+		for _, nb := range node.neighbors[level] {
+			if visited[nb] {
+				continue
+			}
+
+			visited[nb] = true
+
+			d := distance(query, h.nodes[nb].vector)
+
+			if results.Len() < ef || d < results.peek().distance {
+				heap.Push(candidates, hnswItem{node: nb, distance: d})
+				heap.Push(results, hnswItem{node: nb, distance: d})
+
+				if results.Len() > ef {
+					heap.Pop(results)
+				}
+			}
+		}
+	}
+
+	items := make([]hnswItem, results.Len())
+
+	for i := len(items) - 1; i >= 0; i-- {
+		items[i] = heap.Pop(results).(hnswItem)
+	}
+
+	return items
+}
+
+type hnswItem struct {
+	node     int
+	distance float32
+}
+
+// hnswQueue is a min-heap by distance, or a max-heap if max is set
+type hnswQueue struct {
+	items []hnswItem
+	max   bool
+}
+
+func (q hnswQueue) Len() int {
+	return len(q.items)
+}
+
+func (q hnswQueue) Less(i, j int) bool {
+	if q.max {
+		return q.items[i].distance > q.items[j].distance
+	}
+
+	return q.items[i].distance < q.items[j].distance
+}
+
+func (q hnswQueue) Swap(i, j int) {
+	q.items[i], q.items[j] = q.items[j], q.items[i]
+}
+
+func (q *hnswQueue) Push(x any) {
+	q.items = append(q.items, x.(hnswItem))
+}
+
+func (q *hnswQueue) Pop() any {
+	n := len(q.items)
+	item := q.items[n-1]
+	q.items = q.items[:n-1]
+	return item
+}
+
+func (q hnswQueue) peek() hnswItem {
+	return q.items[0]
+}
+
+func normalize(v []float32) []float32 {
+	var sum float64
+
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+
+	result := make([]float32, len(v))
+
+	if sum == 0 {
+		return result
+	}
+
+	norm := float32(math.Sqrt(sum))
+
+	for i, x := range v {
+		result[i] = x / norm
+	}
+
+	return result
+}
+
+func distance(a, b []float32) float32 {
+	if len(a) != len(b) {
+		return 1
+	}
+
+	var dot float32
+
+	for i := range a {
+		dot += a[i] * b[i]
+	}
+
+	return 1 - dot
+}
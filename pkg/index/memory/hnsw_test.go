@@ -0,0 +1,116 @@
+package memory
+
+import (
+	"math/rand"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+func TestHNSWRecall(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	vectors := make(map[string][]float32)
+
+	g := newHNSW(16, 200, 64)
+
+	for i := 0; i < 2000; i++ {
+		id := strconv.Itoa(i)
+		v := randomVector(r, 32)
+
+		vectors[id] = v
+		g.Insert(id, v)
+	}
+
+	for i := 0; i < 500; i++ {
+		id := strconv.Itoa(i)
+
+		delete(vectors, id)
+		g.Delete(id)
+	}
+
+	const k = 10
+
+	var hits int
+	var total int
+
+	for q := 0; q < 50; q++ {
+		query := randomVector(r, 32)
+
+		type item struct {
+			id    string
+			score float32
+		}
+
+		var exact []item
+
+		for id, v := range vectors {
+			exact = append(exact, item{id, cosineSimilarity(query, v)})
+		}
+
+		sort.Slice(exact, func(i, j int) bool { return exact[i].score > exact[j].score })
+
+		expected := make(map[string]bool)
+
+		for _, e := range exact[:k] {
+			expected[e.id] = true
+		}
+
+		ids, _ := g.Search(query, k)
+
+		for _, id := range ids {
+			if _, ok := vectors[id]; !ok {
+				t.Fatalf("deleted document %s returned", id)
+			}
+
+			if expected[id] {
+				hits++
+			}
+		}
+
+		total += k
+	}
+
+	recall := float64(hits) / float64(total)
+
+	if recall < 0.9 {
+		t.Fatalf("recall too low: %.2f", recall)
+	}
+}
+
+func TestHNSWUpdate(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	g := newHNSW(16, 200, 64)
+
+	for i := 0; i < 100; i++ {
+		g.Insert(strconv.Itoa(i), randomVector(r, 8))
+	}
+
+	// updates leave tombstones, which are compacted like the ones of deletes
+	for round := 0; round < 10; round++ {
+		for i := 0; i < 100; i++ {
+			g.Insert(strconv.Itoa(i), randomVector(r, 8))
+		}
+	}
+
+	if len(g.nodes) > 200 {
+		t.Fatalf("graph not compacted: %d nodes for 100 documents", len(g.nodes))
+	}
+
+	ids, _ := g.Search(randomVector(r, 8), 100)
+
+	if len(ids) != 100 {
+		t.Fatalf("expected 100 results, got %d", len(ids))
+	}
+}
+
+func randomVector(r *rand.Rand, dim int) []float32 {
+	v := make([]float32, dim)
+
+	for i := range v {
+		v[i] = r.Float32()*2 - 1
+	}
+
+	return v
+}
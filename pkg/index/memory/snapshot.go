@@ -0,0 +1,163 @@
+package memory
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+)
+
+// snapshotDelay collects the changes of bulk ingestion into a single snapshot
+const snapshotDelay = time.Second
+
+type snapshot struct {
+	Documents []index.Document `json:"documents"`
+}
+
+// Close writes the pending changes to the snapshot file and stops the background snapshots.
+// Without changes the file is left alone, so a newer snapshot of another instance is kept.
+func (p *Provider) Close() error {
+	if p.path == "" {
+		return nil
+	}
+
+	var err error
+
+	p.closeOnce.Do(func() {
+		close(p.done)
+		<-p.stopped
+
+		p.mu.RLock()
+		pending := p.changes != p.saved
+		p.mu.RUnlock()
+
+		if pending {
+			err = p.save()
+		}
+	})
+
+	return err
+}
+
+// schedule marks the documents as changed for the next background snapshot, called while holding the lock
+func (p *Provider) schedule() {
+	if p.dirty == nil {
+		return
+	}
+
+	p.changes++
+	p.notify()
+}
+
+// notify wakes up the background snapshots
+func (p *Provider) notify() {
+	select {
+	case p.dirty <- struct{}{}:
+	default:
+	}
+}
+
+// snapshots writes the documents in the background after they changed, failed snapshots are retried
+func (p *Provider) snapshots() {
+	defer close(p.stopped)
+
+	for {
+		select {
+		case <-p.dirty:
+		case <-p.done:
+			return
+		}
+
+		timer := time.NewTimer(snapshotDelay)
+
+		select {
+		case <-timer.C:
+		case <-p.done:
+			timer.Stop()
+			return
+		}
+
+		if err := p.save(); err != nil {
+			p.notify()
+		}
+	}
+}
+
+// load restores the documents from the snapshot file, if present
+func (p *Provider) load() error {
+	data, err := os.ReadFile(p.path)
+
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	var s snapshot
+
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	for _, d := range s.Documents {
+		p.documents[d.ID] = d
+	}
+
+	return nil
+}
+
+// save writes all documents to the snapshot file, holding the lock only while copying them
+func (p *Provider) save() error {
+	p.mu.RLock()
+
+	changes := p.changes
+
+	s := snapshot{
+		Documents: make([]index.Document, 0, len(p.documents)),
+	}
+
+	for _, d := range p.documents {
+		s.Documents = append(s.Documents, d)
+	}
+
+	p.mu.RUnlock()
+
+	dir := filepath.Dir(p.path)
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(dir, filepath.Base(p.path)+".*.tmp")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(f.Name())
+
+	if err := json.NewEncoder(f).Encode(s); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	// rename is atomic, so a crash never leaves a partially written snapshot behind
+	if err := os.Rename(f.Name(), p.path); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.saved = changes
+	p.mu.Unlock()
+
+	return nil
+}
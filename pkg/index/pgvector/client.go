@@ -0,0 +1,416 @@
+package pgvector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var _ index.Provider = &Client{}
+
+type method string
+
+const (
+	methodHNSW    method = "hnsw"
+	methodIVFFlat method = "ivfflat"
+)
+
+type Client struct {
+	pool *pgxpool.Pool
+
+	namespace string
+
+	embedder index.Embedder
+
+	method method
+
+	m              int
+	efConstruction int
+	efSearch       int
+
+	lists  int
+	probes int
+
+	mu    sync.Mutex
+	ready bool
+}
+
+func New(url string, namespace string, options ...Option) (*Client, error) {
+	c := &Client{
+		namespace: namespace,
+
+		method: methodHNSW,
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	if c.embedder == nil {
+		return nil, errors.New("embedder is required")
+	}
+
+	if c.namespace == "" {
+		return nil, errors.New("namespace is required")
+	}
+
+	pool, err := pgxpool.New(context.Background(), url)
+
+	if err != nil {
+		return nil, err
+	}
+
+	c.pool = pool
+
+	return c, nil
+}
+
+func (c *Client) List(ctx context.Context, options *index.ListOptions) (*index.Page[index.Document], error) {
+	if options == nil {
+		options = new(index.ListOptions)
+	}
+
+	if err := c.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	sql := "SELECT id, title, source, content, metadata, embedding::text FROM " + c.table() + " WHERE id > $1 ORDER BY id"
+
+	args := []any{options.Cursor}
+
+	if options.Limit != nil {
+		// one extra row tells whether there is a next page
+		sql += " LIMIT $2"
+		args = append(args, *options.Limit+1)
+	}
+
+	rows, err := c.pool.Query(ctx, sql, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (index.Document, error) {
+		d, _, err := scanDocument(row, false)
+		return d, err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	page := index.Page[index.Document]{
+		Items: items,
+	}
+
+	if options.Limit != nil && len(items) > *options.Limit {
+		page.Items = items[:*options.Limit]
+		page.Cursor = page.Items[len(page.Items)-1].ID
+	}
+
+	return &page, nil
+}
+
+func (c *Client) Index(ctx context.Context, documents ...index.Document) error {
+	if len(documents) == 0 {
+		return nil
+	}
+
+	if err := c.ensureTable(ctx); err != nil {
+		return err
+	}
+
+	batch := &pgx.Batch{}
+
+	sql := "INSERT INTO " + c.table() + " (id, title, source, content, metadata, embedding) VALUES ($1, $2, $3, $4, $5::jsonb, $6::vector)" +
+		" ON CONFLICT (id) DO UPDATE SET title = EXCLUDED.title, source = EXCLUDED.source, content = EXCLUDED.content, metadata = EXCLUDED.metadata, embedding = EXCLUDED.embedding"
+
+	for _, d := range documents {
+		if d.ID == "" {
+			d.ID = uuid.NewString()
+		}
+
+		if len(d.Embedding) == 0 && c.embedder != nil {
+			embedding, err := c.embedder.Embed(ctx, []string{d.Content})
+
+			if err != nil {
+				return err
+			}
+
+			d.Embedding = embedding.Embeddings[0]
+		}
+
+		metadata := d.Metadata
+
+		if metadata == nil {
+			metadata = map[string]string{}
+		}
+
+		data, err := json.Marshal(metadata)
+
+		if err != nil {
+			return err
+		}
+
+		batch.Queue(sql, d.ID, d.Title, d.Source, d.Content, string(data), formatVector(d.Embedding))
+	}
+
+	return c.pool.SendBatch(ctx, batch).Close()
+}
+
+func (c *Client) Delete(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := c.ensureTable(ctx); err != nil {
+		return err
+	}
+
+	_, err := c.pool.Exec(ctx, "DELETE FROM "+c.table()+" WHERE id = ANY($1)", ids)
+	return err
+}
+
+func (c *Client) Query(ctx context.Context, query string, options *index.QueryOptions) ([]index.Result, error) {
+	if err := options.CheckMode(index.QueryModeVector); err != nil {
+		return nil, err
+	}
+
+	if options == nil {
+		options = new(index.QueryOptions)
+	}
+
+	limit := 10
+
+	if options.Limit != nil {
+		limit = *options.Limit
+	}
+
+	if err := c.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	embedding, err := c.embedder.Embed(ctx, []string{query})
+
+	if err != nil {
+		return nil, err
+	}
+
+	b := &filterBuilder{}
+
+	vector := b.arg(formatVector(embedding.Embeddings[0]))
+
+	sql := "SELECT id, title, source, content, metadata, embedding::text, 1 - (embedding <=> " + vector + "::vector) FROM " + c.table()
+
+	if f := options.Expression(); f != nil {
+		if err := f.Validate(); err != nil {
+			return nil, err
+		}
+
+		where, err := b.convertFilter(*f)
+
+		if err != nil {
+			return nil, err
+		}
+
+		sql += " WHERE " + where
+	}
+
+	sql += " ORDER BY embedding <=> " + vector + "::vector LIMIT " + b.arg(limit)
+
+	tx, err := c.pool.Begin(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	// search parameters only apply to this transaction, so pooled connections keep their defaults
+	if c.method == methodHNSW && c.efSearch > 0 {
+		if _, err := tx.Exec(ctx, "SET LOCAL hnsw.ef_search = "+strconv.Itoa(c.efSearch)); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.method == methodIVFFlat && c.probes > 0 {
+		if _, err := tx.Exec(ctx, "SET LOCAL ivfflat.probes = "+strconv.Itoa(c.probes)); err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := tx.Query(ctx, sql, b.args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (index.Result, error) {
+		d, score, err := scanDocument(row, true)
+
+		return index.Result{
+			Score:    score,
+			Document: d,
+		}, err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return results, tx.Commit(ctx)
+}
+
+// ensureTable creates the extension, the table and its indexes once per client
+func (c *Client) ensureTable(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ready {
+		return nil
+	}
+
+	embeddings, err := c.embedder.Embed(ctx, []string{"init"})
+
+	if err != nil {
+		return err
+	}
+
+	dimensions := len(embeddings.Embeddings[0])
+
+	var params []string
+
+	switch c.method {
+	case methodHNSW:
+		if c.m > 0 {
+			params = append(params, "m = "+strconv.Itoa(c.m))
+		}
+
+		if c.efConstruction > 0 {
+			params = append(params, "ef_construction = "+strconv.Itoa(c.efConstruction))
+		}
+
+	case methodIVFFlat:
+		if c.lists > 0 {
+			params = append(params, "lists = "+strconv.Itoa(c.lists))
+		}
+	}
+
+	vectorIndex := "CREATE INDEX IF NOT EXISTS " + c.identifier("embedding_idx") + " ON " + c.table() + " USING " + string(c.method) + " (embedding vector_cosine_ops)"
+
+	if len(params) > 0 {
+		vectorIndex += " WITH (" + strings.Join(params, ", ") + ")"
+	}
+
+	statements := []string{
+		"CREATE EXTENSION IF NOT EXISTS vector",
+
+		"CREATE TABLE IF NOT EXISTS " + c.table() + " (" +
+			"id TEXT PRIMARY KEY, " +
+			"title TEXT NOT NULL DEFAULT '', " +
+			"source TEXT NOT NULL DEFAULT '', " +
+			"content TEXT NOT NULL DEFAULT '', " +
+			"metadata JSONB NOT NULL DEFAULT '{}', " +
+			"embedding vector(" + strconv.Itoa(dimensions) + ") NOT NULL)",
+
+		vectorIndex,
+
+		"CREATE INDEX IF NOT EXISTS " + c.identifier("metadata_idx") + " ON " + c.table() + " USING gin (metadata)",
+	}
+
+	for _, sql := range statements {
+		if _, err := c.pool.Exec(ctx, sql); err != nil {
+			return err
+		}
+	}
+
+	c.ready = true
+	return nil
+}
+
+func (c *Client) table() string {
+	return pgx.Identifier{c.namespace}.Sanitize()
+}
+
+func (c *Client) identifier(suffix string) string {
+	return pgx.Identifier{c.namespace + "_" + suffix}.Sanitize()
+}
+
+func scanDocument(row pgx.CollectableRow, withScore bool) (index.Document, float32, error) {
+	var d index.Document
+
+	var embedding string
+	var score float32
+
+	dest := []any{&d.ID, &d.Title, &d.Source, &d.Content, &d.Metadata, &embedding}
+
+	if withScore {
+		dest = append(dest, &score)
+	}
+
+	if err := row.Scan(dest...); err != nil {
+		return d, 0, err
+	}
+
+	vector, err := parseVector(embedding)
+
+	if err != nil {
+		return d, 0, err
+	}
+
+	d.Embedding = vector
+
+	return d, score, nil
+}
+
+// formatVector encodes an embedding in the text representation of the pgvector type
+func formatVector(v []float32) string {
+	var sb strings.Builder
+
+	sb.WriteByte('[')
+
+	for i, x := range v {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+
+		sb.WriteString(strconv.FormatFloat(float64(x), 'f', -1, 32))
+	}
+
+	sb.WriteByte(']')
+
+	return sb.String()
+}
+
+func parseVector(s string) ([]float32, error) {
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "["), "]")
+
+	if s == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(s, ",")
+
+	result := make([]float32, len(parts))
+
+	for i, p := range parts {
+		x, err := strconv.ParseFloat(p, 32)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result[i] = float32(x)
+	}
+
+	return result, nil
+}
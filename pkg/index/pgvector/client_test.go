@@ -0,0 +1,43 @@
+package pgvector_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/index/pgvector"
+	"github.com/adrianliechti/wingman/test"
+
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func TestPGVector(t *testing.T) {
+	context := test.NewContext()
+
+	server, err := testcontainers.GenericContainer(context.Context, testcontainers.GenericContainerRequest{
+		Started: true,
+
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "pgvector/pgvector:pg17",
+			ExposedPorts: []string{"5432/tcp"},
+			Env: map[string]string{
+				"POSTGRES_PASSWORD": "postgres",
+			},
+			WaitingFor: wait.ForLog("database system is ready to accept connections").WithOccurrence(2).WithStartupTimeout(time.Minute),
+		},
+	})
+
+	require.NoError(t, err)
+
+	url, err := server.Endpoint(context.Context, "")
+	require.NoError(t, err)
+
+	c, err := pgvector.New("postgres://postgres:postgres@"+url+"/postgres?sslmode=disable", "test", pgvector.WithEmbedder(context.Embedder))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	test.TestIndex(t, context, c)
+}
@@ -0,0 +1,34 @@
+package pgvector
+
+import (
+	"github.com/adrianliechti/wingman/pkg/index"
+)
+
+type Option func(*Client)
+
+func WithEmbedder(embedder index.Embedder) Option {
+	return func(c *Client) {
+		c.embedder = embedder
+	}
+}
+
+// WithHNSW builds an HNSW index on the embeddings. Zero values keep the pgvector defaults.
+func WithHNSW(m, efConstruction, efSearch int) Option {
+	return func(c *Client) {
+		c.method = methodHNSW
+
+		c.m = m
+		c.efConstruction = efConstruction
+		c.efSearch = efSearch
+	}
+}
+
+// WithIVFFlat builds an IVFFlat index on the embeddings. Zero values keep the pgvector defaults.
+func WithIVFFlat(lists, probes int) Option {
+	return func(c *Client) {
+		c.method = methodIVFFlat
+
+		c.lists = lists
+		c.probes = probes
+	}
+}
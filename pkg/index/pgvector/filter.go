@@ -0,0 +1,118 @@
+package pgvector
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+)
+
+const (
+	numberPattern = `^\s*[-+]?([0-9]+\.?[0-9]*|\.[0-9]+)([eE][-+]?[0-9]+)?\s*$`
+	datePattern   = `^\d{4}-\d{2}-\d{2}`
+)
+
+// filterBuilder translates filters into a SQL condition on the metadata column and collects the query arguments.
+// Every condition evaluates to true or false, never NULL, so negations behave like index.Filter.Match.
+type filterBuilder struct {
+	args []any
+}
+
+func (b *filterBuilder) arg(v any) string {
+	b.args = append(b.args, v)
+	return "$" + strconv.Itoa(len(b.args))
+}
+
+func (b *filterBuilder) convertFilter(f index.Filter) (string, error) {
+	switch {
+	case len(f.And) > 0:
+		return b.convertFilters(f.And, " AND ")
+
+	case len(f.Or) > 0:
+		return b.convertFilters(f.Or, " OR ")
+
+	case f.Not != nil:
+		c, err := b.convertFilter(*f.Not)
+
+		if err != nil {
+			return "", err
+		}
+
+		return "NOT " + c, nil
+
+	case f.Operator == "":
+		return "TRUE", nil
+	}
+
+	field := "(metadata ->> " + b.arg(f.Field) + "::text)"
+
+	switch f.Operator {
+	case index.FilterOperatorExists:
+		return "(metadata ? " + b.arg(f.Field) + "::text)", nil
+
+	case index.FilterOperatorEqual:
+		return "COALESCE(lower" + field + " = lower(" + b.arg(f.Value) + "::text), FALSE)", nil
+
+	case index.FilterOperatorNotEqual:
+		return "COALESCE(lower" + field + " <> lower(" + b.arg(f.Value) + "::text), TRUE)", nil
+
+	case index.FilterOperatorIn:
+		return "COALESCE(lower" + field + " = ANY(" + b.arg(lowerValues(f.Values)) + "::text[]), FALSE)", nil
+
+	case index.FilterOperatorNotIn:
+		return "COALESCE(lower" + field + " <> ALL(" + b.arg(lowerValues(f.Values)) + "::text[]), TRUE)", nil
+	}
+
+	operators := map[index.FilterOperator]string{
+		index.FilterOperatorGreater:      ">",
+		index.FilterOperatorGreaterEqual: ">=",
+		index.FilterOperatorLess:         "<",
+		index.FilterOperatorLessEqual:    "<=",
+	}
+
+	op, ok := operators[f.Operator]
+
+	if !ok {
+		return "", errors.Join(index.ErrUnsupportedFilter, errors.New("invalid filter operator: "+string(f.Operator)))
+	}
+
+	// values are compared as numbers or dates if the filter value is one, like index.CompareValues.
+	// numeric accepts any value matching the pattern, where float8 would fail on out of range values.
+	if _, err := strconv.ParseFloat(f.Value, 64); err == nil {
+		return "COALESCE(CASE WHEN " + field + " ~ '" + numberPattern + "' THEN " + field + "::numeric " + op + " " + b.arg(f.Value) + "::numeric END, FALSE)", nil
+	}
+
+	// dates are compared as ISO text, since casting a malformed value to timestamptz would fail the whole query
+	if _, ok := index.ParseDate(f.Value); ok {
+		return "COALESCE(CASE WHEN " + field + " ~ '" + datePattern + "' THEN " + field + " COLLATE \"C\" " + op + " " + b.arg(f.Value) + "::text END, FALSE)", nil
+	}
+
+	return "COALESCE(" + field + " COLLATE \"C\" " + op + " " + b.arg(f.Value) + "::text, FALSE)", nil
+}
+
+func (b *filterBuilder) convertFilters(filters []index.Filter, separator string) (string, error) {
+	var conditions []string
+
+	for _, f := range filters {
+		c, err := b.convertFilter(f)
+
+		if err != nil {
+			return "", err
+		}
+
+		conditions = append(conditions, c)
+	}
+
+	return "(" + strings.Join(conditions, separator) + ")", nil
+}
+
+func lowerValues(values []string) []string {
+	result := make([]string, len(values))
+
+	for i, v := range values {
+		result[i] = strings.ToLower(v)
+	}
+
+	return result
+}
@@ -0,0 +1,31 @@
+package pgvector
+
+import (
+	"testing"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertFilterRange(t *testing.T) {
+	b := &filterBuilder{}
+
+	result, err := b.convertFilter(index.Filter{
+		And: []index.Filter{
+			{Field: "pages", Operator: index.FilterOperatorGreater, Value: "9"},
+			{Field: "date", Operator: index.FilterOperatorGreaterEqual, Value: "2024-07-01"},
+		},
+	})
+
+	require.NoError(t, err)
+
+	// malformed values never reach a cast, so they do not fail the query
+	require.Equal(t, "("+
+		"COALESCE(CASE WHEN (metadata ->> $1::text) ~ '"+numberPattern+"' THEN (metadata ->> $1::text)::numeric > $2::numeric END, FALSE)"+
+		" AND "+
+		"COALESCE(CASE WHEN (metadata ->> $3::text) ~ '"+datePattern+"' THEN (metadata ->> $3::text) COLLATE \"C\" >= $4::text END, FALSE)"+
+		")", result)
+
+	require.Equal(t, []any{"pages", "9", "date", "2024-07-01"}, b.args)
+}
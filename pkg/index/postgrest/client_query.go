@@ -10,6 +10,10 @@ import (
 )
 
 func (c *Client) Query(ctx context.Context, query string, options *index.QueryOptions) ([]index.Result, error) {
+	if err := options.CheckMode(index.QueryModeVector); err != nil {
+		return nil, err
+	}
+
 	if options == nil {
 		options = new(index.QueryOptions)
 	}
@@ -25,7 +25,6 @@ type Client struct {
 	namespace string
 
 	embedder index.Embedder
-	reranker index.Reranker
 }
 
 func New(url string, namespace string, options ...Option) (*Client, error) {
@@ -212,6 +211,10 @@ func (c *Client) Delete(ctx context.Context, ids ...string) error {
 }
 
 func (c *Client) Query(ctx context.Context, query string, options *index.QueryOptions) ([]index.Result, error) {
+	if err := options.CheckMode(index.QueryModeVector); err != nil {
+		return nil, err
+	}
+
 	if options == nil {
 		options = new(index.QueryOptions)
 	}
@@ -240,6 +243,21 @@ func (c *Client) Query(ctx context.Context, query string, options *index.QueryOp
 		"with_payload": true,
 	}
 
+	if f := options.Expression(); f != nil {
+		filter, err := convertFilter(*f)
+
+		if err != nil {
+			return nil, err
+		}
+
+		// the top level of a qdrant filter must be a filter object, not a field condition
+		if _, ok := filter["key"]; ok {
+			filter = map[string]any{"must": []any{filter}}
+		}
+
+		body["filter"] = filter
+	}
+
 	req, _ := http.NewRequestWithContext(ctx, "POST", u, jsonReader(body))
 	req.Header.Set("Content-Type", "application/json")
 
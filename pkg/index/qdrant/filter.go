@@ -0,0 +1,95 @@
+package qdrant
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+)
+
+// convertFilter translates a filter expression into a qdrant filter condition
+func convertFilter(f index.Filter) (map[string]any, error) {
+	switch {
+	case len(f.And) > 0:
+		conditions, err := convertFilters(f.And)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]any{"must": conditions}, nil
+
+	case len(f.Or) > 0:
+		conditions, err := convertFilters(f.Or)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]any{"should": conditions}, nil
+
+	case f.Not != nil:
+		condition, err := convertFilter(*f.Not)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]any{"must_not": []any{condition}}, nil
+	}
+
+	key := "metadata." + f.Field
+
+	switch f.Operator {
+	case index.FilterOperatorEqual:
+		return map[string]any{"key": key, "match": map[string]any{"value": f.Value}}, nil
+
+	case index.FilterOperatorNotEqual:
+		return map[string]any{
+			"must_not": []any{
+				map[string]any{"key": key, "match": map[string]any{"value": f.Value}},
+			},
+		}, nil
+
+	case index.FilterOperatorIn:
+		return map[string]any{"key": key, "match": map[string]any{"any": f.Values}}, nil
+
+	case index.FilterOperatorNotIn:
+		return map[string]any{"key": key, "match": map[string]any{"except": f.Values}}, nil
+
+	case index.FilterOperatorExists:
+		return map[string]any{
+			"must_not": []any{
+				map[string]any{"is_empty": map[string]any{"key": key}},
+			},
+		}, nil
+
+	case index.FilterOperatorGreater, index.FilterOperatorGreaterEqual, index.FilterOperatorLess, index.FilterOperatorLessEqual:
+		// payload values are strings, which qdrant compares as datetime only
+		t, ok := index.ParseDate(f.Value)
+
+		if !ok {
+			return nil, fmt.Errorf("%w: %s on %s", index.ErrUnsupportedFilter, f.Operator, f.Field)
+		}
+
+		return map[string]any{"key": key, "range": map[string]any{string(f.Operator): t.Format(time.RFC3339)}}, nil
+	}
+
+	return nil, index.ErrUnsupportedFilter
+}
+
+func convertFilters(filters []index.Filter) ([]any, error) {
+	var result []any
+
+	for _, f := range filters {
+		condition, err := convertFilter(f)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, condition)
+	}
+
+	return result, nil
+}
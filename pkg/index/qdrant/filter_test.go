@@ -0,0 +1,38 @@
+package qdrant
+
+import (
+	"testing"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertFilter(t *testing.T) {
+	result, err := convertFilter(index.Filter{
+		Or: []index.Filter{
+			{Field: "date", Operator: index.FilterOperatorGreater, Value: "2024-07-01"},
+			{Not: &index.Filter{Field: "archived", Operator: index.FilterOperatorExists}},
+		},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"should": []any{
+			map[string]any{"key": "metadata.date", "range": map[string]any{"gt": "2024-07-01T00:00:00Z"}},
+			map[string]any{"must_not": []any{
+				map[string]any{"must_not": []any{
+					map[string]any{"is_empty": map[string]any{"key": "metadata.archived"}},
+				}},
+			}},
+		},
+	}, result)
+}
+
+func TestConvertFilterNumber(t *testing.T) {
+	_, err := convertFilter(index.Filter{Field: "pages", Operator: index.FilterOperatorGreater, Value: "9"})
+	require.ErrorIs(t, err, index.ErrUnsupportedFilter)
+
+	_, err = convertFilter(index.Filter{Field: "name", Operator: index.FilterOperatorLess, Value: "m"})
+	require.ErrorIs(t, err, index.ErrUnsupportedFilter)
+}
@@ -0,0 +1,132 @@
+package reranker
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/to"
+)
+
+var _ index.Provider = &Provider{}
+
+// Provider wraps an index and reorders its query results with a reranker
+type Provider struct {
+	index    index.Provider
+	reranker index.Reranker
+
+	factor int
+}
+
+func New(index index.Provider, reranker index.Reranker, options ...Option) (*Provider, error) {
+	p := &Provider{
+		index:    index,
+		reranker: reranker,
+
+		factor: 4,
+	}
+
+	for _, option := range options {
+		option(p)
+	}
+
+	if p.index == nil {
+		return nil, errors.New("index is required")
+	}
+
+	if p.reranker == nil {
+		return nil, errors.New("reranker is required")
+	}
+
+	if p.factor < 1 {
+		p.factor = 1
+	}
+
+	return p, nil
+}
+
+func (p *Provider) List(ctx context.Context, options *index.ListOptions) (*index.Page[index.Document], error) {
+	return p.index.List(ctx, options)
+}
+
+func (p *Provider) Index(ctx context.Context, documents ...index.Document) error {
+	return p.index.Index(ctx, documents...)
+}
+
+func (p *Provider) Delete(ctx context.Context, ids ...string) error {
+	return p.index.Delete(ctx, ids...)
+}
+
+// Close closes the wrapped index, if it holds resources
+func (p *Provider) Close() error {
+	if c, ok := p.index.(io.Closer); ok {
+		return c.Close()
+	}
+
+	return nil
+}
+
+func (p *Provider) Query(ctx context.Context, query string, options *index.QueryOptions) ([]index.Result, error) {
+	if options == nil {
+		options = &index.QueryOptions{}
+	}
+
+	candidates := *options
+
+	if options.Limit != nil {
+		candidates.Limit = to.Ptr(*options.Limit * p.factor)
+	}
+
+	results, err := p.index.Query(ctx, query, &candidates)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return results, nil
+	}
+
+	texts := make([]string, 0, len(results))
+
+	// rankings only refer to the text, so documents are looked up by their content
+	documents := make(map[string][]index.Document)
+
+	for _, r := range results {
+		texts = append(texts, r.Content)
+		documents[r.Content] = append(documents[r.Content], r.Document)
+	}
+
+	rankings, err := p.reranker.Rerank(ctx, query, texts, &provider.RerankOptions{
+		Limit: options.Limit,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	reranked := make([]index.Result, 0, len(rankings))
+
+	for _, r := range rankings {
+		queue := documents[r.Text]
+
+		if len(queue) == 0 {
+			continue
+		}
+
+		documents[r.Text] = queue[1:]
+
+		reranked = append(reranked, index.Result{
+			Score:    float32(r.Score),
+			Document: queue[0],
+		})
+	}
+
+	if options.Limit != nil && len(reranked) > *options.Limit {
+		reranked = reranked[:*options.Limit]
+	}
+
+	return reranked, nil
+}
@@ -0,0 +1,68 @@
+package reranker_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+	"github.com/adrianliechti/wingman/pkg/index/reranker"
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/to"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReranker(t *testing.T) {
+	ctx := context.Background()
+
+	source := &staticIndex{
+		results: []index.Result{
+			{Score: 0.9, Document: index.Document{ID: "1", Content: "apples"}},
+			{Score: 0.8, Document: index.Document{ID: "2", Content: "bananas"}},
+			{Score: 0.7, Document: index.Document{ID: "3", Content: "cherries"}},
+			{Score: 0.6, Document: index.Document{ID: "4", Content: "cherries"}},
+		},
+	}
+
+	p, err := reranker.New(source, &keywordReranker{}, reranker.WithFactor(2))
+	require.NoError(t, err)
+
+	results, err := p.Query(ctx, "cherries", &index.QueryOptions{Limit: to.Ptr(2)})
+	require.NoError(t, err)
+
+	require.Equal(t, 4, *source.limit)
+
+	require.Len(t, results, 2)
+	require.Equal(t, "3", results[0].ID)
+	require.Equal(t, "4", results[1].ID)
+}
+
+type staticIndex struct {
+	index.Provider
+
+	limit   *int
+	results []index.Result
+}
+
+func (i *staticIndex) Query(ctx context.Context, query string, options *index.QueryOptions) ([]index.Result, error) {
+	i.limit = options.Limit
+	return i.results, nil
+}
+
+type keywordReranker struct{}
+
+func (r *keywordReranker) Rerank(ctx context.Context, query string, texts []string, options *provider.RerankOptions) ([]provider.Ranking, error) {
+	var matches []provider.Ranking
+	var others []provider.Ranking
+
+	for _, text := range texts {
+		if strings.Contains(text, query) {
+			matches = append(matches, provider.Ranking{Text: text, Score: 1})
+		} else {
+			others = append(others, provider.Ranking{Text: text, Score: 0})
+		}
+	}
+
+	return append(matches, others...), nil
+}
@@ -0,0 +1,10 @@
+package reranker
+
+type Option func(*Provider)
+
+// WithFactor sets how many times more candidates than requested are fetched from the index before reranking
+func WithFactor(factor int) Option {
+	return func(p *Provider) {
+		p.factor = factor
+	}
+}
@@ -31,6 +31,10 @@ func New(url string, options ...Option) (*Client, error) {
 }
 
 func (c *Client) Query(ctx context.Context, query string, options *index.QueryOptions) ([]index.Result, error) {
+	if err := options.CheckMode(index.QueryModeKeyword); err != nil {
+		return nil, err
+	}
+
 	url, _ := url.Parse(c.url)
 	url = url.JoinPath("/search")
 
@@ -35,6 +35,10 @@ func New(token string, options ...Option) (*Client, error) {
 }
 
 func (c *Client) Query(ctx context.Context, query string, options *index.QueryOptions) ([]index.Result, error) {
+	if err := options.CheckMode(index.QueryModeKeyword); err != nil {
+		return nil, err
+	}
+
 	u, _ := url.Parse("https://api.tavily.com/search")
 
 	body := map[string]any{
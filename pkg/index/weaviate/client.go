@@ -28,7 +28,6 @@ type Client struct {
 	class string
 
 	embedder index.Embedder
-	reranker index.Reranker
 }
 
 func New(url, namespace string, options ...Option) (*Client, error) {
@@ -211,6 +210,22 @@ func (c *Client) Delete(ctx context.Context, ids ...string) error {
 }
 
 func (c *Client) Query(ctx context.Context, query string, options *index.QueryOptions) ([]index.Result, error) {
+	if options == nil {
+		options = new(index.QueryOptions)
+	}
+
+	var where string
+
+	if f := options.Expression(); f != nil {
+		filter, err := convertFilter(*f)
+
+		if err != nil {
+			return nil, err
+		}
+
+		where = filter
+	}
+
 	var vector strings.Builder
 
 	embedding, err := c.embedder.Embed(ctx, []string{query})
@@ -227,14 +242,27 @@ func (c *Client) Query(ctx context.Context, query string, options *index.QueryOp
 		vector.WriteString(fmt.Sprintf("%f", v))
 	}
 
+	var alpha string
+
+	// weaviate always runs a hybrid search, pure modes weight only one side
+	switch options.Mode {
+	case index.QueryModeVector:
+		alpha = "1"
+
+	case index.QueryModeKeyword:
+		alpha = "0"
+	}
+
 	data := executeQueryTemplate(queryData{
 		Class: c.class,
 
 		Query:  query,
 		Vector: embedding.Embeddings[0],
 
+		Alpha: alpha,
+
 		Limit: options.Limit,
-		Where: options.Filters,
+		Where: where,
 	})
 
 	body := map[string]any{
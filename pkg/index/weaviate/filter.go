@@ -0,0 +1,108 @@
+package weaviate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+)
+
+// convertFilter translates a filter expression into a weaviate GraphQL where argument
+func convertFilter(f index.Filter) (string, error) {
+	switch {
+	case len(f.And) > 0:
+		return convertOperands("And", f.And)
+
+	case len(f.Or) > 0:
+		return convertOperands("Or", f.Or)
+
+	case f.Not != nil:
+		// weaviate has no negation operator, so it is pushed down to the conditions
+		n, err := f.Not.Negate()
+
+		if err != nil {
+			return "", err
+		}
+
+		return convertFilter(n)
+	}
+
+	path := "[" + strconv.Quote(f.Field) + "]"
+
+	condition := func(operator string, value string) string {
+		return "{ path: " + path + ", operator: " + operator + ", " + value + " }"
+	}
+
+	// values are stored as text, which orders ISO dates but not numbers
+	compare := func(operator string) (string, error) {
+		if _, err := strconv.ParseFloat(f.Value, 64); err == nil {
+			return "", fmt.Errorf("%w: %s on %s", index.ErrUnsupportedFilter, f.Operator, f.Field)
+		}
+
+		return condition(operator, "valueText: "+strconv.Quote(f.Value)), nil
+	}
+
+	switch f.Operator {
+	case index.FilterOperatorEqual:
+		return condition("Equal", "valueText: "+strconv.Quote(f.Value)), nil
+
+	case index.FilterOperatorNotEqual:
+		return condition("NotEqual", "valueText: "+strconv.Quote(f.Value)), nil
+
+	case index.FilterOperatorIn:
+		return condition("ContainsAny", "valueText: "+quoteValues(f.Values)), nil
+
+	case index.FilterOperatorNotIn:
+		var filters []index.Filter
+
+		for _, v := range f.Values {
+			filters = append(filters, index.Filter{Field: f.Field, Operator: index.FilterOperatorNotEqual, Value: v})
+		}
+
+		return convertOperands("And", filters)
+
+	case index.FilterOperatorExists:
+		return condition("IsNull", "valueBoolean: false"), nil
+
+	case index.FilterOperatorGreater:
+		return compare("GreaterThan")
+
+	case index.FilterOperatorGreaterEqual:
+		return compare("GreaterThanEqual")
+
+	case index.FilterOperatorLess:
+		return compare("LessThan")
+
+	case index.FilterOperatorLessEqual:
+		return compare("LessThanEqual")
+	}
+
+	return "", index.ErrUnsupportedFilter
+}
+
+func convertOperands(operator string, filters []index.Filter) (string, error) {
+	var operands []string
+
+	for _, f := range filters {
+		operand, err := convertFilter(f)
+
+		if err != nil {
+			return "", err
+		}
+
+		operands = append(operands, operand)
+	}
+
+	return "{ operator: " + operator + ", operands: [" + strings.Join(operands, ", ") + "] }", nil
+}
+
+func quoteValues(values []string) string {
+	var result []string
+
+	for _, v := range values {
+		result = append(result, strconv.Quote(v))
+	}
+
+	return "[" + strings.Join(result, ", ") + "]"
+}
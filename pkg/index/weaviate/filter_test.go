@@ -0,0 +1,33 @@
+package weaviate
+
+import (
+	"testing"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertFilter(t *testing.T) {
+	result, err := convertFilter(index.Filter{
+		And: []index.Filter{
+			{Field: "date", Operator: index.FilterOperatorGreaterEqual, Value: "2024-07-01"},
+			{Field: "department", Operator: index.FilterOperatorIn, Values: []string{"x", "y"}},
+		},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, `{ operator: And, operands: [{ path: ["date"], operator: GreaterThanEqual, valueText: "2024-07-01" }, { path: ["department"], operator: ContainsAny, valueText: ["x", "y"] }] }`, result)
+
+	result, err = convertFilter(index.Filter{
+		Not: &index.Filter{Field: "department", Operator: index.FilterOperatorEqual, Value: "x"},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, `{ path: ["department"], operator: NotEqual, valueText: "x" }`, result)
+}
+
+func TestConvertFilterNumber(t *testing.T) {
+	_, err := convertFilter(index.Filter{Field: "pages", Operator: index.FilterOperatorGreater, Value: "9"})
+	require.ErrorIs(t, err, index.ErrUnsupportedFilter)
+}
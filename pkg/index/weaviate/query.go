@@ -18,8 +18,11 @@ type queryData struct {
 	Query  string
 	Vector []float32
 
+	// Alpha weights the vector search against the keyword search, weaviate's default if empty
+	Alpha string
+
 	Limit *int
-	Where map[string]string
+	Where string
 }
 
 func executeQueryTemplate(data queryData) string {
@@ -0,0 +1,15 @@
+package weaviate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryTemplateAlpha(t *testing.T) {
+	query := executeQueryTemplate(queryData{Class: "Document", Query: "test", Vector: []float32{1, 0}})
+	require.NotContains(t, query, "alpha")
+
+	query = executeQueryTemplate(queryData{Class: "Document", Query: "test", Vector: []float32{1, 0}, Alpha: "0"})
+	require.Contains(t, query, "alpha: 0\n")
+}
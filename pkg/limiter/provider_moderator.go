@@ -0,0 +1,37 @@
+package limiter
+
+import (
+	"context"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+
+	"golang.org/x/time/rate"
+)
+
+type Moderator interface {
+	Limiter
+	provider.Moderator
+}
+
+type limitedModerator struct {
+	limiter  *rate.Limiter
+	provider provider.Moderator
+}
+
+func NewModerator(l *rate.Limiter, p provider.Moderator) Moderator {
+	return &limitedModerator{
+		limiter:  l,
+		provider: p,
+	}
+}
+
+func (p *limitedModerator) limiterSetup() {
+}
+
+func (p *limitedModerator) Moderate(ctx context.Context, inputs []string, options *provider.ModerateOptions) ([]provider.Moderation, error) {
+	if p.limiter != nil {
+		p.limiter.Wait(ctx)
+	}
+
+	return p.provider.Moderate(ctx, inputs, options)
+}
@@ -2,6 +2,7 @@ package otel
 
 import (
 	"context"
+	"io"
 	"strings"
 
 	"github.com/adrianliechti/wingman/pkg/index"
@@ -67,6 +68,14 @@ func (p *observableIndex) Delete(ctx context.Context, ids ...string) error {
 	return err
 }
 
+func (p *observableIndex) Close() error {
+	if c, ok := p.index.(io.Closer); ok {
+		return c.Close()
+	}
+
+	return nil
+}
+
 func (p *observableIndex) Query(ctx context.Context, query string, options *index.QueryOptions) ([]index.Result, error) {
 	ctx, span := otel.Tracer(p.library).Start(ctx, p.name)
 	defer span.End()
@@ -0,0 +1,66 @@
+package otel
+
+import (
+	"context"
+	"strings"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type Moderator interface {
+	Observable
+	provider.Moderator
+}
+
+type observableModerator struct {
+	name    string
+	library string
+
+	model    string
+	provider string
+
+	moderator provider.Moderator
+}
+
+func NewModerator(provider, model string, p provider.Moderator) Moderator {
+	library := strings.ToLower(provider)
+
+	return &observableModerator{
+		moderator: p,
+
+		name:    strings.TrimSuffix(strings.ToLower(provider), "-moderator") + "-moderator",
+		library: library,
+
+		model:    model,
+		provider: provider,
+	}
+}
+
+func (p *observableModerator) otelSetup() {
+}
+
+func (p *observableModerator) Moderate(ctx context.Context, inputs []string, options *provider.ModerateOptions) ([]provider.Moderation, error) {
+	ctx, span := otel.Tracer(p.library).Start(ctx, p.name)
+	defer span.End()
+
+	result, err := p.moderator.Moderate(ctx, inputs, options)
+
+	meterRequest(ctx, p.library, p.provider, "moderate", p.model)
+
+	if EnableDebug {
+		span.SetAttributes(attribute.StringSlice("inputs", inputs))
+
+		var flagged []bool
+
+		for _, r := range result {
+			flagged = append(flagged, r.Flagged)
+		}
+
+		span.SetAttributes(attribute.BoolSlice("flagged", flagged))
+	}
+
+	return result, err
+}
@@ -0,0 +1,42 @@
+package moderator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+
+	"github.com/stretchr/testify/require"
+)
+
+type completer string
+
+func (c completer) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	return &provider.Completion{
+		Message: provider.Message{
+			Role:    provider.MessageRoleAssistant,
+			Content: string(c),
+		},
+	}, nil
+}
+
+func TestModerate(t *testing.T) {
+	m := FromCompleter(completer("```json\n{\"categories\": {\"hate\": 0.9, \"violence\": 0.1, \"other\": 1}}\n```"), []string{"hate", "violence", "spam"})
+
+	results, err := m.Moderate(context.Background(), []string{"input"}, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	result := results[0]
+
+	require.True(t, result.Flagged)
+	require.Equal(t, map[string]bool{"hate": true, "violence": false, "spam": false}, result.Categories)
+	require.Equal(t, map[string]float64{"hate": 0.9, "violence": 0.1, "spam": 0}, result.Scores)
+}
+
+func TestModerateInvalid(t *testing.T) {
+	m := FromCompleter(completer("I cannot classify this."), nil)
+
+	_, err := m.Moderate(context.Background(), []string{"input"}, nil)
+	require.Error(t, err)
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"strings"
 
 	"github.com/adrianliechti/wingman/pkg/provider"
 
@@ -40,6 +41,10 @@ func (c *Completer) Complete(ctx context.Context, messages []provider.Message, o
 		options = new(provider.CompleteOptions)
 	}
 
+	if err := options.Unsupported("presence_penalty", "frequency_penalty", "seed", "logprobs"); err != nil {
+		return nil, err
+	}
+
 	req, err := c.convertMessageRequest(messages, options)
 
 	if err != nil {
@@ -60,6 +65,8 @@ func (c *Completer) complete(ctx context.Context, req anthropic.MessageNewParams
 		return nil, convertError(err)
 	}
 
+	reasoning, signature := toReasoning(message.Content)
+
 	return &provider.Completion{
 		ID:     message.ID,
 		Reason: toCompletionResult(message.StopReason),
@@ -68,6 +75,9 @@ func (c *Completer) complete(ctx context.Context, req anthropic.MessageNewParams
 			Role:    provider.MessageRoleAssistant,
 			Content: toContent(message.Content),
 
+			Reasoning:          reasoning,
+			ReasoningSignature: signature,
+
 			ToolCalls: toToolCalls(message.Content),
 		},
 
@@ -128,6 +138,9 @@ func (c *Completer) completeStream(ctx context.Context, req anthropic.MessageNew
 				Message: provider.Message{
 					Role:    provider.MessageRoleAssistant,
 					Content: event.Delta.Text,
+
+					Reasoning:          event.Delta.Thinking,
+					ReasoningSignature: event.Delta.Signature,
 				},
 			}
 
@@ -179,6 +192,8 @@ func (c *Completer) completeStream(ctx context.Context, req anthropic.MessageNew
 		return nil, convertError(err)
 	}
 
+	reasoning, signature := toReasoning(message.Content)
+
 	return &provider.Completion{
 		ID:     message.ID,
 		Reason: toCompletionResult(message.StopReason),
@@ -187,6 +202,9 @@ func (c *Completer) completeStream(ctx context.Context, req anthropic.MessageNew
 			Role:    provider.MessageRoleAssistant,
 			Content: toContent(message.Content),
 
+			Reasoning:          reasoning,
+			ReasoningSignature: signature,
+
 			ToolCalls: toToolCalls(message.Content),
 		},
 
@@ -220,10 +238,33 @@ func (c *Completer) convertMessageRequest(input []provider.Message, options *pro
 		req.MaxTokens = anthropic.F(int64(*options.MaxTokens))
 	}
 
-	if options.Temperature != nil {
+	budget := c.thinkingBudget(options)
+
+	if budget > 0 {
+		req.Thinking = anthropic.F[anthropic.ThinkingConfigParamUnion](anthropic.ThinkingConfigEnabledParam{
+			Type:         anthropic.F(anthropic.ThinkingConfigEnabledTypeEnabled),
+			BudgetTokens: anthropic.F(budget),
+		})
+
+		// the thinking budget counts towards the max tokens, so it is added to the default
+		if options.MaxTokens == nil {
+			req.MaxTokens = anthropic.F(req.MaxTokens.Value + budget)
+		}
+	}
+
+	// extended thinking rejects a temperature
+	if options.Temperature != nil && budget == 0 {
 		req.Temperature = anthropic.F(float64(*options.Temperature))
 	}
 
+	if options.TopP != nil {
+		req.TopP = anthropic.F(float64(*options.TopP))
+	}
+
+	if len(options.Tools) > 0 && (options.ToolChoice != nil || options.ParallelToolCalls != nil) {
+		req.ToolChoice = anthropic.F(convertToolChoice(options.ToolChoice, options.ParallelToolCalls))
+	}
+
 	for _, m := range input {
 		switch m.Role {
 		case provider.MessageRoleSystem:
@@ -274,6 +315,16 @@ func (c *Completer) convertMessageRequest(input []provider.Message, options *pro
 		case provider.MessageRoleAssistant:
 			blocks := []anthropic.ContentBlockParamUnion{}
 
+			// thinking can only be passed back with the signature it was returned with
+			if m.Reasoning != "" && m.ReasoningSignature != "" {
+				blocks = append(blocks, anthropic.ThinkingBlockParam{
+					Type: anthropic.F(anthropic.ThinkingBlockParamTypeThinking),
+
+					Thinking:  anthropic.F(m.Reasoning),
+					Signature: anthropic.F(m.ReasoningSignature),
+				})
+			}
+
 			if m.Content != "" {
 				blocks = append(blocks, anthropic.NewTextBlock(m.Content))
 			}
@@ -347,6 +398,116 @@ func (c *Completer) convertMessageRequest(input []provider.Message, options *pro
 	return req, nil
 }
 
+func convertToolChoice(choice *provider.ToolChoice, parallel *bool) anthropic.ToolChoiceUnionParam {
+	if choice == nil {
+		choice = &provider.ToolChoice{
+			Mode: provider.ToolChoiceModeAuto,
+		}
+	}
+
+	disableParallel := parallel != nil && !*parallel
+
+	if choice.Name != "" {
+		return anthropic.ToolChoiceToolParam{
+			Type: anthropic.F(anthropic.ToolChoiceToolTypeTool),
+			Name: anthropic.F(choice.Name),
+
+			DisableParallelToolUse: anthropic.F(disableParallel),
+		}
+	}
+
+	switch choice.Mode {
+	case provider.ToolChoiceModeNone:
+		return anthropic.ToolChoiceNoneParam{
+			Type: anthropic.F(anthropic.ToolChoiceNoneTypeNone),
+		}
+
+	case provider.ToolChoiceModeRequired:
+		return anthropic.ToolChoiceAnyParam{
+			Type: anthropic.F(anthropic.ToolChoiceAnyTypeAny),
+
+			DisableParallelToolUse: anthropic.F(disableParallel),
+		}
+
+	default:
+		return anthropic.ToolChoiceAutoParam{
+			Type: anthropic.F(anthropic.ToolChoiceAutoTypeAuto),
+
+			DisableParallelToolUse: anthropic.F(disableParallel),
+		}
+	}
+}
+
+// thinkingBudget returns the extended thinking budget, either as requested or mapped from the reasoning effort.
+// An effort is ignored by models without extended thinking and kept below an explicit max tokens limit.
+func (c *Completer) thinkingBudget(options *provider.CompleteOptions) int64 {
+	if options.ReasoningBudget != nil {
+		return int64(*options.ReasoningBudget)
+	}
+
+	if !supportsThinking(c.model) {
+		return 0
+	}
+
+	var budget int64
+
+	switch options.Effort {
+	case provider.ReasoningEffortLow:
+		budget = 4096
+
+	case provider.ReasoningEffortMedium:
+		budget = 16384
+
+	case provider.ReasoningEffortHigh:
+		budget = 32768
+	}
+
+	if options.MaxTokens != nil && budget >= int64(*options.MaxTokens) {
+		budget = int64(*options.MaxTokens) / 2
+	}
+
+	// anthropic requires a budget of at least 1024 tokens
+	if budget < 1024 {
+		return 0
+	}
+
+	return budget
+}
+
+// supportsThinking reports whether a model has extended thinking, which all models since claude 3.7 have
+func supportsThinking(model string) bool {
+	for _, prefix := range []string{
+		"claude-instant",
+		"claude-2",
+		"claude-3-haiku",
+		"claude-3-sonnet",
+		"claude-3-opus",
+		"claude-3-5-",
+	} {
+		if strings.HasPrefix(model, prefix) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func toReasoning(blocks []anthropic.ContentBlock) (string, string) {
+	var reasoning strings.Builder
+	var signature string
+
+	for _, b := range blocks {
+		if b.Type != anthropic.ContentBlockTypeThinking {
+			continue
+		}
+
+		reasoning.WriteString(b.Thinking)
+		signature = b.Signature
+	}
+
+	return reasoning.String(), signature
+}
+
 func toContent(blocks []anthropic.ContentBlock) string {
 	for _, b := range blocks {
 		if b.Type != anthropic.ContentBlockTypeText {
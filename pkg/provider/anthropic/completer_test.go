@@ -0,0 +1,60 @@
+package anthropic
+
+import (
+	"testing"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/to"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThinkingEffort(t *testing.T) {
+	c, err := NewCompleter("", "claude-sonnet-4-0")
+	require.NoError(t, err)
+
+	req, err := c.convertMessageRequest(nil, &provider.CompleteOptions{
+		Effort:      provider.ReasoningEffortMedium,
+		Temperature: to.Ptr[float32](0.5),
+	})
+
+	require.NoError(t, err)
+
+	require.Equal(t, int64(16384), req.Thinking.Value.(anthropic.ThinkingConfigEnabledParam).BudgetTokens.Value)
+	require.Equal(t, int64(4096+16384), req.MaxTokens.Value)
+	require.False(t, req.Temperature.Present)
+}
+
+func TestThinkingBudget(t *testing.T) {
+	c, err := NewCompleter("", "claude-sonnet-4-0")
+	require.NoError(t, err)
+
+	req, err := c.convertMessageRequest(nil, &provider.CompleteOptions{
+		Effort:          provider.ReasoningEffortMedium,
+		ReasoningBudget: to.Ptr(10000),
+
+		MaxTokens: to.Ptr(12000),
+	})
+
+	require.NoError(t, err)
+
+	require.Equal(t, int64(10000), req.Thinking.Value.(anthropic.ThinkingConfigEnabledParam).BudgetTokens.Value)
+	require.Equal(t, int64(12000), req.MaxTokens.Value)
+}
+
+func TestThinkingUnsupported(t *testing.T) {
+	c, err := NewCompleter("", "claude-3-5-haiku-latest")
+	require.NoError(t, err)
+
+	req, err := c.convertMessageRequest(nil, &provider.CompleteOptions{
+		Effort:      provider.ReasoningEffortHigh,
+		Temperature: to.Ptr[float32](0.5),
+	})
+
+	require.NoError(t, err)
+
+	require.False(t, req.Thinking.Present)
+	require.Equal(t, int64(4096), req.MaxTokens.Value)
+	require.Equal(t, 0.5, req.Temperature.Value)
+}
@@ -56,6 +56,10 @@ func (c *Completer) Complete(ctx context.Context, messages []provider.Message, o
 		options = new(provider.CompleteOptions)
 	}
 
+	if err := options.Unsupported("parallel_tool_calls", "presence_penalty", "frequency_penalty", "seed", "logprobs"); err != nil {
+		return nil, err
+	}
+
 	req, err := c.convertConverseInput(messages, options)
 
 	if err != nil {
@@ -70,6 +74,8 @@ func (c *Completer) Complete(ctx context.Context, messages []provider.Message, o
 
 			System:     req.System,
 			ToolConfig: req.ToolConfig,
+
+			InferenceConfig: req.InferenceConfig,
 		}
 
 		return c.completeStream(ctx, req, options)
@@ -240,16 +246,44 @@ func (c *Completer) convertConverseInput(input []provider.Message, options *prov
 		return nil, err
 	}
 
+	tools, err := convertToolConfig(options.Tools, options.ToolChoice)
+
+	if err != nil {
+		return nil, err
+	}
+
 	return &bedrockruntime.ConverseInput{
 		ModelId: aws.String(c.model),
 
 		Messages: messages,
 
 		System:     convertSystem(input),
-		ToolConfig: convertToolConfig(options.Tools),
+		ToolConfig: tools,
+
+		InferenceConfig: convertInferenceConfig(options),
 	}, nil
 }
 
+func convertInferenceConfig(options *provider.CompleteOptions) *types.InferenceConfiguration {
+	result := &types.InferenceConfiguration{
+		StopSequences: options.Stop,
+	}
+
+	if options.MaxTokens != nil {
+		result.MaxTokens = aws.Int32(int32(*options.MaxTokens))
+	}
+
+	if options.Temperature != nil {
+		result.Temperature = options.Temperature
+	}
+
+	if options.TopP != nil {
+		result.TopP = options.TopP
+	}
+
+	return result
+}
+
 func convertSystem(messages []provider.Message) []types.SystemContentBlock {
 	var result []types.SystemContentBlock
 
@@ -373,13 +407,31 @@ func convertMessages(messages []provider.Message) ([]types.Message, error) {
 	return result, nil
 }
 
-func convertToolConfig(tools []provider.Tool) *types.ToolConfiguration {
+func convertToolConfig(tools []provider.Tool, choice *provider.ToolChoice) (*types.ToolConfiguration, error) {
 	if len(tools) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	result := &types.ToolConfiguration{}
 
+	if choice != nil {
+		switch {
+		case choice.Name != "":
+			result.ToolChoice = &types.ToolChoiceMemberTool{
+				Value: types.SpecificToolChoice{
+					Name: aws.String(choice.Name),
+				},
+			}
+
+		case choice.Mode == provider.ToolChoiceModeRequired:
+			result.ToolChoice = &types.ToolChoiceMemberAny{}
+
+		case choice.Mode == provider.ToolChoiceModeNone:
+			// the converse api has no way to turn off tool calls while tools are present
+			return nil, &provider.UnsupportedOptionError{Option: "tool_choice"}
+		}
+	}
+
 	for _, t := range tools {
 		tool := types.ToolSpecification{
 			Name: aws.String(t.Name),
@@ -398,7 +450,7 @@ func convertToolConfig(tools []provider.Tool) *types.ToolConfiguration {
 		result.Tools = append(result.Tools, &types.ToolMemberToolSpec{Value: tool})
 	}
 
-	return result
+	return result, nil
 }
 
 func convertFile(val provider.File) (types.ContentBlock, error) {
@@ -41,6 +41,10 @@ func (c *Completer) Complete(ctx context.Context, messages []provider.Message, o
 		options = new(provider.CompleteOptions)
 	}
 
+	if err := options.Unsupported("parallel_tool_calls", "logprobs"); err != nil {
+		return nil, err
+	}
+
 	req, err := convertChatRequest(c.model, messages, options)
 
 	if err != nil {
@@ -48,18 +52,31 @@ func (c *Completer) Complete(ctx context.Context, messages []provider.Message, o
 	}
 
 	if options.Stream != nil {
+		var toolChoice *v2.V2ChatStreamRequestToolChoice
+
+		if req.ToolChoice != nil {
+			toolChoice = to.Ptr(v2.V2ChatStreamRequestToolChoice(*req.ToolChoice))
+		}
+
 		req := &v2.V2ChatStreamRequest{
 			Model: c.model,
 
-			Tools:    req.Tools,
-			Messages: req.Messages,
+			Tools:      req.Tools,
+			ToolChoice: toolChoice,
+			Messages:   req.Messages,
 
 			ResponseFormat: req.ResponseFormat,
 
 			MaxTokens:     req.MaxTokens,
 			StopSequences: req.StopSequences,
 			Temperature:   req.Temperature,
+			P:             req.P,
+
+			Seed:             req.Seed,
+			PresencePenalty:  req.PresencePenalty,
+			FrequencyPenalty: req.FrequencyPenalty,
 		}
+
 		return c.completeStream(ctx, req, options)
 	}
 
@@ -301,6 +318,36 @@ func convertChatRequest(model string, messages []provider.Message, options *prov
 		req.Temperature = to.Ptr(float64(*options.Temperature))
 	}
 
+	if options.TopP != nil {
+		req.P = to.Ptr(float64(*options.TopP))
+	}
+
+	if options.Seed != nil {
+		req.Seed = options.Seed
+	}
+
+	if options.PresencePenalty != nil {
+		req.PresencePenalty = to.Ptr(float64(*options.PresencePenalty))
+	}
+
+	if options.FrequencyPenalty != nil {
+		req.FrequencyPenalty = to.Ptr(float64(*options.FrequencyPenalty))
+	}
+
+	if options.ToolChoice != nil {
+		if options.ToolChoice.Name != "" {
+			return nil, &provider.UnsupportedOptionError{Option: "tool_choice"}
+		}
+
+		switch options.ToolChoice.Mode {
+		case provider.ToolChoiceModeNone:
+			req.ToolChoice = to.Ptr(v2.V2ChatRequestToolChoiceNone)
+
+		case provider.ToolChoiceModeRequired:
+			req.ToolChoice = to.Ptr(v2.V2ChatRequestToolChoiceRequired)
+		}
+	}
+
 	for _, t := range options.Tools {
 		tool := &v2.ToolV2{
 			Type: to.Ptr("function"),
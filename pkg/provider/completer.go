@@ -4,6 +4,15 @@ import (
 	"context"
 )
 
+// UnsupportedOptionError is returned by completers which cannot honor a requested option
+type UnsupportedOptionError struct {
+	Option string
+}
+
+func (e *UnsupportedOptionError) Error() string {
+	return "option not supported by provider: " + e.Option
+}
+
 type Completer interface {
 	Complete(ctx context.Context, messages []Message, options *CompleteOptions) (*Completion, error)
 }
@@ -12,6 +21,10 @@ type Message struct {
 	Role    MessageRole
 	Content string
 
+	// Reasoning is the thinking trace of reasoning models, and the signature verifies it when passed back to its provider
+	Reasoning          string
+	ReasoningSignature string
+
 	Files []File
 
 	Tool      string
@@ -40,16 +53,83 @@ type CompleteOptions struct {
 	Stream StreamHandler
 	Effort ReasoningEffort
 
+	// ReasoningBudget is the exact thinking token budget, counted within MaxTokens, for providers supporting it
+	ReasoningBudget *int
+
 	Stop  []string
 	Tools []Tool
 
+	ToolChoice        *ToolChoice
+	ParallelToolCalls *bool
+
 	MaxTokens   *int
 	Temperature *float32
+	TopP        *float32
+
+	PresencePenalty  *float32
+	FrequencyPenalty *float32
+
+	Seed *int
+
+	Logprobs    bool
+	TopLogprobs *int
 
 	Format CompletionFormat
 	Schema *Schema
 }
 
+// Unsupported returns an UnsupportedOptionError for the first of the named options which is set.
+// A tool choice of auto and enabled parallel tool calls match the default behavior and are not counted.
+func (o *CompleteOptions) Unsupported(options ...string) error {
+	for _, option := range options {
+		var set bool
+
+		switch option {
+		case "tool_choice":
+			set = o.ToolChoice != nil && (o.ToolChoice.Mode != ToolChoiceModeAuto || o.ToolChoice.Name != "")
+
+		case "parallel_tool_calls":
+			set = o.ParallelToolCalls != nil && !*o.ParallelToolCalls
+
+		case "top_p":
+			set = o.TopP != nil
+
+		case "presence_penalty":
+			set = o.PresencePenalty != nil
+
+		case "frequency_penalty":
+			set = o.FrequencyPenalty != nil
+
+		case "seed":
+			set = o.Seed != nil
+
+		case "logprobs":
+			set = o.Logprobs || o.TopLogprobs != nil
+		}
+
+		if set {
+			return &UnsupportedOptionError{Option: option}
+		}
+	}
+
+	return nil
+}
+
+type ToolChoice struct {
+	Mode ToolChoiceMode
+
+	// Name forces a call of this tool
+	Name string
+}
+
+type ToolChoiceMode string
+
+const (
+	ToolChoiceModeAuto     ToolChoiceMode = "auto"
+	ToolChoiceModeNone     ToolChoiceMode = "none"
+	ToolChoiceModeRequired ToolChoiceMode = "required"
+)
+
 type Completion struct {
 	ID string
 
@@ -57,9 +137,23 @@ type Completion struct {
 
 	Message Message
 
+	Logprobs []Logprob
+
 	Usage *Usage
 }
 
+type Logprob struct {
+	Token   string
+	Logprob float64
+
+	TopLogprobs []TopLogprob
+}
+
+type TopLogprob struct {
+	Token   string
+	Logprob float64
+}
+
 type ReasoningEffort string
 
 const (
@@ -0,0 +1,296 @@
+package custom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/to"
+)
+
+var _ provider.Completer = (*Completer)(nil)
+
+type Completer struct {
+	*Config
+}
+
+func NewCompleter(url, model string, options ...Option) (*Completer, error) {
+	cfg, err := newConfig(url, model, options...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Completer{
+		Config: cfg,
+	}, nil
+}
+
+func (c *Completer) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	if options == nil {
+		options = new(provider.CompleteOptions)
+	}
+
+	if err := options.Unsupported("logprobs"); err != nil {
+		return nil, err
+	}
+
+	req, err := convertCompleteRequest(c.model, messages, options)
+
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := c.client.Complete(ctx, req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := &provider.Completion{
+		Message: provider.Message{
+			Role: provider.MessageRoleAssistant,
+		},
+	}
+
+	for {
+		resp, err := stream.Recv()
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.Id != "" {
+			result.ID = resp.Id
+		}
+
+		if resp.Reason != "" {
+			result.Reason = provider.CompletionReason(resp.Reason)
+		}
+
+		if resp.Usage != nil {
+			result.Usage = &provider.Usage{
+				InputTokens:  int(resp.Usage.InputTokens),
+				OutputTokens: int(resp.Usage.OutputTokens),
+			}
+		}
+
+		delta := provider.Completion{
+			ID:     result.ID,
+			Reason: provider.CompletionReason(resp.Reason),
+
+			Message: provider.Message{
+				Role: provider.MessageRoleAssistant,
+			},
+
+			Usage: result.Usage,
+		}
+
+		if m := resp.Message; m != nil {
+			delta.Message.Content = m.Content
+			delta.Message.Reasoning = m.Reasoning
+			delta.Message.ReasoningSignature = m.ReasoningSignature
+
+			result.Message.Content += m.Content
+			result.Message.Reasoning += m.Reasoning
+
+			if m.ReasoningSignature != "" {
+				result.Message.ReasoningSignature = m.ReasoningSignature
+			}
+
+			for _, t := range m.ToolCalls {
+				call := provider.ToolCall{
+					ID: t.Id,
+
+					Name:      t.Name,
+					Arguments: t.Arguments,
+				}
+
+				delta.Message.ToolCalls = append(delta.Message.ToolCalls, call)
+
+				if t.Id == "" && len(result.Message.ToolCalls) > 0 {
+					last := &result.Message.ToolCalls[len(result.Message.ToolCalls)-1]
+
+					last.Name += t.Name
+					last.Arguments += t.Arguments
+
+					continue
+				}
+
+				result.Message.ToolCalls = append(result.Message.ToolCalls, call)
+			}
+		}
+
+		if options.Stream != nil {
+			if err := options.Stream(ctx, delta); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if result.Reason == "" {
+		result.Reason = provider.CompletionReasonStop
+
+		if len(result.Message.ToolCalls) > 0 {
+			result.Reason = provider.CompletionReasonTool
+		}
+	}
+
+	return result, nil
+}
+
+func convertCompleteRequest(model string, messages []provider.Message, options *provider.CompleteOptions) (*CompleteRequest, error) {
+	req := &CompleteRequest{
+		Model: model,
+
+		Stop: options.Stop,
+
+		ParallelToolCalls: options.ParallelToolCalls,
+
+		Temperature: options.Temperature,
+		TopP:        options.TopP,
+
+		PresencePenalty:  options.PresencePenalty,
+		FrequencyPenalty: options.FrequencyPenalty,
+	}
+
+	if options.Effort != "" {
+		req.Effort = to.Ptr(string(options.Effort))
+	}
+
+	if options.ToolChoice != nil {
+		req.ToolChoice = to.Ptr(string(options.ToolChoice.Mode))
+
+		if options.ToolChoice.Name != "" {
+			req.ToolChoiceName = to.Ptr(options.ToolChoice.Name)
+		}
+	}
+
+	if options.MaxTokens != nil {
+		req.MaxTokens = to.Ptr(int32(*options.MaxTokens))
+	}
+
+	if options.Seed != nil {
+		req.Seed = to.Ptr(int32(*options.Seed))
+	}
+
+	if options.Format != "" {
+		req.Format = to.Ptr(string(options.Format))
+	}
+
+	if options.Schema != nil {
+		schema, err := json.Marshal(options.Schema.Schema)
+
+		if err != nil {
+			return nil, err
+		}
+
+		req.Schema = &Schema{
+			Name:        options.Schema.Name,
+			Description: options.Schema.Description,
+
+			Strict: options.Schema.Strict,
+
+			Schema: string(schema),
+		}
+	}
+
+	for _, t := range options.Tools {
+		parameters, err := json.Marshal(t.Parameters)
+
+		if err != nil {
+			return nil, err
+		}
+
+		req.Tools = append(req.Tools, &Tool{
+			Name:        t.Name,
+			Description: t.Description,
+
+			Strict: t.Strict,
+
+			Parameters: string(parameters),
+		})
+	}
+
+	for _, m := range messages {
+		message := &Message{
+			Role:    string(m.Role),
+			Content: m.Content,
+
+			Reasoning:          m.Reasoning,
+			ReasoningSignature: m.ReasoningSignature,
+
+			Tool: m.Tool,
+		}
+
+		for i, f := range m.Files {
+			data, err := readFile(&m.Files[i])
+
+			if err != nil {
+				return nil, err
+			}
+
+			message.Files = append(message.Files, &File{
+				Name:        f.Name,
+				ContentType: f.ContentType,
+
+				Content: data,
+			})
+		}
+
+		for _, t := range m.ToolCalls {
+			message.ToolCalls = append(message.ToolCalls, &ToolCall{
+				Id: t.ID,
+
+				Name:      t.Name,
+				Arguments: t.Arguments,
+			})
+		}
+
+		req.Messages = append(req.Messages, message)
+	}
+
+	return req, nil
+}
+
+// readFile reads the content of a file and leaves it readable again,
+// as the same messages are sent again on retries and later turns of a conversation
+func readFile(f *provider.File) ([]byte, error) {
+	if s, ok := f.Content.(io.ReadSeeker); ok {
+		offset, err := s.Seek(0, io.SeekCurrent)
+
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := io.ReadAll(s)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := s.Seek(offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+
+		return data, nil
+	}
+
+	data, err := io.ReadAll(f.Content)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// readers which cannot be rewound are replaced in the shared message
+	f.Content = bytes.NewReader(data)
+
+	return data, nil
+}
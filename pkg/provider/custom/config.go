@@ -0,0 +1,45 @@
+package custom
+
+import (
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type Config struct {
+	url   string
+	model string
+
+	client ProviderClient
+}
+
+type Option func(*Config)
+
+func newConfig(url, model string, options ...Option) (*Config, error) {
+	if url == "" || !strings.HasPrefix(url, "grpc://") {
+		return nil, errors.New("invalid url")
+	}
+
+	cfg := &Config{
+		url:   url,
+		model: model,
+	}
+
+	for _, option := range options {
+		option(cfg)
+	}
+
+	conn, err := grpc.NewClient(strings.TrimPrefix(cfg.url, "grpc://"),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.client = NewProviderClient(conn)
+
+	return cfg, nil
+}
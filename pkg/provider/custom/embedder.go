@@ -0,0 +1,51 @@
+package custom
+
+import (
+	"context"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+var _ provider.Embedder = (*Embedder)(nil)
+
+type Embedder struct {
+	*Config
+}
+
+func NewEmbedder(url, model string, options ...Option) (*Embedder, error) {
+	cfg, err := newConfig(url, model, options...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Embedder{
+		Config: cfg,
+	}, nil
+}
+
+func (e *Embedder) Embed(ctx context.Context, texts []string) (*provider.Embedding, error) {
+	resp, err := e.client.Embed(ctx, &EmbedRequest{
+		Model: e.model,
+		Texts: texts,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := &provider.Embedding{}
+
+	for _, e := range resp.Embeddings {
+		result.Embeddings = append(result.Embeddings, e.Data)
+	}
+
+	if resp.Usage != nil {
+		result.Usage = &provider.Usage{
+			InputTokens:  int(resp.Usage.InputTokens),
+			OutputTokens: int(resp.Usage.OutputTokens),
+		}
+	}
+
+	return result, nil
+}
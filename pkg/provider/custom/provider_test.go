@@ -0,0 +1,314 @@
+package custom
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/to"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type testServer struct {
+	UnimplementedProviderServer
+
+	mu sync.Mutex
+
+	completions []*CompleteRequest
+	transcripts [][]*TranscribeRequest
+}
+
+func (s *testServer) Complete(req *CompleteRequest, stream grpc.ServerStreamingServer[Completion]) error {
+	s.mu.Lock()
+	s.completions = append(s.completions, req)
+	s.mu.Unlock()
+
+	chunks := []*Completion{
+		{Id: "1", Message: &Message{Content: "Let me "}},
+		{Message: &Message{Content: "check."}},
+		{Message: &Message{ToolCalls: []*ToolCall{{Id: "call_1", Name: "weather", Arguments: `{"city":`}}}},
+		{Message: &Message{ToolCalls: []*ToolCall{{Arguments: `"Bern"}`}}}},
+		{Message: &Message{ToolCalls: []*ToolCall{{Id: "call_2", Name: "time"}, {Arguments: `{}`}}}},
+		{Reason: string(provider.CompletionReasonTool), Usage: &Usage{InputTokens: 10, OutputTokens: 5}},
+	}
+
+	for _, c := range chunks {
+		if err := stream.Send(c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *testServer) Embed(ctx context.Context, req *EmbedRequest) (*Embeddings, error) {
+	result := &Embeddings{
+		Usage: &Usage{InputTokens: int32(len(req.Texts))},
+	}
+
+	for _, t := range req.Texts {
+		result.Embeddings = append(result.Embeddings, &Embedding{Data: []float32{float32(len(t)), 1}})
+	}
+
+	return result, nil
+}
+
+func (s *testServer) Rerank(ctx context.Context, req *RerankRequest) (*Rankings, error) {
+	result := &Rankings{}
+
+	for _, t := range req.Texts {
+		if strings.Contains(t, req.Query) {
+			result.Rankings = append(result.Rankings, &Ranking{Text: t, Score: 1})
+		}
+	}
+
+	if req.Limit != nil && len(result.Rankings) > int(*req.Limit) {
+		result.Rankings = result.Rankings[:*req.Limit]
+	}
+
+	return result, nil
+}
+
+func (s *testServer) Transcribe(stream grpc.ClientStreamingServer[TranscribeRequest, Transcription]) error {
+	var chunks []*TranscribeRequest
+	var size int
+
+	for {
+		req, err := stream.Recv()
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		chunks = append(chunks, req)
+		size += len(req.Data)
+	}
+
+	s.mu.Lock()
+	s.transcripts = append(s.transcripts, chunks)
+	s.mu.Unlock()
+
+	return stream.SendAndClose(&Transcription{
+		Id:       "1",
+		Text:     strings.Repeat("a", size/(1<<20)),
+		Language: chunks[0].Language,
+	})
+}
+
+func (s *testServer) Synthesize(req *SynthesizeRequest, stream grpc.ServerStreamingServer[Synthesis]) error {
+	if err := stream.Send(&Synthesis{Id: "1", Name: "speech." + req.Format, ContentType: "audio/" + req.Format}); err != nil {
+		return err
+	}
+
+	for _, word := range strings.Fields(req.Input) {
+		if err := stream.Send(&Synthesis{Data: []byte(word)}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func newTestConfig(t *testing.T) (*Config, *testServer) {
+	listener := bufconn.Listen(1 << 20)
+
+	s := &testServer{}
+
+	server := grpc.NewServer()
+	RegisterProviderServer(server, s)
+
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return &Config{
+		model:  "test",
+		client: NewProviderClient(conn),
+	}, s
+}
+
+func TestComplete(t *testing.T) {
+	cfg, s := newTestConfig(t)
+	c := &Completer{Config: cfg}
+
+	var deltas []provider.Completion
+
+	result, err := c.Complete(context.Background(), []provider.Message{
+		{Role: provider.MessageRoleUser, Content: "Weather in Bern?"},
+	}, &provider.CompleteOptions{
+		Stream: func(ctx context.Context, completion provider.Completion) error {
+			deltas = append(deltas, completion)
+			return nil
+		},
+
+		Tools: []provider.Tool{
+			{Name: "weather", Parameters: map[string]any{"type": "object"}},
+		},
+
+		MaxTokens: to.Ptr(100),
+	})
+
+	require.NoError(t, err)
+
+	require.Equal(t, "1", result.ID)
+	require.Equal(t, provider.CompletionReasonTool, result.Reason)
+	require.Equal(t, "Let me check.", result.Message.Content)
+
+	// tool calls without id continue the previous call
+	require.Equal(t, []provider.ToolCall{
+		{ID: "call_1", Name: "weather", Arguments: `{"city":"Bern"}`},
+		{ID: "call_2", Name: "time", Arguments: `{}`},
+	}, result.Message.ToolCalls)
+
+	require.Equal(t, &provider.Usage{InputTokens: 10, OutputTokens: 5}, result.Usage)
+
+	require.Len(t, deltas, 6)
+	require.Equal(t, "Let me ", deltas[0].Message.Content)
+	require.Equal(t, "1", deltas[1].ID)
+	require.Nil(t, deltas[4].Usage)
+	require.Equal(t, result.Usage, deltas[5].Usage)
+
+	req := s.completions[0]
+
+	require.Equal(t, "test", req.Model)
+	require.Equal(t, int32(100), *req.MaxTokens)
+	require.Equal(t, `{"type":"object"}`, req.Tools[0].Parameters)
+	require.Equal(t, "Weather in Bern?", req.Messages[0].Content)
+}
+
+func TestCompleteFiles(t *testing.T) {
+	cfg, s := newTestConfig(t)
+	c := &Completer{Config: cfg}
+
+	messages := []provider.Message{
+		{
+			Role:    provider.MessageRoleUser,
+			Content: "Describe these",
+
+			Files: []provider.File{
+				{Name: "a.txt", ContentType: "text/plain", Content: bytes.NewReader([]byte("first"))},
+				{Name: "b.txt", ContentType: "text/plain", Content: io.MultiReader(strings.NewReader("second"))},
+			},
+		},
+	}
+
+	// the same messages are sent again, like on a retry or the next turn of an agent
+	for range 2 {
+		_, err := c.Complete(context.Background(), messages, nil)
+		require.NoError(t, err)
+	}
+
+	require.Len(t, s.completions, 2)
+
+	for _, req := range s.completions {
+		files := req.Messages[0].Files
+
+		require.Len(t, files, 2)
+		require.Equal(t, "a.txt", files[0].Name)
+		require.Equal(t, []byte("first"), files[0].Content)
+		require.Equal(t, []byte("second"), files[1].Content)
+	}
+}
+
+func TestEmbed(t *testing.T) {
+	cfg, _ := newTestConfig(t)
+	e := &Embedder{Config: cfg}
+
+	result, err := e.Embed(context.Background(), []string{"a", "bb", "ccc"})
+	require.NoError(t, err)
+
+	require.Equal(t, [][]float32{{1, 1}, {2, 1}, {3, 1}}, result.Embeddings)
+	require.Equal(t, &provider.Usage{InputTokens: 3}, result.Usage)
+}
+
+func TestRerank(t *testing.T) {
+	cfg, _ := newTestConfig(t)
+	r := &Reranker{Config: cfg}
+
+	result, err := r.Rerank(context.Background(), "cat", []string{"a cat", "a dog", "cats"}, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, []provider.Ranking{
+		{Text: "a cat", Score: 1},
+		{Text: "cats", Score: 1},
+	}, result)
+
+	result, err = r.Rerank(context.Background(), "cat", []string{"a cat", "a dog", "cats"}, &provider.RerankOptions{Limit: to.Ptr(1)})
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+}
+
+func TestTranscribe(t *testing.T) {
+	cfg, s := newTestConfig(t)
+	tr := &Transcriber{Config: cfg}
+
+	// audio larger than a chunk is split, with the options only in the first chunk
+	audio := make([]byte, 5<<19)
+
+	result, err := tr.Transcribe(context.Background(), provider.File{
+		Name:        "audio.wav",
+		ContentType: "audio/wav",
+		Content:     bytes.NewReader(audio),
+	}, &provider.TranscribeOptions{
+		Language: "de",
+	})
+
+	require.NoError(t, err)
+
+	require.Equal(t, "1", result.ID)
+	require.Equal(t, "de", result.Language)
+	require.Equal(t, "aa", result.Text)
+
+	chunks := s.transcripts[0]
+	require.Len(t, chunks, 3)
+
+	require.Equal(t, "test", chunks[0].Model)
+	require.Equal(t, "audio.wav", chunks[0].Name)
+	require.Equal(t, "audio/wav", chunks[0].ContentType)
+	require.Len(t, chunks[0].Data, 1<<20)
+
+	require.Empty(t, chunks[1].Name)
+	require.Len(t, chunks[1].Data, 1<<20)
+	require.Len(t, chunks[2].Data, 1<<19)
+}
+
+func TestSynthesize(t *testing.T) {
+	cfg, _ := newTestConfig(t)
+	sy := &Synthesizer{Config: cfg}
+
+	result, err := sy.Synthesize(context.Background(), "hello wide world", &provider.SynthesizeOptions{
+		Format: provider.AudioFormatWAV,
+	})
+
+	require.NoError(t, err)
+	defer result.Reader.Close()
+
+	require.Equal(t, "speech.wav", result.Name)
+
+	data, err := io.ReadAll(result.Reader)
+	require.NoError(t, err)
+	require.Equal(t, "hellowideworld", string(data))
+}
@@ -0,0 +1,60 @@
+package custom
+
+import (
+	"context"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/to"
+)
+
+var _ provider.Reranker = (*Reranker)(nil)
+
+type Reranker struct {
+	*Config
+}
+
+func NewReranker(url, model string, options ...Option) (*Reranker, error) {
+	cfg, err := newConfig(url, model, options...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reranker{
+		Config: cfg,
+	}, nil
+}
+
+func (r *Reranker) Rerank(ctx context.Context, query string, texts []string, options *provider.RerankOptions) ([]provider.Ranking, error) {
+	if options == nil {
+		options = new(provider.RerankOptions)
+	}
+
+	req := &RerankRequest{
+		Model: r.model,
+
+		Query: query,
+		Texts: texts,
+	}
+
+	if options.Limit != nil {
+		req.Limit = to.Ptr(int32(*options.Limit))
+	}
+
+	resp, err := r.client.Rerank(ctx, req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var result []provider.Ranking
+
+	for _, r := range resp.Rankings {
+		result = append(result, provider.Ranking{
+			Text:  r.Text,
+			Score: r.Score,
+		})
+	}
+
+	return result, nil
+}
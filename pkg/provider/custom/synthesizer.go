@@ -0,0 +1,108 @@
+package custom
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+var _ provider.Synthesizer = (*Synthesizer)(nil)
+
+type Synthesizer struct {
+	*Config
+}
+
+func NewSynthesizer(url, model string, options ...Option) (*Synthesizer, error) {
+	cfg, err := newConfig(url, model, options...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Synthesizer{
+		Config: cfg,
+	}, nil
+}
+
+func (s *Synthesizer) Synthesize(ctx context.Context, input string, options *provider.SynthesizeOptions) (*provider.Synthesis, error) {
+	if options == nil {
+		options = new(provider.SynthesizeOptions)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	stream, err := s.client.Synthesize(ctx, &SynthesizeRequest{
+		Model: s.model,
+
+		Input: input,
+		Voice: options.Voice,
+
+		Format: string(options.Format),
+	})
+
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	// the first chunk carries the metadata and surfaces errors before the audio is returned
+	first, err := stream.Recv()
+
+	if err != nil && !errors.Is(err, io.EOF) {
+		cancel()
+		return nil, err
+	}
+
+	if first == nil {
+		first = &Synthesis{}
+	}
+
+	reader, writer := io.Pipe()
+
+	go func() {
+		defer cancel()
+
+		chunk := first
+
+		for {
+			if len(chunk.Data) > 0 {
+				if _, err := writer.Write(chunk.Data); err != nil {
+					return
+				}
+			}
+
+			next, err := stream.Recv()
+
+			if errors.Is(err, io.EOF) {
+				writer.Close()
+				return
+			}
+
+			if err != nil {
+				writer.CloseWithError(err)
+				return
+			}
+
+			chunk = next
+		}
+	}()
+
+	return &provider.Synthesis{
+		ID:   first.Id,
+		Name: first.Name,
+
+		Reader: &synthesisReader{reader, cancel},
+	}, nil
+}
+
+type synthesisReader struct {
+	*io.PipeReader
+	cancel context.CancelFunc
+}
+
+func (r *synthesisReader) Close() error {
+	r.cancel()
+	return r.PipeReader.Close()
+}
@@ -0,0 +1,89 @@
+package custom
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+var _ provider.Transcriber = (*Transcriber)(nil)
+
+type Transcriber struct {
+	*Config
+}
+
+func NewTranscriber(url, model string, options ...Option) (*Transcriber, error) {
+	cfg, err := newConfig(url, model, options...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transcriber{
+		Config: cfg,
+	}, nil
+}
+
+func (t *Transcriber) Transcribe(ctx context.Context, input provider.File, options *provider.TranscribeOptions) (*provider.Transcription, error) {
+	if options == nil {
+		options = new(provider.TranscribeOptions)
+	}
+
+	stream, err := t.client.Transcribe(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req := &TranscribeRequest{
+		Model: t.model,
+
+		Name:        input.Name,
+		ContentType: input.ContentType,
+
+		Language:    options.Language,
+		Temperature: options.Temperature,
+	}
+
+	// audio is sent in chunks to stay below the grpc message size limit
+	buf := make([]byte, 1<<20)
+
+	for {
+		n, err := io.ReadFull(input.Content, buf)
+
+		if n > 0 {
+			req.Data = buf[:n]
+
+			if err := stream.Send(req); err != nil {
+				return nil, err
+			}
+
+			req = &TranscribeRequest{}
+		}
+
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := stream.CloseAndRecv()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &provider.Transcription{
+		ID: resp.Id,
+
+		Text:     resp.Text,
+		Language: resp.Language,
+
+		Duration: resp.Duration,
+	}, nil
+}
@@ -1,7 +1,9 @@
 package elevenlabs
 
 import (
+	"bytes"
 	"context"
+	"io"
 	"net/http"
 	"net/url"
 	"strings"
@@ -25,6 +27,10 @@ func NewSynthesizer(url, model string, options ...Option) (*Synthesizer, error)
 		model: model,
 	}
 
+	if url != "" {
+		cfg.url = url
+	}
+
 	for _, option := range options {
 		option(cfg)
 	}
@@ -41,6 +47,11 @@ func (s *Synthesizer) Synthesize(ctx context.Context, content string, options *p
 
 	u, _ := url.Parse(strings.TrimRight(s.url, "/") + "/v1/text-to-speech/" + s.model)
 
+	// elevenlabs returns wav audio as raw pcm, which gets its header below
+	if options.Format == provider.AudioFormatWAV {
+		u.RawQuery = url.Values{"output_format": {"pcm_24000"}}.Encode()
+	}
+
 	body := map[string]any{
 		"text":     content,
 		"model_id": "eleven_multilingual_v2",
@@ -61,6 +72,23 @@ func (s *Synthesizer) Synthesize(ctx context.Context, content string, options *p
 
 	id := uuid.NewString()
 
+	if options.Format == provider.AudioFormatWAV {
+		defer resp.Body.Close()
+
+		data, err := io.ReadAll(resp.Body)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &provider.Synthesis{
+			ID: id,
+
+			Name:   id + ".wav",
+			Reader: io.NopCloser(bytes.NewReader(encodeWAV(data, 24000))),
+		}, nil
+	}
+
 	return &provider.Synthesis{
 		ID: id,
 
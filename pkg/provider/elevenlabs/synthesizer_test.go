@@ -0,0 +1,44 @@
+package elevenlabs_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/provider/elevenlabs"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSynthesizeWAV(t *testing.T) {
+	var format string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		format = r.URL.Query().Get("output_format")
+		w.Write([]byte{1, 0, 2, 0})
+	}))
+
+	defer server.Close()
+
+	s, err := elevenlabs.NewSynthesizer(server.URL, "voice")
+	require.NoError(t, err)
+
+	synthesis, err := s.Synthesize(context.Background(), "hello", &provider.SynthesizeOptions{
+		Format: provider.AudioFormatWAV,
+	})
+
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(synthesis.Reader)
+	require.NoError(t, err)
+
+	require.Equal(t, "pcm_24000", format)
+
+	require.Len(t, data, 44+4)
+	require.Equal(t, "RIFF", string(data[0:4]))
+	require.Equal(t, "WAVE", string(data[8:12]))
+	require.Equal(t, []byte{1, 0, 2, 0}, data[44:])
+}
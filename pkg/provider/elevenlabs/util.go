@@ -2,20 +2,27 @@ package elevenlabs
 
 import (
 	"bytes"
+	"encoding/binary"
 	"encoding/json"
-	"errors"
 	"io"
 	"net/http"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
 )
 
 func convertError(resp *http.Response) error {
 	data, _ := io.ReadAll(resp.Body)
 
-	if len(data) == 0 {
-		return errors.New(http.StatusText(resp.StatusCode))
+	message := string(data)
+
+	if message == "" {
+		message = http.StatusText(resp.StatusCode)
 	}
 
-	return errors.New(string(data))
+	return &provider.StatusError{
+		StatusCode: resp.StatusCode,
+		Message:    message,
+	}
 }
 
 func jsonReader(v any) io.Reader {
@@ -27,3 +34,27 @@ func jsonReader(v any) io.Reader {
 	enc.Encode(v)
 	return b
 }
+
+// encodeWAV wraps 16-bit mono pcm audio in a wav header
+func encodeWAV(data []byte, rate int) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(36+len(data)))
+	buf.WriteString("WAVE")
+
+	buf.WriteString("fmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16))
+	binary.Write(&buf, binary.LittleEndian, uint16(1)) // pcm
+	binary.Write(&buf, binary.LittleEndian, uint16(1)) // mono
+	binary.Write(&buf, binary.LittleEndian, uint32(rate))
+	binary.Write(&buf, binary.LittleEndian, uint32(rate*2))
+	binary.Write(&buf, binary.LittleEndian, uint16(2))
+	binary.Write(&buf, binary.LittleEndian, uint16(16))
+
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(len(data)))
+	buf.Write(data)
+
+	return buf.Bytes()
+}
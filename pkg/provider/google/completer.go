@@ -41,6 +41,10 @@ func (c *Completer) Complete(ctx context.Context, messages []provider.Message, o
 		options = new(provider.CompleteOptions)
 	}
 
+	if err := options.Unsupported("parallel_tool_calls", "presence_penalty", "frequency_penalty", "seed", "logprobs"); err != nil {
+		return nil, err
+	}
+
 	client, err := genai.NewClient(ctx, c.Options()...)
 
 	if err != nil {
@@ -68,6 +72,10 @@ func (c *Completer) Complete(ctx context.Context, messages []provider.Message, o
 		model.Tools = convertTools(options.Tools)
 	}
 
+	if options.ToolChoice != nil {
+		model.ToolConfig = convertToolConfig(options.ToolChoice)
+	}
+
 	if len(options.Stop) > 0 {
 		model.StopSequences = options.Stop
 	}
@@ -80,6 +88,10 @@ func (c *Completer) Complete(ctx context.Context, messages []provider.Message, o
 		model.SetTemperature(*options.Temperature)
 	}
 
+	if options.TopP != nil {
+		model.SetTopP(*options.TopP)
+	}
+
 	if options.Format == provider.CompletionFormatJSON || options.Schema != nil {
 		model.ResponseMIMEType = "application/json"
 
@@ -369,6 +381,29 @@ func convertTools(tools []provider.Tool) []*genai.Tool {
 	}
 }
 
+func convertToolConfig(choice *provider.ToolChoice) *genai.ToolConfig {
+	config := &genai.FunctionCallingConfig{
+		Mode: genai.FunctionCallingAuto,
+	}
+
+	switch choice.Mode {
+	case provider.ToolChoiceModeNone:
+		config.Mode = genai.FunctionCallingNone
+
+	case provider.ToolChoiceModeRequired:
+		config.Mode = genai.FunctionCallingAny
+	}
+
+	if choice.Name != "" {
+		config.Mode = genai.FunctionCallingAny
+		config.AllowedFunctionNames = []string{choice.Name}
+	}
+
+	return &genai.ToolConfig{
+		FunctionCallingConfig: config,
+	}
+}
+
 func convertSchema(parameters map[string]any) *genai.Schema {
 	if len(parameters) == 0 {
 		return nil
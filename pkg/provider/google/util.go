@@ -3,6 +3,8 @@ package google
 import (
 	"errors"
 
+	"github.com/adrianliechti/wingman/pkg/provider"
+
 	"google.golang.org/api/googleapi"
 )
 
@@ -10,7 +12,10 @@ func convertError(err error) error {
 	var apierr *googleapi.Error
 
 	if errors.As(err, &apierr) {
-		return errors.New(apierr.Body)
+		return &provider.StatusError{
+			StatusCode: apierr.Code,
+			Message:    apierr.Body,
+		}
 	}
 
 	return err
@@ -3,19 +3,25 @@ package jina
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
 	"io"
 	"net/http"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
 )
 
 func convertError(resp *http.Response) error {
 	data, _ := io.ReadAll(resp.Body)
 
-	if len(data) == 0 {
-		return errors.New(http.StatusText(resp.StatusCode))
+	message := string(data)
+
+	if message == "" {
+		message = http.StatusText(resp.StatusCode)
 	}
 
-	return errors.New(string(data))
+	return &provider.StatusError{
+		StatusCode: resp.StatusCode,
+		Message:    message,
+	}
 }
 
 func jsonReader(v any) io.Reader {
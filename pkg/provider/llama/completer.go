@@ -23,7 +23,9 @@ func NewCompleter(url, model string, options ...Option) (*Completer, error) {
 		option(cfg)
 	}
 
-	opts := []openai.Option{}
+	opts := []openai.Option{
+		openai.WithReasoningTags(),
+	}
 
 	if cfg.client != nil {
 		opts = append(opts, openai.WithClient(cfg.client))
@@ -9,7 +9,12 @@ type Completer = openai.Completer
 func NewCompleter(model string, options ...Option) (*Completer, error) {
 	url := "https://api.mistral.ai/v1/"
 
-	cfg := &Config{}
+	cfg := &Config{
+		options: []openai.Option{
+			openai.WithUnsupported("logprobs"),
+			openai.WithSeedParameter("random_seed"),
+		},
+	}
 
 	for _, option := range options {
 		option(cfg)
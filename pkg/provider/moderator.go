@@ -0,0 +1,20 @@
+package provider
+
+import (
+	"context"
+)
+
+type Moderator interface {
+	Moderate(ctx context.Context, inputs []string, options *ModerateOptions) ([]Moderation, error)
+}
+
+type ModerateOptions struct {
+}
+
+type Moderation struct {
+	Flagged bool
+
+	// Categories tells per category whether the input was flagged, Scores holds the confidence between 0 and 1
+	Categories map[string]bool
+	Scores     map[string]float64
+}
@@ -16,7 +16,13 @@ func NewCompleter(url, model string, options ...Option) (*Completer, error) {
 	url = strings.TrimRight(url, "/")
 	url = strings.TrimSuffix(url, "/v1")
 
-	cfg := &Config{}
+	cfg := &Config{
+		options: []openai.Option{
+			// the openai compatible api of ollama silently ignores these
+			openai.WithUnsupported("tool_choice", "parallel_tool_calls", "logprobs"),
+			openai.WithReasoningTags(),
+		},
+	}
 
 	for _, option := range options {
 		option(cfg)
@@ -6,10 +6,12 @@ import (
 	"errors"
 	"io"
 	"slices"
+	"strings"
 
 	"github.com/adrianliechti/wingman/pkg/provider"
 
 	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
 )
 
 var _ provider.Completer = (*Completer)(nil)
@@ -40,21 +42,31 @@ func (c *Completer) Complete(ctx context.Context, messages []provider.Message, o
 		options = new(provider.CompleteOptions)
 	}
 
+	if err := options.Unsupported(c.unsupported...); err != nil {
+		return nil, err
+	}
+
 	req, err := c.convertCompletionRequest(messages, options)
 
 	if err != nil {
 		return nil, err
 	}
 
+	var opts []option.RequestOption
+
+	if options.Seed != nil && c.seedParameter != "" {
+		opts = append(opts, option.WithJSONSet(c.seedParameter, *options.Seed))
+	}
+
 	if options.Stream != nil {
-		return c.completeStream(ctx, *req, options)
+		return c.completeStream(ctx, *req, options, opts...)
 	}
 
-	return c.complete(ctx, *req, options)
+	return c.complete(ctx, *req, options, opts...)
 }
 
-func (c *Completer) complete(ctx context.Context, req openai.ChatCompletionNewParams, options *provider.CompleteOptions) (*provider.Completion, error) {
-	completion, err := c.completions.New(ctx, req)
+func (c *Completer) complete(ctx context.Context, req openai.ChatCompletionNewParams, options *provider.CompleteOptions, opts ...option.RequestOption) (*provider.Completion, error) {
+	completion, err := c.completions.New(ctx, req, opts...)
 
 	if err != nil {
 		return nil, convertError(err)
@@ -67,17 +79,28 @@ func (c *Completer) complete(ctx context.Context, req openai.ChatCompletionNewPa
 		reason = provider.CompletionReasonStop
 	}
 
+	content := choice.Message.Content
+	reasoning := toReasoning(choice.Message)
+
+	if c.reasoningTags && reasoning == "" {
+		reasoning, content = splitThink(content)
+	}
+
 	return &provider.Completion{
 		ID:     completion.ID,
 		Reason: reason,
 
 		Message: provider.Message{
 			Role:    provider.MessageRoleAssistant,
-			Content: choice.Message.Content,
+			Content: content,
+
+			Reasoning: reasoning,
 
 			ToolCalls: toToolCalls(choice.Message.ToolCalls),
 		},
 
+		Logprobs: toLogprobs(choice.Logprobs.Content),
+
 		Usage: &provider.Usage{
 			InputTokens:  int(completion.Usage.PromptTokens),
 			OutputTokens: int(completion.Usage.CompletionTokens),
@@ -85,11 +108,24 @@ func (c *Completer) complete(ctx context.Context, req openai.ChatCompletionNewPa
 	}, nil
 }
 
-func (c *Completer) completeStream(ctx context.Context, req openai.ChatCompletionNewParams, options *provider.CompleteOptions) (*provider.Completion, error) {
-	stream := c.completions.NewStreaming(ctx, req)
+func (c *Completer) completeStream(ctx context.Context, req openai.ChatCompletionNewParams, options *provider.CompleteOptions, opts ...option.RequestOption) (*provider.Completion, error) {
+	// without it, streams carry no usage and the accumulated usage stays zero
+	req.StreamOptions = openai.F(openai.ChatCompletionStreamOptionsParam{
+		IncludeUsage: openai.F(true),
+	})
+
+	stream := c.completions.NewStreaming(ctx, req, opts...)
 
 	completion := openai.ChatCompletionAccumulator{}
 
+	// the accumulator drops the non-standard reasoning fields
+	var reasoning strings.Builder
+	var parser *thinkParser
+
+	if c.reasoningTags {
+		parser = &thinkParser{}
+	}
+
 	for stream.Next() {
 		chunk := stream.Current()
 		completion.AddChunk(chunk)
@@ -100,16 +136,32 @@ func (c *Completer) completeStream(ctx context.Context, req openai.ChatCompletio
 
 		choice := chunk.Choices[0]
 
+		deltaContent := choice.Delta.Content
+		deltaReasoning := toDeltaReasoning(choice.Delta)
+
+		if parser != nil {
+			var tagReasoning string
+			tagReasoning, deltaContent = parser.Write(deltaContent)
+
+			deltaReasoning += tagReasoning
+		}
+
+		reasoning.WriteString(deltaReasoning)
+
 		delta := provider.Completion{
 			ID:     completion.ID,
 			Reason: toDeltaCompletionResult(choice.FinishReason),
 
 			Message: provider.Message{
 				Role:    provider.MessageRoleAssistant,
-				Content: choice.Delta.Content,
+				Content: deltaContent,
+
+				Reasoning: deltaReasoning,
 
 				ToolCalls: toDeltaToolCalls(choice.Delta.ToolCalls),
 			},
+
+			Logprobs: toLogprobs(choice.Logprobs.Content),
 		}
 
 		if err := options.Stream(ctx, delta); err != nil {
@@ -121,6 +173,27 @@ func (c *Completer) completeStream(ctx context.Context, req openai.ChatCompletio
 		return nil, convertError(err)
 	}
 
+	if parser != nil {
+		if tagReasoning, content := parser.Flush(); tagReasoning != "" || content != "" {
+			reasoning.WriteString(tagReasoning)
+
+			delta := provider.Completion{
+				ID: completion.ID,
+
+				Message: provider.Message{
+					Role:    provider.MessageRoleAssistant,
+					Content: content,
+
+					Reasoning: tagReasoning,
+				},
+			}
+
+			if err := options.Stream(ctx, delta); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	choice := completion.Choices[0]
 	reason := toCompletionResult(choice.FinishReason)
 
@@ -128,17 +201,27 @@ func (c *Completer) completeStream(ctx context.Context, req openai.ChatCompletio
 		reason = provider.CompletionReasonStop
 	}
 
+	content := choice.Message.Content
+
+	if parser != nil {
+		_, content = splitThink(content)
+	}
+
 	return &provider.Completion{
 		ID:     completion.ID,
 		Reason: reason,
 
 		Message: provider.Message{
 			Role:    provider.MessageRoleAssistant,
-			Content: choice.Message.Content,
+			Content: content,
+
+			Reasoning: reasoning.String(),
 
 			ToolCalls: toToolCalls(choice.Message.ToolCalls),
 		},
 
+		Logprobs: toLogprobs(choice.Logprobs.Content),
+
 		Usage: &provider.Usage{
 			InputTokens:  int(completion.Usage.PromptTokens),
 			OutputTokens: int(completion.Usage.CompletionTokens),
@@ -169,6 +252,14 @@ func (c *Completer) convertCompletionRequest(input []provider.Message, options *
 
 	if len(tools) > 0 {
 		req.Tools = openai.F(tools)
+
+		if options.ParallelToolCalls != nil {
+			req.ParallelToolCalls = openai.F(*options.ParallelToolCalls)
+		}
+	}
+
+	if options.ToolChoice != nil {
+		req.ToolChoice = openai.F(convertToolChoice(options.ToolChoice))
 	}
 
 	if len(messages) > 0 {
@@ -228,6 +319,31 @@ func (c *Completer) convertCompletionRequest(input []provider.Message, options *
 		req.Temperature = openai.F(float64(*options.Temperature))
 	}
 
+	if options.TopP != nil {
+		req.TopP = openai.F(float64(*options.TopP))
+	}
+
+	if options.PresencePenalty != nil {
+		req.PresencePenalty = openai.F(float64(*options.PresencePenalty))
+	}
+
+	if options.FrequencyPenalty != nil {
+		req.FrequencyPenalty = openai.F(float64(*options.FrequencyPenalty))
+	}
+
+	if options.Seed != nil && c.seedParameter == "" {
+		req.Seed = openai.F(int64(*options.Seed))
+	}
+
+	if options.Logprobs {
+		req.Logprobs = openai.F(true)
+	}
+
+	if options.TopLogprobs != nil {
+		req.Logprobs = openai.F(true)
+		req.TopLogprobs = openai.F(int64(*options.TopLogprobs))
+	}
+
 	return req, nil
 }
 
@@ -349,6 +465,51 @@ func convertTools(tools []provider.Tool) ([]openai.ChatCompletionToolParam, erro
 	return result, nil
 }
 
+func convertToolChoice(choice *provider.ToolChoice) openai.ChatCompletionToolChoiceOptionUnionParam {
+	if choice.Name != "" {
+		return openai.ChatCompletionNamedToolChoiceParam{
+			Type: openai.F(openai.ChatCompletionNamedToolChoiceTypeFunction),
+
+			Function: openai.F(openai.ChatCompletionNamedToolChoiceFunctionParam{
+				Name: openai.F(choice.Name),
+			}),
+		}
+	}
+
+	switch choice.Mode {
+	case provider.ToolChoiceModeNone:
+		return openai.ChatCompletionToolChoiceOptionAutoNone
+
+	case provider.ToolChoiceModeRequired:
+		return openai.ChatCompletionToolChoiceOptionAutoRequired
+
+	default:
+		return openai.ChatCompletionToolChoiceOptionAutoAuto
+	}
+}
+
+func toLogprobs(logprobs []openai.ChatCompletionTokenLogprob) []provider.Logprob {
+	var result []provider.Logprob
+
+	for _, l := range logprobs {
+		logprob := provider.Logprob{
+			Token:   l.Token,
+			Logprob: l.Logprob,
+		}
+
+		for _, t := range l.TopLogprobs {
+			logprob.TopLogprobs = append(logprob.TopLogprobs, provider.TopLogprob{
+				Token:   t.Token,
+				Logprob: t.Logprob,
+			})
+		}
+
+		result = append(result, logprob)
+	}
+
+	return result
+}
+
 func toDeltaToolCalls(calls []openai.ChatCompletionChunkChoicesDeltaToolCall) []provider.ToolCall {
 	var result []provider.ToolCall
 
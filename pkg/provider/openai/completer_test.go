@@ -0,0 +1,51 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompleteStreamUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		json.NewDecoder(r.Body).Decode(&req)
+
+		require.Equal(t, map[string]any{"include_usage": true}, req["stream_options"])
+
+		w.Header().Set("Content-Type", "text/event-stream")
+
+		for _, chunk := range []string{
+			`{"id":"1","object":"chat.completion.chunk","choices":[{"index":0,"delta":{"role":"assistant","content":"Hello"}}]}`,
+			`{"id":"1","object":"chat.completion.chunk","choices":[{"index":0,"delta":{},"finish_reason":"stop"}]}`,
+			`{"id":"1","object":"chat.completion.chunk","choices":[],"usage":{"prompt_tokens":3,"completion_tokens":1,"total_tokens":4}}`,
+		} {
+			fmt.Fprintf(w, "data: %s\n\n", chunk)
+		}
+
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+
+	defer server.Close()
+
+	c, err := NewCompleter(server.URL, "test")
+	require.NoError(t, err)
+
+	completion, err := c.Complete(context.Background(), []provider.Message{{Role: provider.MessageRoleUser, Content: "hi"}}, &provider.CompleteOptions{
+		Stream: func(ctx context.Context, completion provider.Completion) error {
+			return nil
+		},
+	})
+
+	require.NoError(t, err)
+
+	require.Equal(t, "Hello", completion.Message.Content)
+	require.Equal(t, &provider.Usage{InputTokens: 3, OutputTokens: 1}, completion.Usage)
+}
@@ -15,6 +15,12 @@ type Config struct {
 	model string
 
 	client *http.Client
+
+	// for openai compatible apis deviating from the openai parameters
+	unsupported   []string
+	seedParameter string
+
+	reasoningTags bool
 }
 
 type Option func(*Config)
@@ -31,6 +37,27 @@ func WithToken(token string) Option {
 	}
 }
 
+// WithUnsupported rejects requests using one of the named options instead of passing them on
+func WithUnsupported(options ...string) Option {
+	return func(c *Config) {
+		c.unsupported = append(c.unsupported, options...)
+	}
+}
+
+// WithSeedParameter sends the seed under a different parameter name
+func WithSeedParameter(name string) Option {
+	return func(c *Config) {
+		c.seedParameter = name
+	}
+}
+
+// WithReasoningTags separates a leading <think> block of the content as reasoning
+func WithReasoningTags() Option {
+	return func(c *Config) {
+		c.reasoningTags = true
+	}
+}
+
 func (c *Config) Options() []option.RequestOption {
 	if c.url == "" {
 		c.url = "https://api.openai.com/v1/"
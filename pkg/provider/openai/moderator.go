@@ -0,0 +1,73 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+
+	"github.com/openai/openai-go"
+)
+
+var _ provider.Moderator = (*Moderator)(nil)
+
+type Moderator struct {
+	*Config
+	moderations *openai.ModerationService
+}
+
+func NewModerator(url, model string, options ...Option) (*Moderator, error) {
+	cfg := &Config{
+		url:   url,
+		model: model,
+	}
+
+	for _, option := range options {
+		option(cfg)
+	}
+
+	return &Moderator{
+		Config:      cfg,
+		moderations: openai.NewModerationService(cfg.Options()...),
+	}, nil
+}
+
+func (m *Moderator) Moderate(ctx context.Context, inputs []string, options *provider.ModerateOptions) ([]provider.Moderation, error) {
+	if options == nil {
+		options = new(provider.ModerateOptions)
+	}
+
+	moderation, err := m.moderations.New(ctx, openai.ModerationNewParams{
+		Model: openai.F(m.model),
+		Input: openai.F[openai.ModerationNewParamsInputUnion](openai.ModerationNewParamsInputArray(inputs)),
+	})
+
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	var result []provider.Moderation
+
+	for _, r := range moderation.Results {
+		// categories are read from the raw response to keep the ones added after this sdk version
+		categories := map[string]bool{}
+		scores := map[string]float64{}
+
+		if err := json.Unmarshal([]byte(r.Categories.JSON.RawJSON()), &categories); err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal([]byte(r.CategoryScores.JSON.RawJSON()), &scores); err != nil {
+			return nil, err
+		}
+
+		result = append(result, provider.Moderation{
+			Flagged: r.Flagged,
+
+			Categories: categories,
+			Scores:     scores,
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,149 @@
+package openai
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/openai/openai-go"
+)
+
+const (
+	thinkStart = "<think>"
+	thinkEnd   = "</think>"
+)
+
+// toReasoning returns the non-standard reasoning field of compatible apis, e.g. deepseek, llama.cpp or ollama
+func toReasoning(message openai.ChatCompletionMessage) string {
+	return extraString(message.JSON.ExtraFields["reasoning_content"].Raw(), message.JSON.ExtraFields["reasoning"].Raw())
+}
+
+func toDeltaReasoning(delta openai.ChatCompletionChunkChoicesDelta) string {
+	return extraString(delta.JSON.ExtraFields["reasoning_content"].Raw(), delta.JSON.ExtraFields["reasoning"].Raw())
+}
+
+func extraString(values ...string) string {
+	for _, raw := range values {
+		var value string
+
+		if raw == "" || json.Unmarshal([]byte(raw), &value) != nil {
+			continue
+		}
+
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// splitThink separates a leading <think> block, as emitted by models like deepseek-r1, from the content
+func splitThink(content string) (string, string) {
+	trimmed := strings.TrimLeft(content, " \t\r\n")
+
+	if !strings.HasPrefix(trimmed, thinkStart) {
+		return "", content
+	}
+
+	trimmed = trimmed[len(thinkStart):]
+
+	reasoning, rest, found := strings.Cut(trimmed, thinkEnd)
+
+	if !found {
+		return trimmed, ""
+	}
+
+	return reasoning, strings.TrimLeft(rest, " \t\r\n")
+}
+
+type thinkState int
+
+const (
+	thinkStatePending thinkState = iota
+	thinkStateReasoning
+	thinkStateContent
+)
+
+// thinkParser separates a leading <think> block from streamed content, holding back fragments of a tag split across deltas
+type thinkParser struct {
+	state thinkState
+	buf   string
+
+	// trim drops the whitespace between the closing tag and the content
+	trim bool
+}
+
+func (p *thinkParser) Write(text string) (string, string) {
+	p.buf += text
+
+	switch p.state {
+	case thinkStatePending:
+		trimmed := strings.TrimLeft(p.buf, " \t\r\n")
+
+		if len(trimmed) < len(thinkStart) && strings.HasPrefix(thinkStart, trimmed) {
+			return "", ""
+		}
+
+		if !strings.HasPrefix(trimmed, thinkStart) {
+			content := p.buf
+
+			p.buf = ""
+			p.state = thinkStateContent
+
+			return "", content
+		}
+
+		p.buf = ""
+		p.state = thinkStateReasoning
+
+		return p.Write(trimmed[len(thinkStart):])
+
+	case thinkStateReasoning:
+		if reasoning, rest, found := strings.Cut(p.buf, thinkEnd); found {
+			p.buf = ""
+			p.state = thinkStateContent
+			p.trim = true
+
+			_, content := p.Write(rest)
+			return reasoning, content
+		}
+
+		// keep a possible start of the closing tag for the next delta
+		keep := 0
+
+		for i := len(thinkEnd) - 1; i > 0; i-- {
+			if strings.HasSuffix(p.buf, thinkEnd[:i]) {
+				keep = i
+				break
+			}
+		}
+
+		reasoning := p.buf[:len(p.buf)-keep]
+		p.buf = p.buf[len(p.buf)-keep:]
+
+		return reasoning, ""
+
+	default:
+		content := p.buf
+		p.buf = ""
+
+		if p.trim {
+			content = strings.TrimLeft(content, " \t\r\n")
+			p.trim = content == ""
+		}
+
+		return "", content
+	}
+}
+
+// Flush returns what is held back at the end of the stream
+func (p *thinkParser) Flush() (string, string) {
+	text := p.buf
+	p.buf = ""
+
+	if p.state == thinkStateReasoning {
+		return text, ""
+	}
+
+	return "", text
+}
@@ -0,0 +1,56 @@
+package openai
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitThink(t *testing.T) {
+	reasoning, content := splitThink("\n<think>step by step</think>\n\nanswer")
+
+	require.Equal(t, "step by step", reasoning)
+	require.Equal(t, "answer", content)
+
+	reasoning, content = splitThink("answer <think>not reasoning</think>")
+
+	require.Equal(t, "", reasoning)
+	require.Equal(t, "answer <think>not reasoning</think>", content)
+}
+
+func TestThinkParser(t *testing.T) {
+	parse := func(chunks ...string) (string, string) {
+		p := &thinkParser{}
+
+		var reasoning, content string
+
+		for _, c := range chunks {
+			r, c := p.Write(c)
+
+			reasoning += r
+			content += c
+		}
+
+		r, c := p.Flush()
+
+		return reasoning + r, content + c
+	}
+
+	// tags split across chunks
+	reasoning, content := parse("\n<th", "ink>step by", " step</th", "ink>", "\n\nthe", " answer")
+
+	require.Equal(t, "step by step", reasoning)
+	require.Equal(t, "the answer", content)
+
+	// no leading tag
+	reasoning, content = parse("the <think>", " answer")
+
+	require.Equal(t, "", reasoning)
+	require.Equal(t, "the <think> answer", content)
+
+	// stream ending while reasoning
+	reasoning, content = parse("<think>step", " by</")
+
+	require.Equal(t, "step by</", reasoning)
+	require.Equal(t, "", content)
+}
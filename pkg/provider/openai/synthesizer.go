@@ -37,13 +37,19 @@ func (s *Synthesizer) Synthesize(ctx context.Context, content string, options *p
 		options = new(provider.SynthesizeOptions)
 	}
 
+	format := openai.AudioSpeechNewParamsResponseFormatWAV
+
+	if options.Format == provider.AudioFormatMP3 {
+		format = openai.AudioSpeechNewParamsResponseFormatMP3
+	}
+
 	result, err := s.speech.New(ctx, openai.AudioSpeechNewParams{
 		Model: openai.F(s.model),
 		Input: openai.F(content),
 
 		Voice: openai.F(openai.AudioSpeechNewParamsVoiceAlloy),
 
-		ResponseFormat: openai.F(openai.AudioSpeechNewParamsResponseFormatWAV),
+		ResponseFormat: openai.F(format),
 	})
 
 	if err != nil {
@@ -55,7 +61,7 @@ func (s *Synthesizer) Synthesize(ctx context.Context, content string, options *p
 	return &provider.Synthesis{
 		ID: id,
 
-		Name:   id + ".wav",
+		Name:   id + "." + string(format),
 		Reader: result.Body,
 	}, nil
 }
@@ -2,12 +2,59 @@ package provider
 
 import (
 	"io"
+	"slices"
 )
 
 type Provider = any
 
 type Model struct {
 	ID string
+
+	Type ModelType
+
+	Name        string
+	Description string
+
+	// Provider is the type of the owning provider, router or chain
+	Provider string
+
+	Capabilities []Capability
+}
+
+type ModelType string
+
+const (
+	ModelTypeAuto        ModelType = ""
+	ModelTypeCompleter   ModelType = "completer"
+	ModelTypeEmbedder    ModelType = "embedder"
+	ModelTypeModerator   ModelType = "moderator"
+	ModelTypeRenderer    ModelType = "renderer"
+	ModelTypeReranker    ModelType = "reranker"
+	ModelTypeSynthesizer ModelType = "synthesizer"
+	ModelTypeTranscriber ModelType = "transcriber"
+)
+
+type Capability string
+
+const (
+	CapabilityTools     Capability = "tools"
+	CapabilityVision    Capability = "vision"
+	CapabilitySchema    Capability = "json_schema"
+	CapabilityStreaming Capability = "streaming"
+)
+
+func (m Model) HasCapability(c Capability) bool {
+	return slices.Contains(m.Capabilities, c)
+}
+
+// StatusError is a failed http response of a provider
+type StatusError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *StatusError) Error() string {
+	return e.Message
 }
 
 type File struct {
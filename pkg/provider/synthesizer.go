@@ -11,8 +11,18 @@ type Synthesizer interface {
 
 type SynthesizeOptions struct {
 	Voice string
+
+	// Format is the audio format to return, providers use their default if empty
+	Format AudioFormat
 }
 
+type AudioFormat string
+
+const (
+	AudioFormatMP3 AudioFormat = "mp3"
+	AudioFormatWAV AudioFormat = "wav"
+)
+
 type Synthesis struct {
 	ID string
 
@@ -0,0 +1,48 @@
+package failover
+
+import (
+	"context"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+type Completer struct {
+	*router
+	completers []provider.Completer
+}
+
+func NewCompleter(completers []provider.Completer, options ...Option) (provider.Completer, error) {
+	r, err := newRouter(len(completers), options...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Completer{
+		router:     r,
+		completers: completers,
+	}, nil
+}
+
+func (c *Completer) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	if options == nil {
+		options = new(provider.CompleteOptions)
+	}
+
+	return execute(ctx, c.router, func(index int) (*provider.Completion, bool, error) {
+		streamed := false
+		attempt := *options
+
+		if options.Stream != nil {
+			attempt.Stream = func(ctx context.Context, completion provider.Completion) error {
+				streamed = true
+				return options.Stream(ctx, completion)
+			}
+		}
+
+		completion, err := c.completers[index].Complete(ctx, messages, &attempt)
+
+		// once a partial answer reached the client, switching backends would duplicate output
+		return completion, streamed, err
+	})
+}
@@ -0,0 +1,32 @@
+package failover
+
+import (
+	"context"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+type Embedder struct {
+	*router
+	embedders []provider.Embedder
+}
+
+func NewEmbedder(embedders []provider.Embedder, options ...Option) (provider.Embedder, error) {
+	r, err := newRouter(len(embedders), options...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Embedder{
+		router:    r,
+		embedders: embedders,
+	}, nil
+}
+
+func (e *Embedder) Embed(ctx context.Context, texts []string) (*provider.Embedding, error) {
+	return execute(ctx, e.router, func(index int) (*provider.Embedding, bool, error) {
+		embedding, err := e.embedders[index].Embed(ctx, texts)
+		return embedding, false, err
+	})
+}
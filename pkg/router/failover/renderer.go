@@ -0,0 +1,32 @@
+package failover
+
+import (
+	"context"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+type Renderer struct {
+	*router
+	renderers []provider.Renderer
+}
+
+func NewRenderer(renderers []provider.Renderer, options ...Option) (provider.Renderer, error) {
+	r, err := newRouter(len(renderers), options...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Renderer{
+		router:    r,
+		renderers: renderers,
+	}, nil
+}
+
+func (r *Renderer) Render(ctx context.Context, input string, options *provider.RenderOptions) (*provider.Image, error) {
+	return execute(ctx, r.router, func(index int) (*provider.Image, bool, error) {
+		image, err := r.renderers[index].Render(ctx, input, options)
+		return image, false, err
+	})
+}
@@ -0,0 +1,32 @@
+package failover
+
+import (
+	"context"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+type Reranker struct {
+	*router
+	rerankers []provider.Reranker
+}
+
+func NewReranker(rerankers []provider.Reranker, options ...Option) (provider.Reranker, error) {
+	r, err := newRouter(len(rerankers), options...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reranker{
+		router:    r,
+		rerankers: rerankers,
+	}, nil
+}
+
+func (r *Reranker) Rerank(ctx context.Context, query string, texts []string, options *provider.RerankOptions) ([]provider.Ranking, error) {
+	return execute(ctx, r.router, func(index int) ([]provider.Ranking, bool, error) {
+		rankings, err := r.rerankers[index].Rerank(ctx, query, texts, options)
+		return rankings, false, err
+	})
+}
@@ -0,0 +1,109 @@
+package failover
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+type router struct {
+	size int
+
+	cooldown time.Duration
+
+	mu        sync.Mutex
+	unhealthy map[int]time.Time
+}
+
+type Option func(*router)
+
+func WithCooldown(cooldown time.Duration) Option {
+	return func(r *router) {
+		r.cooldown = cooldown
+	}
+}
+
+func newRouter(size int, options ...Option) (*router, error) {
+	r := &router{
+		size: size,
+
+		cooldown:  30 * time.Second,
+		unhealthy: make(map[int]time.Time),
+	}
+
+	for _, option := range options {
+		option(r)
+	}
+
+	if r.size == 0 {
+		return nil, errors.New("no models configured")
+	}
+
+	return r, nil
+}
+
+// execute calls fn for each backend in priority order until one succeeds or fails permanently
+func execute[T any](ctx context.Context, r *router, fn func(index int) (T, bool, error)) (T, error) {
+	var result T
+	var err error
+
+	for _, index := range r.candidates() {
+		var final bool
+
+		result, final, err = fn(index)
+
+		if err == nil {
+			r.markHealthy(index)
+			return result, nil
+		}
+
+		if final || ctx.Err() != nil || !isRetryable(err) {
+			return result, err
+		}
+
+		r.markUnhealthy(index)
+	}
+
+	return result, err
+}
+
+// candidates returns all backends in priority order, healthy ones first
+func (r *router) candidates() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+
+	var healthy []int
+	var unhealthy []int
+
+	for i := 0; i < r.size; i++ {
+		if until, ok := r.unhealthy[i]; ok {
+			if now.Before(until) {
+				unhealthy = append(unhealthy, i)
+				continue
+			}
+
+			delete(r.unhealthy, i)
+		}
+
+		healthy = append(healthy, i)
+	}
+
+	return append(healthy, unhealthy...)
+}
+
+func (r *router) markHealthy(index int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.unhealthy, index)
+}
+
+func (r *router) markUnhealthy(index int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.unhealthy[index] = time.Now().Add(r.cooldown)
+}
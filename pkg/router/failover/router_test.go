@@ -0,0 +1,162 @@
+package failover
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type embedder struct {
+	err   error
+	calls int
+}
+
+func (e *embedder) Embed(ctx context.Context, texts []string) (*provider.Embedding, error) {
+	e.calls++
+
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	return &provider.Embedding{}, nil
+}
+
+var errUnavailable = &provider.StatusError{StatusCode: http.StatusServiceUnavailable, Message: "unavailable"}
+
+func TestFailover(t *testing.T) {
+	primary := &embedder{err: errUnavailable}
+	secondary := &embedder{}
+
+	e, err := NewEmbedder([]provider.Embedder{primary, secondary})
+	require.NoError(t, err)
+
+	_, err = e.Embed(context.Background(), nil)
+	require.NoError(t, err)
+
+	require.Equal(t, 1, primary.calls)
+	require.Equal(t, 1, secondary.calls)
+
+	// the failed backend is cooling down and tried last
+	_, err = e.Embed(context.Background(), nil)
+	require.NoError(t, err)
+
+	require.Equal(t, 1, primary.calls)
+	require.Equal(t, 2, secondary.calls)
+}
+
+func TestCooldown(t *testing.T) {
+	primary := &embedder{err: errUnavailable}
+	secondary := &embedder{}
+
+	e, err := NewEmbedder([]provider.Embedder{primary, secondary}, WithCooldown(10*time.Millisecond))
+	require.NoError(t, err)
+
+	_, err = e.Embed(context.Background(), nil)
+	require.NoError(t, err)
+
+	time.Sleep(20 * time.Millisecond)
+
+	primary.err = nil
+
+	_, err = e.Embed(context.Background(), nil)
+	require.NoError(t, err)
+
+	require.Equal(t, 2, primary.calls)
+	require.Equal(t, 1, secondary.calls)
+}
+
+func TestUnhealthyFallback(t *testing.T) {
+	primary := &embedder{err: errUnavailable}
+	secondary := &embedder{err: errUnavailable}
+
+	e, err := NewEmbedder([]provider.Embedder{primary, secondary})
+	require.NoError(t, err)
+
+	_, err = e.Embed(context.Background(), nil)
+	require.ErrorIs(t, err, errUnavailable)
+
+	// backends cooling down are still tried when no healthy one is left
+	primary.err = nil
+
+	_, err = e.Embed(context.Background(), nil)
+	require.NoError(t, err)
+
+	require.Equal(t, 2, primary.calls)
+}
+
+func TestNonRetryable(t *testing.T) {
+	invalid := &provider.StatusError{StatusCode: http.StatusBadRequest, Message: "invalid request"}
+
+	primary := &embedder{err: invalid}
+	secondary := &embedder{}
+
+	e, err := NewEmbedder([]provider.Embedder{primary, secondary})
+	require.NoError(t, err)
+
+	_, err = e.Embed(context.Background(), nil)
+	require.ErrorIs(t, err, invalid)
+
+	require.Equal(t, 0, secondary.calls)
+}
+
+type completer struct {
+	err   error
+	calls int
+}
+
+func (c *completer) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	c.calls++
+
+	if options.Stream != nil {
+		options.Stream(ctx, provider.Completion{Message: provider.Message{Content: "partial"}})
+	}
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	return &provider.Completion{}, nil
+}
+
+func TestStreamed(t *testing.T) {
+	primary := &completer{err: errUnavailable}
+	secondary := &completer{}
+
+	c, err := NewCompleter([]provider.Completer{primary, secondary})
+	require.NoError(t, err)
+
+	_, err = c.Complete(context.Background(), nil, &provider.CompleteOptions{
+		Stream: func(ctx context.Context, completion provider.Completion) error {
+			return nil
+		},
+	})
+
+	require.ErrorIs(t, err, errUnavailable)
+	require.Equal(t, 0, secondary.calls)
+}
+
+func TestRetryable(t *testing.T) {
+	for _, test := range []struct {
+		err       error
+		retryable bool
+	}{
+		{errUnavailable, true},
+		{&provider.StatusError{StatusCode: http.StatusTooManyRequests}, true},
+		{&provider.StatusError{StatusCode: http.StatusUnauthorized}, false},
+		{context.DeadlineExceeded, true},
+		{context.Canceled, false},
+		{status.Error(codes.Unavailable, "unavailable"), true},
+		{status.Error(codes.InvalidArgument, "invalid"), false},
+		{errors.New("the model answered: internal server error"), false},
+	} {
+		require.Equal(t, test.retryable, isRetryable(test.err), test.err.Error())
+	}
+}
@@ -0,0 +1,32 @@
+package failover
+
+import (
+	"context"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+type Synthesizer struct {
+	*router
+	synthesizers []provider.Synthesizer
+}
+
+func NewSynthesizer(synthesizers []provider.Synthesizer, options ...Option) (provider.Synthesizer, error) {
+	r, err := newRouter(len(synthesizers), options...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Synthesizer{
+		router:       r,
+		synthesizers: synthesizers,
+	}, nil
+}
+
+func (s *Synthesizer) Synthesize(ctx context.Context, input string, options *provider.SynthesizeOptions) (*provider.Synthesis, error) {
+	return execute(ctx, s.router, func(index int) (*provider.Synthesis, bool, error) {
+		synthesis, err := s.synthesizers[index].Synthesize(ctx, input, options)
+		return synthesis, false, err
+	})
+}
@@ -0,0 +1,44 @@
+package failover
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+type Transcriber struct {
+	*router
+	transcribers []provider.Transcriber
+}
+
+func NewTranscriber(transcribers []provider.Transcriber, options ...Option) (provider.Transcriber, error) {
+	r, err := newRouter(len(transcribers), options...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transcriber{
+		router:       r,
+		transcribers: transcribers,
+	}, nil
+}
+
+func (t *Transcriber) Transcribe(ctx context.Context, input provider.File, options *provider.TranscribeOptions) (*provider.Transcription, error) {
+	// the audio is buffered so every attempt can read it from the start
+	data, err := io.ReadAll(input.Content)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return execute(ctx, t.router, func(index int) (*provider.Transcription, bool, error) {
+		file := input
+		file.Content = bytes.NewReader(data)
+
+		transcription, err := t.transcribers[index].Transcribe(ctx, file, options)
+		return transcription, false, err
+	})
+}
@@ -0,0 +1,87 @@
+package failover
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/cohere-ai/cohere-go/v2/core"
+	"github.com/openai/openai-go"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func isRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var neterr net.Error
+
+	if errors.As(err, &neterr) {
+		return true
+	}
+
+	var openaierr *openai.Error
+
+	if errors.As(err, &openaierr) {
+		return isRetryableStatus(openaierr.StatusCode)
+	}
+
+	var anthropicerr *anthropic.Error
+
+	if errors.As(err, &anthropicerr) {
+		return isRetryableStatus(anthropicerr.StatusCode)
+	}
+
+	var googleerr *googleapi.Error
+
+	if errors.As(err, &googleerr) {
+		return isRetryableStatus(googleerr.Code)
+	}
+
+	var statuserr *provider.StatusError
+
+	if errors.As(err, &statuserr) {
+		return isRetryableStatus(statuserr.StatusCode)
+	}
+
+	var cohereerr *core.APIError
+
+	if errors.As(err, &cohereerr) {
+		return isRetryableStatus(cohereerr.StatusCode)
+	}
+
+	// aws sdk response errors
+	var httperr interface{ HTTPStatusCode() int }
+
+	if errors.As(err, &httperr) {
+		return isRetryableStatus(httperr.HTTPStatusCode())
+	}
+
+	if s, ok := status.FromError(err); ok {
+		switch s.Code() {
+		case codes.Unavailable, codes.ResourceExhausted, codes.DeadlineExceeded, codes.Aborted:
+			return true
+		}
+	}
+
+	return false
+}
+
+func isRetryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
@@ -0,0 +1,68 @@
+package latency
+
+import (
+	"context"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+type Completer struct {
+	*router
+	completers []provider.Completer
+}
+
+func NewCompleter(completers []provider.Completer) (provider.Completer, error) {
+	r, err := newRouter(len(completers))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Completer{
+		router:     r,
+		completers: completers,
+	}, nil
+}
+
+func (c *Completer) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	if options == nil {
+		options = new(provider.CompleteOptions)
+	}
+
+	index := c.pick()
+	start := time.Now()
+
+	var first time.Duration
+
+	attempt := *options
+
+	if options.Stream != nil {
+		attempt.Stream = func(ctx context.Context, completion provider.Completion) error {
+			if first == 0 {
+				first = time.Since(start)
+			}
+
+			return options.Stream(ctx, completion)
+		}
+	}
+
+	completion, err := c.completers[index].Complete(ctx, messages, &attempt)
+
+	if err != nil {
+		if ctx.Err() == nil {
+			c.observe(index, penalty)
+		}
+
+		return nil, err
+	}
+
+	// without streaming the time to first token is the duration of the whole request
+	if first == 0 {
+		first = time.Since(start)
+	}
+
+	c.observe(index, first)
+
+	return completion, nil
+}
@@ -0,0 +1,31 @@
+package latency
+
+import (
+	"context"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+type Embedder struct {
+	*router
+	embedders []provider.Embedder
+}
+
+func NewEmbedder(embedders []provider.Embedder) (provider.Embedder, error) {
+	r, err := newRouter(len(embedders))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Embedder{
+		router:    r,
+		embedders: embedders,
+	}, nil
+}
+
+func (e *Embedder) Embed(ctx context.Context, texts []string) (*provider.Embedding, error) {
+	return measure(ctx, e.router, func(index int) (*provider.Embedding, error) {
+		return e.embedders[index].Embed(ctx, texts)
+	})
+}
@@ -0,0 +1,31 @@
+package latency
+
+import (
+	"context"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+type Renderer struct {
+	*router
+	renderers []provider.Renderer
+}
+
+func NewRenderer(renderers []provider.Renderer) (provider.Renderer, error) {
+	r, err := newRouter(len(renderers))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Renderer{
+		router:    r,
+		renderers: renderers,
+	}, nil
+}
+
+func (r *Renderer) Render(ctx context.Context, input string, options *provider.RenderOptions) (*provider.Image, error) {
+	return measure(ctx, r.router, func(index int) (*provider.Image, error) {
+		return r.renderers[index].Render(ctx, input, options)
+	})
+}
@@ -0,0 +1,31 @@
+package latency
+
+import (
+	"context"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+type Reranker struct {
+	*router
+	rerankers []provider.Reranker
+}
+
+func NewReranker(rerankers []provider.Reranker) (provider.Reranker, error) {
+	r, err := newRouter(len(rerankers))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reranker{
+		router:    r,
+		rerankers: rerankers,
+	}, nil
+}
+
+func (r *Reranker) Rerank(ctx context.Context, query string, texts []string, options *provider.RerankOptions) ([]provider.Ranking, error) {
+	return measure(ctx, r.router, func(index int) ([]provider.Ranking, error) {
+		return r.rerankers[index].Rerank(ctx, query, texts, options)
+	})
+}
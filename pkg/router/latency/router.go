@@ -0,0 +1,117 @@
+package latency
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+const (
+	// share of the requests sent to a random backend to keep the measurements of slower ones current
+	exploration = 0.1
+
+	// weight of the newest sample in the moving average
+	smoothing = 0.3
+
+	// latency assumed for a failed request
+	penalty = 10 * time.Second
+)
+
+type router struct {
+	mu sync.Mutex
+
+	latencies []time.Duration
+
+	// next rotates the requests over backends without measurements
+	next int
+
+	exploration float64
+}
+
+func newRouter(size int) (*router, error) {
+	if size == 0 {
+		return nil, errors.New("no models configured")
+	}
+
+	return &router{
+		latencies: make([]time.Duration, size),
+
+		exploration: exploration,
+	}, nil
+}
+
+// pick returns the backend with the lowest recent latency.
+// Backends without measurements are tried in turn first, so a cold start spreads over all of them.
+func (r *router) pick() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unmeasured []int
+
+	for i, l := range r.latencies {
+		if l == 0 {
+			unmeasured = append(unmeasured, i)
+		}
+	}
+
+	if len(unmeasured) > 0 {
+		index := unmeasured[r.next%len(unmeasured)]
+		r.next++
+
+		return index
+	}
+
+	if rand.Float64() < r.exploration {
+		return rand.Intn(len(r.latencies))
+	}
+
+	result := 0
+
+	for i, l := range r.latencies {
+		if l < r.latencies[result] {
+			result = i
+		}
+	}
+
+	return result
+}
+
+func (r *router) observe(index int, latency time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if latency <= 0 {
+		latency = time.Nanosecond
+	}
+
+	current := r.latencies[index]
+
+	if current == 0 {
+		r.latencies[index] = latency
+		return
+	}
+
+	r.latencies[index] = time.Duration(smoothing*float64(latency) + (1-smoothing)*float64(current))
+}
+
+// measure runs fn on the picked backend and records its latency
+func measure[T any](ctx context.Context, r *router, fn func(index int) (T, error)) (T, error) {
+	index := r.pick()
+	start := time.Now()
+
+	result, err := fn(index)
+
+	if err != nil {
+		if ctx.Err() == nil {
+			r.observe(index, penalty)
+		}
+
+		return result, err
+	}
+
+	r.observe(index, time.Since(start))
+
+	return result, nil
+}
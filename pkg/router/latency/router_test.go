@@ -0,0 +1,123 @@
+package latency
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+
+	"github.com/stretchr/testify/require"
+)
+
+type embedder struct {
+	delay time.Duration
+
+	mu    sync.Mutex
+	calls int
+}
+
+func (e *embedder) Embed(ctx context.Context, texts []string) (*provider.Embedding, error) {
+	e.mu.Lock()
+	e.calls++
+	e.mu.Unlock()
+
+	time.Sleep(e.delay)
+
+	return &provider.Embedding{}, nil
+}
+
+func TestColdStart(t *testing.T) {
+	embedders := []*embedder{
+		{delay: 20 * time.Millisecond},
+		{delay: 20 * time.Millisecond},
+		{delay: 20 * time.Millisecond},
+	}
+
+	e, err := NewEmbedder([]provider.Embedder{embedders[0], embedders[1], embedders[2]})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+
+	for range 6 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			e.Embed(context.Background(), nil)
+		}()
+	}
+
+	wg.Wait()
+
+	for _, e := range embedders {
+		require.Equal(t, 2, e.calls)
+	}
+}
+
+func TestLowestLatency(t *testing.T) {
+	slow := &embedder{delay: 20 * time.Millisecond}
+	fast := &embedder{}
+
+	p, err := NewEmbedder([]provider.Embedder{slow, fast})
+	require.NoError(t, err)
+
+	e := p.(*Embedder)
+	e.exploration = 0
+
+	for range 10 {
+		_, err := e.Embed(context.Background(), nil)
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, 1, slow.calls)
+	require.Equal(t, 9, fast.calls)
+}
+
+type completer struct {
+	first time.Duration
+	total time.Duration
+
+	calls int
+}
+
+func (c *completer) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	c.calls++
+
+	time.Sleep(c.first)
+
+	if options.Stream != nil {
+		options.Stream(ctx, provider.Completion{Message: provider.Message{Content: "hello"}})
+	}
+
+	time.Sleep(c.total - c.first)
+
+	return &provider.Completion{}, nil
+}
+
+func TestTimeToFirstToken(t *testing.T) {
+	// the long answer starts first and wins although it takes longer overall
+	long := &completer{first: 5 * time.Millisecond, total: 60 * time.Millisecond}
+	short := &completer{first: 30 * time.Millisecond, total: 30 * time.Millisecond}
+
+	p, err := NewCompleter([]provider.Completer{long, short})
+	require.NoError(t, err)
+
+	c := p.(*Completer)
+	c.exploration = 0
+
+	options := &provider.CompleteOptions{
+		Stream: func(ctx context.Context, completion provider.Completion) error {
+			return nil
+		},
+	}
+
+	for range 4 {
+		_, err := c.Complete(context.Background(), nil, options)
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, 3, long.calls)
+	require.Equal(t, 1, short.calls)
+}
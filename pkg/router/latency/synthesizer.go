@@ -0,0 +1,31 @@
+package latency
+
+import (
+	"context"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+type Synthesizer struct {
+	*router
+	synthesizers []provider.Synthesizer
+}
+
+func NewSynthesizer(synthesizers []provider.Synthesizer) (provider.Synthesizer, error) {
+	r, err := newRouter(len(synthesizers))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Synthesizer{
+		router:       r,
+		synthesizers: synthesizers,
+	}, nil
+}
+
+func (s *Synthesizer) Synthesize(ctx context.Context, input string, options *provider.SynthesizeOptions) (*provider.Synthesis, error) {
+	return measure(ctx, s.router, func(index int) (*provider.Synthesis, error) {
+		return s.synthesizers[index].Synthesize(ctx, input, options)
+	})
+}
@@ -0,0 +1,31 @@
+package latency
+
+import (
+	"context"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+type Transcriber struct {
+	*router
+	transcribers []provider.Transcriber
+}
+
+func NewTranscriber(transcribers []provider.Transcriber) (provider.Transcriber, error) {
+	r, err := newRouter(len(transcribers))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transcriber{
+		router:       r,
+		transcribers: transcribers,
+	}, nil
+}
+
+func (t *Transcriber) Transcribe(ctx context.Context, input provider.File, options *provider.TranscribeOptions) (*provider.Transcription, error) {
+	return measure(ctx, t.router, func(index int) (*provider.Transcription, error) {
+		return t.transcribers[index].Transcribe(ctx, input, options)
+	})
+}
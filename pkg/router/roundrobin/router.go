@@ -25,3 +25,98 @@ func (c *Completer) Complete(ctx context.Context, messages []provider.Message, o
 
 	return provider.Complete(ctx, messages, options)
 }
+
+type Embedder struct {
+	embedders []provider.Embedder
+}
+
+func NewEmbedder(embedder ...provider.Embedder) (provider.Embedder, error) {
+	e := &Embedder{
+		embedders: embedder,
+	}
+
+	return e, nil
+}
+
+func (e *Embedder) Embed(ctx context.Context, texts []string) (*provider.Embedding, error) {
+	index := rand.Intn(len(e.embedders))
+	provider := e.embedders[index]
+
+	return provider.Embed(ctx, texts)
+}
+
+type Reranker struct {
+	rerankers []provider.Reranker
+}
+
+func NewReranker(reranker ...provider.Reranker) (provider.Reranker, error) {
+	r := &Reranker{
+		rerankers: reranker,
+	}
+
+	return r, nil
+}
+
+func (r *Reranker) Rerank(ctx context.Context, query string, texts []string, options *provider.RerankOptions) ([]provider.Ranking, error) {
+	index := rand.Intn(len(r.rerankers))
+	provider := r.rerankers[index]
+
+	return provider.Rerank(ctx, query, texts, options)
+}
+
+type Renderer struct {
+	renderers []provider.Renderer
+}
+
+func NewRenderer(renderer ...provider.Renderer) (provider.Renderer, error) {
+	r := &Renderer{
+		renderers: renderer,
+	}
+
+	return r, nil
+}
+
+func (r *Renderer) Render(ctx context.Context, input string, options *provider.RenderOptions) (*provider.Image, error) {
+	index := rand.Intn(len(r.renderers))
+	provider := r.renderers[index]
+
+	return provider.Render(ctx, input, options)
+}
+
+type Synthesizer struct {
+	synthesizers []provider.Synthesizer
+}
+
+func NewSynthesizer(synthesizer ...provider.Synthesizer) (provider.Synthesizer, error) {
+	s := &Synthesizer{
+		synthesizers: synthesizer,
+	}
+
+	return s, nil
+}
+
+func (s *Synthesizer) Synthesize(ctx context.Context, input string, options *provider.SynthesizeOptions) (*provider.Synthesis, error) {
+	index := rand.Intn(len(s.synthesizers))
+	provider := s.synthesizers[index]
+
+	return provider.Synthesize(ctx, input, options)
+}
+
+type Transcriber struct {
+	transcribers []provider.Transcriber
+}
+
+func NewTranscriber(transcriber ...provider.Transcriber) (provider.Transcriber, error) {
+	t := &Transcriber{
+		transcribers: transcriber,
+	}
+
+	return t, nil
+}
+
+func (t *Transcriber) Transcribe(ctx context.Context, input provider.File, options *provider.TranscribeOptions) (*provider.Transcription, error) {
+	index := rand.Intn(len(t.transcribers))
+	provider := t.transcribers[index]
+
+	return provider.Transcribe(ctx, input, options)
+}
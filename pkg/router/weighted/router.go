@@ -0,0 +1,201 @@
+package weighted
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+type router struct {
+	weights []int
+	total   int
+
+	intn func(n int) int
+}
+
+func newRouter(size int, weights []int) (*router, error) {
+	if size == 0 {
+		return nil, errors.New("no models configured")
+	}
+
+	r := &router{
+		weights: make([]int, size),
+
+		intn: rand.Intn,
+	}
+
+	for i := range r.weights {
+		weight := 1
+
+		if i < len(weights) {
+			weight = weights[i]
+		}
+
+		if weight < 0 {
+			return nil, errors.New("weight must not be negative")
+		}
+
+		r.weights[i] = weight
+		r.total += weight
+	}
+
+	if r.total == 0 {
+		return nil, errors.New("at least one weight must be positive")
+	}
+
+	return r, nil
+}
+
+func (r *router) pick() int {
+	n := r.intn(r.total)
+
+	for i, w := range r.weights {
+		if n < w {
+			return i
+		}
+
+		n -= w
+	}
+
+	return len(r.weights) - 1
+}
+
+type Completer struct {
+	*router
+	completers []provider.Completer
+}
+
+func NewCompleter(completers []provider.Completer, weights []int) (provider.Completer, error) {
+	r, err := newRouter(len(completers), weights)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Completer{
+		router:     r,
+		completers: completers,
+	}, nil
+}
+
+func (c *Completer) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	provider := c.completers[c.pick()]
+	return provider.Complete(ctx, messages, options)
+}
+
+type Embedder struct {
+	*router
+	embedders []provider.Embedder
+}
+
+func NewEmbedder(embedders []provider.Embedder, weights []int) (provider.Embedder, error) {
+	r, err := newRouter(len(embedders), weights)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Embedder{
+		router:    r,
+		embedders: embedders,
+	}, nil
+}
+
+func (e *Embedder) Embed(ctx context.Context, texts []string) (*provider.Embedding, error) {
+	provider := e.embedders[e.pick()]
+	return provider.Embed(ctx, texts)
+}
+
+type Reranker struct {
+	*router
+	rerankers []provider.Reranker
+}
+
+func NewReranker(rerankers []provider.Reranker, weights []int) (provider.Reranker, error) {
+	r, err := newRouter(len(rerankers), weights)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reranker{
+		router:    r,
+		rerankers: rerankers,
+	}, nil
+}
+
+func (r *Reranker) Rerank(ctx context.Context, query string, texts []string, options *provider.RerankOptions) ([]provider.Ranking, error) {
+	provider := r.rerankers[r.pick()]
+	return provider.Rerank(ctx, query, texts, options)
+}
+
+type Renderer struct {
+	*router
+	renderers []provider.Renderer
+}
+
+func NewRenderer(renderers []provider.Renderer, weights []int) (provider.Renderer, error) {
+	r, err := newRouter(len(renderers), weights)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Renderer{
+		router:    r,
+		renderers: renderers,
+	}, nil
+}
+
+func (r *Renderer) Render(ctx context.Context, input string, options *provider.RenderOptions) (*provider.Image, error) {
+	provider := r.renderers[r.pick()]
+	return provider.Render(ctx, input, options)
+}
+
+type Synthesizer struct {
+	*router
+	synthesizers []provider.Synthesizer
+}
+
+func NewSynthesizer(synthesizers []provider.Synthesizer, weights []int) (provider.Synthesizer, error) {
+	r, err := newRouter(len(synthesizers), weights)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Synthesizer{
+		router:       r,
+		synthesizers: synthesizers,
+	}, nil
+}
+
+func (s *Synthesizer) Synthesize(ctx context.Context, input string, options *provider.SynthesizeOptions) (*provider.Synthesis, error) {
+	provider := s.synthesizers[s.pick()]
+	return provider.Synthesize(ctx, input, options)
+}
+
+type Transcriber struct {
+	*router
+	transcribers []provider.Transcriber
+}
+
+func NewTranscriber(transcribers []provider.Transcriber, weights []int) (provider.Transcriber, error) {
+	r, err := newRouter(len(transcribers), weights)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transcriber{
+		router:       r,
+		transcribers: transcribers,
+	}, nil
+}
+
+func (t *Transcriber) Transcribe(ctx context.Context, input provider.File, options *provider.TranscribeOptions) (*provider.Transcription, error) {
+	provider := t.transcribers[t.pick()]
+	return provider.Transcribe(ctx, input, options)
+}
@@ -0,0 +1,65 @@
+package weighted
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeights(t *testing.T) {
+	for _, test := range []struct {
+		size    int
+		weights []int
+
+		expected []int
+		err      bool
+	}{
+		{size: 2, weights: nil, expected: []int{1, 1}},
+		{size: 3, weights: []int{3, 0}, expected: []int{3, 0, 1}},
+		{size: 2, weights: []int{0, 0}, err: true},
+		{size: 2, weights: []int{1, -1}, err: true},
+		{size: 0, err: true},
+	} {
+		r, err := newRouter(test.size, test.weights)
+
+		if test.err {
+			require.Error(t, err)
+			continue
+		}
+
+		require.NoError(t, err)
+		require.Equal(t, test.expected, r.weights)
+	}
+}
+
+func TestPick(t *testing.T) {
+	r, err := newRouter(3, []int{1, 0, 3})
+	require.NoError(t, err)
+
+	var picks []int
+
+	for n := range r.total {
+		r.intn = func(int) int { return n }
+		picks = append(picks, r.pick())
+	}
+
+	require.Equal(t, []int{0, 2, 2, 2}, picks)
+}
+
+func TestDistribution(t *testing.T) {
+	r, err := newRouter(3, []int{1, 2, 7})
+	require.NoError(t, err)
+
+	r.intn = rand.New(rand.NewSource(1)).Intn
+
+	counts := make([]int, 3)
+
+	for range 10000 {
+		counts[r.pick()]++
+	}
+
+	require.InDelta(t, 1000, counts[0], 150)
+	require.InDelta(t, 2000, counts[1], 200)
+	require.InDelta(t, 7000, counts[2], 300)
+}
@@ -0,0 +1,273 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/adrianliechti/wingman/pkg/tool"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var _ tool.Provider = (*Client)(nil)
+
+// Client connects to an mcp server, either over streamable http or by spawning a command speaking stdio
+type Client struct {
+	url   string
+	token string
+
+	command string
+	args    []string
+	env     map[string]string
+
+	// dial returns the transport of a new connection
+	dial func() mcp.Transport
+
+	mu      sync.Mutex
+	session *mcp.ClientSession
+
+	tools []tool.Tool
+}
+
+func New(url string, options ...Option) (*Client, error) {
+	c := &Client{
+		url: url,
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	if c.url == "" && c.command == "" {
+		return nil, errors.New("missing url or command")
+	}
+
+	c.dial = c.transport
+
+	return c, nil
+}
+
+func (c *Client) Tools(ctx context.Context) ([]tool.Tool, error) {
+	c.mu.Lock()
+	tools := c.tools
+	c.mu.Unlock()
+
+	if tools != nil {
+		return tools, nil
+	}
+
+	var result []tool.Tool
+
+	err := c.call(ctx, true, func(session *mcp.ClientSession) error {
+		result = []tool.Tool{}
+
+		for t, err := range session.Tools(ctx, nil) {
+			if err != nil {
+				return err
+			}
+
+			parameters, _ := t.InputSchema.(map[string]any)
+
+			result = append(result, tool.Tool{
+				Name:        t.Name,
+				Description: t.Description,
+
+				Parameters: parameters,
+			})
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.tools = result
+	c.mu.Unlock()
+
+	return result, nil
+}
+
+func (c *Client) Execute(ctx context.Context, name string, parameters map[string]any) (any, error) {
+	var result *mcp.CallToolResult
+
+	if parameters == nil {
+		parameters = map[string]any{}
+	}
+
+	err := c.call(ctx, false, func(session *mcp.ClientSession) error {
+		r, err := session.CallTool(ctx, &mcp.CallToolParams{
+			Name:      name,
+			Arguments: parameters,
+		})
+
+		result = r
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	var texts []string
+
+	for _, content := range result.Content {
+		if t, ok := content.(*mcp.TextContent); ok {
+			texts = append(texts, t.Text)
+		}
+	}
+
+	text := strings.Join(texts, "\n")
+
+	if result.IsError {
+		if text == "" {
+			text = "tool execution failed"
+		}
+
+		return nil, errors.New(text)
+	}
+
+	if result.StructuredContent != nil {
+		return result.StructuredContent, nil
+	}
+
+	return text, nil
+}
+
+// call runs fn on the current session and retries once on a new session if the request failed before reaching the server,
+// as on a closing connection or a session the server no longer knows. Connections lost during a request are retried only if
+// the request is idempotent, since a tool might have run already.
+func (c *Client) call(ctx context.Context, idempotent bool, fn func(session *mcp.ClientSession) error) error {
+	session, err := c.connect(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	err = fn(session)
+
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+
+	unsent := errors.Is(err, mcp.ErrConnectionClosed) || errors.Is(err, mcp.ErrSessionMissing)
+
+	var rpcErr *jsonrpc.Error
+
+	// the server answered the request, so the connection is fine
+	if !unsent && errors.As(err, &rpcErr) {
+		return err
+	}
+
+	c.reset(session)
+
+	if !unsent && !idempotent {
+		return err
+	}
+
+	if session, err = c.connect(ctx); err != nil {
+		return err
+	}
+
+	return fn(session)
+}
+
+func (c *Client) connect(ctx context.Context) (*mcp.ClientSession, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session != nil {
+		return c.session, nil
+	}
+
+	client := mcp.NewClient(&mcp.Implementation{
+		Name:    "wingman",
+		Version: "1.0.0",
+	}, &mcp.ClientOptions{
+		ToolListChangedHandler: func(ctx context.Context, req *mcp.ToolListChangedRequest) {
+			c.mu.Lock()
+			c.tools = nil
+			c.mu.Unlock()
+		},
+	})
+
+	session, err := client.Connect(ctx, c.dial(), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	c.session = session
+	c.tools = nil
+
+	go func() {
+		session.Wait()
+		c.reset(session)
+	}()
+
+	return session, nil
+}
+
+// reset drops a closed session, so the next call reconnects
+func (c *Client) reset(session *mcp.ClientSession) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session != session {
+		return
+	}
+
+	session.Close()
+
+	c.session = nil
+	c.tools = nil
+}
+
+func (c *Client) transport() mcp.Transport {
+	if c.command != "" {
+		cmd := exec.Command(c.command, c.args...)
+		cmd.Env = os.Environ()
+
+		for k, v := range c.env {
+			cmd.Env = append(cmd.Env, k+"="+v)
+		}
+
+		return &mcp.CommandTransport{
+			Command: cmd,
+		}
+	}
+
+	client := http.DefaultClient
+
+	if c.token != "" {
+		client = &http.Client{
+			Transport: &tokenTransport{
+				token: c.token,
+			},
+		}
+	}
+
+	return &mcp.StreamableClientTransport{
+		Endpoint:   c.url,
+		HTTPClient: client,
+	}
+}
+
+type tokenTransport struct {
+	token string
+}
+
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+
+	return http.DefaultTransport.RoundTrip(req)
+}
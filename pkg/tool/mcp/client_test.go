@@ -0,0 +1,249 @@
+package mcp
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/require"
+)
+
+// testServer runs an in-memory mcp server, connecting a new session for every dial of the client
+type testServer struct {
+	*mcp.Server
+
+	mu       sync.Mutex
+	sessions []*mcp.ServerSession
+	conns    []mcp.Connection
+
+	calls atomic.Int32
+}
+
+func newTestClient(t *testing.T) (*Client, *testServer) {
+	s := &testServer{
+		Server: mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil),
+	}
+
+	s.AddTool(&mcp.Tool{
+		Name:        "echo",
+		Description: "returns its input",
+
+		InputSchema: map[string]any{"type": "object"},
+	}, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.calls.Add(1)
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: string(req.Params.Arguments)},
+			},
+		}, nil
+	})
+
+	s.AddTool(&mcp.Tool{
+		Name: "fail",
+
+		InputSchema: map[string]any{"type": "object"},
+	}, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result := &mcp.CallToolResult{}
+		result.SetError(context.DeadlineExceeded)
+
+		return result, nil
+	})
+
+	c, err := New("memory://")
+	require.NoError(t, err)
+
+	c.dial = func() mcp.Transport {
+		client, server := mcp.NewInMemoryTransports()
+
+		session, err := s.Connect(context.Background(), server, nil)
+		require.NoError(t, err)
+
+		s.mu.Lock()
+		s.sessions = append(s.sessions, session)
+		s.mu.Unlock()
+
+		return &testTransport{client, s}
+	}
+
+	t.Cleanup(func() {
+		c.mu.Lock()
+		session := c.session
+		c.mu.Unlock()
+
+		if session != nil {
+			session.Close()
+		}
+	})
+
+	return c, s
+}
+
+// disconnect closes the server side of the current session
+func (s *testServer) disconnect() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sessions[len(s.sessions)-1].Close()
+}
+
+// drop breaks the current connection of the client, like a network failure
+func (s *testServer) drop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.conns[len(s.conns)-1].Close()
+}
+
+func (s *testServer) connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.sessions)
+}
+
+// testTransport keeps the client connections, so they can be broken
+type testTransport struct {
+	mcp.Transport
+
+	server *testServer
+}
+
+func (t *testTransport) Connect(ctx context.Context) (mcp.Connection, error) {
+	conn, err := t.Transport.Connect(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	t.server.mu.Lock()
+	t.server.conns = append(t.server.conns, conn)
+	t.server.mu.Unlock()
+
+	return conn, nil
+}
+
+func TestTools(t *testing.T) {
+	c, s := newTestClient(t)
+
+	tools, err := c.Tools(context.Background())
+	require.NoError(t, err)
+	require.Len(t, tools, 2)
+
+	require.Equal(t, "echo", tools[0].Name)
+	require.Equal(t, "returns its input", tools[0].Description)
+	require.Equal(t, map[string]any{"type": "object"}, tools[0].Parameters)
+
+	require.Equal(t, "fail", tools[1].Name)
+
+	// the list is cached
+	_, err = c.Tools(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, s.connections())
+}
+
+func TestExecute(t *testing.T) {
+	c, _ := newTestClient(t)
+
+	result, err := c.Execute(context.Background(), "echo", map[string]any{"text": "hello"})
+	require.NoError(t, err)
+	require.Equal(t, `{"text":"hello"}`, result)
+
+	_, err = c.Execute(context.Background(), "fail", nil)
+	require.ErrorContains(t, err, "deadline exceeded")
+
+	_, err = c.Execute(context.Background(), "missing", nil)
+	require.Error(t, err)
+}
+
+func TestReconnect(t *testing.T) {
+	c, s := newTestClient(t)
+
+	_, err := c.Tools(context.Background())
+	require.NoError(t, err)
+
+	s.disconnect()
+
+	// the closed session is dropped in the background
+	require.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		return c.session == nil
+	}, time.Second, 10*time.Millisecond)
+
+	result, err := c.Execute(context.Background(), "echo", map[string]any{})
+	require.NoError(t, err)
+	require.Equal(t, `{}`, result)
+
+	tools, err := c.Tools(context.Background())
+	require.NoError(t, err)
+	require.Len(t, tools, 2)
+
+	require.Equal(t, 2, s.connections())
+
+	// a call on a closed session never reached the server, so even a tool is called again
+	c.mu.Lock()
+	session := c.session
+	c.mu.Unlock()
+
+	session.Close()
+
+	result, err = c.Execute(context.Background(), "echo", map[string]any{})
+	require.NoError(t, err)
+	require.Equal(t, `{}`, result)
+
+	require.Equal(t, 3, s.connections())
+}
+
+func TestExecuteNotRepeated(t *testing.T) {
+	c, s := newTestClient(t)
+
+	// the connection is lost while the tool runs, so the client cannot tell whether it ran
+	s.AddTool(&mcp.Tool{
+		Name: "crash",
+
+		InputSchema: map[string]any{"type": "object"},
+	}, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.calls.Add(1)
+
+		s.drop()
+
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	_, err := c.Execute(context.Background(), "crash", nil)
+	require.Error(t, err)
+
+	// the tool may have had side effects, so it must not run again on a new connection
+	require.EqualValues(t, 1, s.calls.Load())
+	require.Equal(t, 1, s.connections())
+
+	result, err := c.Execute(context.Background(), "echo", nil)
+	require.NoError(t, err)
+	require.Equal(t, `{}`, result)
+
+	require.EqualValues(t, 2, s.calls.Load())
+	require.Equal(t, 2, s.connections())
+}
+
+func TestToolsChanged(t *testing.T) {
+	c, s := newTestClient(t)
+
+	tools, err := c.Tools(context.Background())
+	require.NoError(t, err)
+	require.Len(t, tools, 2)
+
+	s.RemoveTools("fail")
+
+	require.Eventually(t, func() bool {
+		tools, err := c.Tools(context.Background())
+		return err == nil && len(tools) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	require.Equal(t, 1, s.connections())
+}
@@ -0,0 +1,22 @@
+package mcp
+
+type Option func(*Client)
+
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+func WithCommand(command string, args ...string) Option {
+	return func(c *Client) {
+		c.command = command
+		c.args = args
+	}
+}
+
+func WithEnv(env map[string]string) Option {
+	return func(c *Client) {
+		c.env = env
+	}
+}
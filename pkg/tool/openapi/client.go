@@ -0,0 +1,260 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/adrianliechti/wingman/pkg/tool"
+)
+
+var _ tool.Provider = (*Client)(nil)
+
+// Client generates a tool for every operation of an openapi 3 document and calls the described service
+type Client struct {
+	client *http.Client
+
+	spec string
+	url  string
+
+	token   string
+	headers map[string]string
+
+	operations []string
+
+	mu     sync.Mutex
+	loaded []operation
+}
+
+func New(spec string, options ...Option) (*Client, error) {
+	c := &Client{
+		client: http.DefaultClient,
+
+		spec: spec,
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	if c.spec == "" {
+		return nil, errors.New("missing openapi document")
+	}
+
+	return c, nil
+}
+
+func (c *Client) Tools(ctx context.Context) ([]tool.Tool, error) {
+	operations, err := c.load(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var result []tool.Tool
+
+	for _, op := range operations {
+		result = append(result, tool.Tool{
+			Name:        op.Name,
+			Description: op.Description,
+
+			Parameters: op.Schema,
+		})
+	}
+
+	return result, nil
+}
+
+func (c *Client) Execute(ctx context.Context, name string, parameters map[string]any) (any, error) {
+	operations, err := c.load(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	i := slices.IndexFunc(operations, func(op operation) bool {
+		return op.Name == name
+	})
+
+	if i < 0 {
+		return nil, tool.ErrInvalidTool
+	}
+
+	op := operations[i]
+
+	path := op.Path
+	query := url.Values{}
+	headers := http.Header{}
+
+	var missing []string
+
+	for _, p := range op.Parameters {
+		value, ok := parameters[p.Name]
+
+		if !ok || value == nil {
+			if p.In == "path" {
+				missing = append(missing, p.Name)
+			}
+
+			continue
+		}
+
+		switch p.In {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", url.PathEscape(formatValue(value)))
+
+		case "query":
+			if values, ok := value.([]any); ok {
+				for _, v := range values {
+					query.Add(p.Name, formatValue(v))
+				}
+			} else {
+				query.Set(p.Name, formatValue(value))
+			}
+
+		case "header":
+			headers.Set(p.Name, formatValue(value))
+		}
+	}
+
+	// the request would otherwise go to a path with the literal placeholder
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing path parameters: %s", strings.Join(missing, ", "))
+	}
+
+	u := c.url + path
+
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var body io.Reader
+
+	if op.Body != "" {
+		if value, ok := parameters[op.Body]; ok {
+			data, err := json.Marshal(value)
+
+			if err != nil {
+				return nil, err
+			}
+
+			body = bytes.NewReader(data)
+			headers.Set("Content-Type", "application/json")
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, op.Method, u, body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range headers {
+		req.Header[k] = v
+	}
+
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unable to execute operation: %s %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+
+	var result any
+
+	if err := json.Unmarshal(data, &result); err == nil {
+		return result, nil
+	}
+
+	return string(data), nil
+}
+
+// load reads the document once and keeps the allowed operations
+func (c *Client) load(ctx context.Context) ([]operation, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.loaded != nil {
+		return c.loaded, nil
+	}
+
+	spec, err := loadSpec(ctx, c.client, c.spec)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if c.url == "" {
+		url, err := serverURL(spec, c.spec)
+
+		if err != nil {
+			return nil, err
+		}
+
+		c.url = url
+	}
+
+	c.url = strings.TrimRight(c.url, "/")
+
+	result := []operation{}
+
+	for _, op := range parseOperations(spec) {
+		if len(c.operations) > 0 && !slices.Contains(c.operations, op.ID) {
+			continue
+		}
+
+		result = append(result, op)
+	}
+
+	uniqueNames(result)
+
+	c.loaded = result
+
+	return result, nil
+}
+
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+
+	case nil:
+		return ""
+	}
+
+	data, _ := json.Marshal(v)
+	return strings.Trim(string(data), "\"")
+}
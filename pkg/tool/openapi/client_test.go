@@ -0,0 +1,196 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const spec = `
+openapi: 3.0.3
+info:
+  title: Pets
+  version: "1.0"
+servers:
+  - url: /api
+paths:
+  /pets/{id}:
+    parameters:
+      - $ref: "#/components/parameters/id"
+    get:
+      operationId: getPet
+      summary: Get a pet
+      parameters:
+        - name: fields
+          in: query
+          schema:
+            type: string
+      responses:
+        "200":
+          description: ok
+    put:
+      operationId: updatePet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/Pet"
+      responses:
+        "200":
+          description: ok
+  /pets:
+    post:
+      summary: Create a pet
+      parameters:
+        - name: body
+          in: query
+          schema:
+            type: string
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/Pet"
+      responses:
+        "201":
+          description: created
+components:
+  parameters:
+    id:
+      name: id
+      in: path
+      description: the pet id
+      schema:
+        type: integer
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string
+`
+
+func newServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /openapi.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(spec))
+	})
+
+	mux.HandleFunc("GET /api/pets/{id}", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"id":     r.PathValue("id"),
+			"fields": r.URL.Query().Get("fields"),
+			"key":    r.Header.Get("X-Api-Key"),
+		})
+	})
+
+	mux.HandleFunc("PUT /api/pets/{id}", func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		w.Write(data)
+	})
+
+	mux.HandleFunc("POST /api/pets", func(w http.ResponseWriter, r *http.Request) {
+		var pet map[string]any
+		json.NewDecoder(r.Body).Decode(&pet)
+
+		pet["body"] = r.URL.Query().Get("body")
+
+		json.NewEncoder(w).Encode(pet)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestTools(t *testing.T) {
+	server := newServer(t)
+
+	c, err := New(server.URL + "/openapi.yaml")
+	require.NoError(t, err)
+
+	tools, err := c.Tools(context.Background())
+	require.NoError(t, err)
+	require.Len(t, tools, 3)
+
+	require.Equal(t, "post_pets", tools[0].Name)
+	require.Equal(t, "Create a pet", tools[0].Description)
+
+	// the request body gets another name than the query parameter called body
+	require.Contains(t, tools[0].Parameters["properties"], "body")
+	require.Contains(t, tools[0].Parameters["properties"], "request_body")
+
+	require.Equal(t, "getPet", tools[1].Name)
+	require.Equal(t, map[string]any{
+		"type": "object",
+
+		"properties": map[string]any{
+			"id":     map[string]any{"type": "integer", "description": "the pet id"},
+			"fields": map[string]any{"type": "string"},
+		},
+
+		"required": []string{"id"},
+	}, tools[1].Parameters)
+
+	require.Equal(t, "updatePet", tools[2].Name)
+	require.Equal(t, []string{"id", "body"}, tools[2].Parameters["required"])
+}
+
+func TestExecute(t *testing.T) {
+	server := newServer(t)
+
+	c, err := New(server.URL+"/openapi.yaml", WithHeaders(map[string]string{"X-Api-Key": "secret"}))
+	require.NoError(t, err)
+
+	result, err := c.Execute(context.Background(), "getPet", map[string]any{"id": float64(42), "fields": "name"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"id": "42", "fields": "name", "key": "secret"}, result)
+
+	result, err = c.Execute(context.Background(), "updatePet", map[string]any{"id": float64(1), "body": map[string]any{"name": "Rex"}})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"name": "Rex"}, result)
+
+	result, err = c.Execute(context.Background(), "post_pets", map[string]any{"body": "query", "request_body": map[string]any{"name": "Rex"}})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"name": "Rex", "body": "query"}, result)
+
+	// a request without its path parameters is not sent
+	_, err = c.Execute(context.Background(), "getPet", map[string]any{"fields": "name"})
+	require.EqualError(t, err, "missing path parameters: id")
+
+	_, err = c.Execute(context.Background(), "updatePet", map[string]any{"id": nil, "body": map[string]any{}})
+	require.EqualError(t, err, "missing path parameters: id")
+}
+
+func TestOperations(t *testing.T) {
+	server := newServer(t)
+
+	c, err := New(server.URL+"/openapi.yaml", WithOperations("getPet"))
+	require.NoError(t, err)
+
+	tools, err := c.Tools(context.Background())
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+
+	_, err = c.Execute(context.Background(), "updatePet", nil)
+	require.Error(t, err)
+
+	// operations are listed by their id, or by method and path without one
+	c, err = New(server.URL+"/openapi.yaml", WithOperations("updatePet", "post_/pets", "post_pets"))
+	require.NoError(t, err)
+
+	tools, err = c.Tools(context.Background())
+	require.NoError(t, err)
+	require.Len(t, tools, 2)
+
+	require.Equal(t, "post_pets", tools[0].Name)
+	require.Equal(t, "updatePet", tools[1].Name)
+}
@@ -0,0 +1,40 @@
+package openapi
+
+import (
+	"net/http"
+)
+
+type Option func(*Client)
+
+func WithClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.client = client
+	}
+}
+
+// WithURL overrides the server url of the document
+func WithURL(url string) Option {
+	return func(c *Client) {
+		c.url = url
+	}
+}
+
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+func WithHeaders(headers map[string]string) Option {
+	return func(c *Client) {
+		c.headers = headers
+	}
+}
+
+// WithOperations limits the tools to the given operation ids,
+// operations without one are given as method_path like "post_/pets"
+func WithOperations(operations ...string) Option {
+	return func(c *Client) {
+		c.operations = operations
+	}
+}
@@ -0,0 +1,389 @@
+package openapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type operation struct {
+	// ID is the operationId, or method_path for operations without one
+	ID string
+
+	Name        string
+	Description string
+
+	Method string
+	Path   string
+
+	Parameters []parameter
+
+	// Body is the name of the argument holding the json request body, empty without one
+	Body         string
+	BodyRequired bool
+
+	Schema map[string]any
+}
+
+type parameter struct {
+	Name string
+	In   string
+}
+
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// loadSpec reads an openapi 3 document in json or yaml from a file or url
+func loadSpec(ctx context.Context, client *http.Client, location string) (map[string]any, error) {
+	var data []byte
+
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		req, _ := http.NewRequestWithContext(ctx, "GET", location, nil)
+
+		resp, err := client.Do(req)
+
+		if err != nil {
+			return nil, err
+		}
+
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.New("unable to load openapi document")
+		}
+
+		if data, err = io.ReadAll(resp.Body); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+
+		if data, err = os.ReadFile(location); err != nil {
+			return nil, err
+		}
+	}
+
+	var spec map[string]any
+
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, err
+	}
+
+	if version, _ := spec["openapi"].(string); !strings.HasPrefix(version, "3.") {
+		return nil, errors.New("unsupported openapi version, expected 3.x")
+	}
+
+	return spec, nil
+}
+
+// serverURL returns the first server of the document, resolved against the document location
+func serverURL(spec map[string]any, location string) (string, error) {
+	var server string
+
+	if servers, ok := spec["servers"].([]any); ok && len(servers) > 0 {
+		if s, ok := servers[0].(map[string]any); ok {
+			server, _ = s["url"].(string)
+
+			// server variables are replaced by their defaults
+			if variables, ok := s["variables"].(map[string]any); ok {
+				for name, v := range variables {
+					if v, ok := v.(map[string]any); ok {
+						value, _ := v["default"].(string)
+						server = strings.ReplaceAll(server, "{"+name+"}", value)
+					}
+				}
+			}
+		}
+	}
+
+	u, err := url.Parse(server)
+
+	if err != nil {
+		return "", err
+	}
+
+	if u.IsAbs() {
+		return strings.TrimRight(u.String(), "/"), nil
+	}
+
+	base, err := url.Parse(location)
+
+	if err != nil || !base.IsAbs() {
+		return "", errors.New("missing server url")
+	}
+
+	return strings.TrimRight(base.ResolveReference(u).String(), "/"), nil
+}
+
+// parseOperations generates a tool operation for every path and method of the document
+func parseOperations(spec map[string]any) []operation {
+	var result []operation
+
+	paths, _ := spec["paths"].(map[string]any)
+
+	var keys []string
+
+	for path := range paths {
+		keys = append(keys, path)
+	}
+
+	slices.Sort(keys)
+
+	for _, path := range keys {
+		item, _ := resolve(spec, paths[path], nil).(map[string]any)
+
+		if item == nil {
+			continue
+		}
+
+		for _, method := range methods {
+			op, ok := item[method].(map[string]any)
+
+			if !ok {
+				continue
+			}
+
+			result = append(result, parseOperation(spec, method, path, item, op))
+		}
+	}
+
+	return result
+}
+
+func parseOperation(spec map[string]any, method, path string, item, op map[string]any) operation {
+	id, _ := op["operationId"].(string)
+
+	if id == "" {
+		id = method + "_" + path
+	}
+
+	summary, _ := op["summary"].(string)
+	description, _ := op["description"].(string)
+
+	if summary != "" && description != "" {
+		description = summary + "\n\n" + description
+	} else if summary != "" {
+		description = summary
+	}
+
+	result := operation{
+		ID: id,
+
+		Name:        toolName(id),
+		Description: description,
+
+		Method: strings.ToUpper(method),
+		Path:   path,
+	}
+
+	properties := map[string]any{}
+	required := []string{}
+
+	// operation parameters override the parameters of the path
+	var params []any
+
+	if p, ok := item["parameters"].([]any); ok {
+		params = append(params, p...)
+	}
+
+	if p, ok := op["parameters"].([]any); ok {
+		params = append(params, p...)
+	}
+
+	for _, p := range params {
+		p, _ := resolve(spec, p, nil).(map[string]any)
+
+		if p == nil {
+			continue
+		}
+
+		name, _ := p["name"].(string)
+		in, _ := p["in"].(string)
+
+		if name == "" || (in != "path" && in != "query" && in != "header") {
+			continue
+		}
+
+		schema, _ := resolve(spec, p["schema"], nil).(map[string]any)
+
+		if schema == nil {
+			schema = map[string]any{"type": "string"}
+		}
+
+		if description, ok := p["description"].(string); ok && description != "" {
+			schema["description"] = description
+		}
+
+		result.Parameters = slices.DeleteFunc(result.Parameters, func(p parameter) bool {
+			return p.Name == name
+		})
+
+		result.Parameters = append(result.Parameters, parameter{
+			Name: name,
+			In:   in,
+		})
+
+		properties[name] = schema
+
+		if r, _ := p["required"].(bool); r || in == "path" {
+			if !slices.Contains(required, name) {
+				required = append(required, name)
+			}
+		}
+	}
+
+	if body, ok := resolve(spec, op["requestBody"], nil).(map[string]any); ok {
+		if content, ok := body["content"].(map[string]any); ok {
+			if media, ok := content["application/json"].(map[string]any); ok {
+				schema, _ := resolve(spec, media["schema"], nil).(map[string]any)
+
+				if schema == nil {
+					schema = map[string]any{}
+				}
+
+				if description, ok := body["description"].(string); ok && description != "" {
+					schema["description"] = description
+				}
+
+				// a parameter may already be called body
+				name := "body"
+
+				for i := 1; properties[name] != nil; i++ {
+					name = "request_body"
+
+					if i > 1 {
+						name += "_" + strconv.Itoa(i)
+					}
+				}
+
+				result.Body = name
+				result.BodyRequired, _ = body["required"].(bool)
+
+				properties[name] = schema
+
+				if result.BodyRequired {
+					required = append(required, name)
+				}
+			}
+		}
+	}
+
+	result.Schema = map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+
+	if len(required) > 0 {
+		result.Schema["required"] = required
+	}
+
+	return result
+}
+
+// maxRefDepth limits the nesting of references, as a schema referencing itself would never end
+const maxRefDepth = 16
+
+// resolve replaces local references, cutting off cyclic and too deeply nested ones with an empty schema.
+// refs holds the references being expanded.
+func resolve(spec map[string]any, v any, refs []string) any {
+	switch v := v.(type) {
+	case map[string]any:
+		if ref, ok := v["$ref"].(string); ok {
+			if len(refs) >= maxRefDepth || slices.Contains(refs, ref) {
+				return map[string]any{}
+			}
+
+			return resolve(spec, lookup(spec, ref), append(slices.Clip(refs), ref))
+		}
+
+		result := make(map[string]any, len(v))
+
+		for key, value := range v {
+			result[key] = resolve(spec, value, refs)
+		}
+
+		return result
+
+	case []any:
+		result := make([]any, len(v))
+
+		for i, value := range v {
+			result[i] = resolve(spec, value, refs)
+		}
+
+		return result
+	}
+
+	return v
+}
+
+func lookup(spec map[string]any, ref string) any {
+	pointer, ok := strings.CutPrefix(ref, "#/")
+
+	if !ok {
+		return map[string]any{}
+	}
+
+	var current any = spec
+
+	for _, part := range strings.Split(pointer, "/") {
+		part = strings.ReplaceAll(part, "~1", "/")
+		part = strings.ReplaceAll(part, "~0", "~")
+
+		m, ok := current.(map[string]any)
+
+		if !ok {
+			return map[string]any{}
+		}
+
+		current = m[part]
+	}
+
+	if current == nil {
+		return map[string]any{}
+	}
+
+	return current
+}
+
+var invalidName = regexp.MustCompile(`[^a-zA-Z0-9-]+`)
+
+// maxNameLength is the longest tool name accepted by model providers
+const maxNameLength = 64
+
+// toolName sanitizes operation ids to the names accepted by model providers
+func toolName(name string) string {
+	name = invalidName.ReplaceAllString(name, "_")
+	name = strings.Trim(name, "_")
+
+	if len(name) > maxNameLength {
+		name = name[:maxNameLength]
+	}
+
+	return name
+}
+
+// uniqueNames numbers the names of operations colliding after sanitizing
+func uniqueNames(operations []operation) {
+	names := map[string]bool{}
+
+	for i := range operations {
+		name := operations[i].Name
+
+		for n := 2; names[name]; n++ {
+			suffix := "_" + strconv.Itoa(n)
+			name = operations[i].Name[:min(len(operations[i].Name), maxNameLength-len(suffix))] + suffix
+		}
+
+		names[name] = true
+		operations[i].Name = name
+	}
+}
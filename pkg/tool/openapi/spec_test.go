@@ -0,0 +1,158 @@
+package openapi
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveNested(t *testing.T) {
+	// inline schemas nest deeper than any reference limit
+	schema := map[string]any{"type": "string", "maxLength": 10}
+	expected := map[string]any{"type": "string", "maxLength": 10}
+
+	for range 40 {
+		schema = map[string]any{
+			"type":     "object",
+			"required": []any{"value"},
+
+			"properties": map[string]any{
+				"value": schema,
+			},
+		}
+
+		expected = map[string]any{
+			"type":     "object",
+			"required": []any{"value"},
+
+			"properties": map[string]any{
+				"value": expected,
+			},
+		}
+	}
+
+	require.Equal(t, expected, resolve(map[string]any{}, schema, nil))
+}
+
+func TestResolveReferences(t *testing.T) {
+	spec := map[string]any{
+		"components": map[string]any{
+			"schemas": map[string]any{
+				"Pet": map[string]any{
+					"type": "object",
+
+					"properties": map[string]any{
+						"name":  map[string]any{"$ref": "#/components/schemas/Name"},
+						"owner": map[string]any{"$ref": "#/components/schemas/Owner"},
+					},
+				},
+
+				"Name": map[string]any{"type": "string"},
+
+				"Owner": map[string]any{
+					"type": "object",
+
+					"properties": map[string]any{
+						"name": map[string]any{"$ref": "#/components/schemas/Name"},
+						"pets": map[string]any{
+							"type":  "array",
+							"items": map[string]any{"$ref": "#/components/schemas/Pet"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	result := resolve(spec, map[string]any{"$ref": "#/components/schemas/Pet"}, nil)
+
+	require.Equal(t, map[string]any{
+		"type": "object",
+
+		"properties": map[string]any{
+			"name": map[string]any{"type": "string"},
+			"owner": map[string]any{
+				"type": "object",
+
+				"properties": map[string]any{
+					"name": map[string]any{"type": "string"},
+					"pets": map[string]any{
+						"type":  "array",
+						"items": map[string]any{},
+					},
+				},
+			},
+		},
+	}, result)
+}
+
+func TestResolveReferenceDepth(t *testing.T) {
+	schemas := map[string]any{}
+
+	// a chain of distinct references longer than the limit
+	for i := range 20 {
+		schemas["S"+strconv.Itoa(i)] = map[string]any{
+			"type": "object",
+
+			"properties": map[string]any{
+				"next": map[string]any{"$ref": "#/components/schemas/S" + strconv.Itoa(i+1)},
+			},
+		}
+	}
+
+	spec := map[string]any{
+		"components": map[string]any{
+			"schemas": schemas,
+		},
+	}
+
+	result := resolve(spec, map[string]any{"$ref": "#/components/schemas/S0"}, nil)
+
+	depth := 0
+
+	for {
+		properties, ok := result.(map[string]any)["properties"].(map[string]any)
+
+		if !ok {
+			break
+		}
+
+		result = properties["next"]
+		depth++
+	}
+
+	require.Equal(t, maxRefDepth, depth)
+	require.Equal(t, map[string]any{}, result)
+}
+
+func TestUniqueNames(t *testing.T) {
+	long := strings.Repeat("a", 70)
+
+	operations := []operation{
+		{ID: "get.pet", Name: toolName("get.pet")},
+		{ID: "get_pet", Name: toolName("get_pet")},
+		{ID: "get pet", Name: toolName("get pet")},
+		{ID: long + "1", Name: toolName(long + "1")},
+		{ID: long + "2", Name: toolName(long + "2")},
+		{ID: "get_pet_2", Name: toolName("get_pet_2")},
+	}
+
+	uniqueNames(operations)
+
+	var names []string
+
+	for _, op := range operations {
+		names = append(names, op.Name)
+	}
+
+	require.Equal(t, []string{
+		"get_pet",
+		"get_pet_2",
+		"get_pet_3",
+		strings.Repeat("a", 64),
+		strings.Repeat("a", 62) + "_2",
+		"get_pet_2_2",
+	}, names)
+}
@@ -0,0 +1,69 @@
+package anthropic
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/adrianliechti/wingman/config"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	*config.Config
+	http.Handler
+}
+
+func New(cfg *config.Config) (*Handler, error) {
+	mux := chi.NewMux()
+
+	h := &Handler{
+		Config:  cfg,
+		Handler: mux,
+	}
+
+	h.Attach(mux)
+	return h, nil
+}
+
+func (h *Handler) Attach(r chi.Router) {
+	r.Post("/messages", h.handleMessages)
+}
+
+func writeJson(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	errorType := "invalid_request_error"
+
+	switch {
+	case code == http.StatusNotFound:
+		errorType = "not_found_error"
+
+	case code >= 500:
+		errorType = "api_error"
+	}
+
+	resp := ErrorResponse{
+		Type: "error",
+
+		Error: Error{
+			Type:    errorType,
+			Message: err.Error(),
+		},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	enc.Encode(resp)
+}
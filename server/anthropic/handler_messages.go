@@ -0,0 +1,567 @@
+package anthropic
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/to"
+	"github.com/adrianliechti/wingman/server/sse"
+
+	"github.com/google/uuid"
+)
+
+func (h *Handler) handleMessages(w http.ResponseWriter, r *http.Request) {
+	var req MessageRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	completer, err := h.Completer(req.Model)
+
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	messages, err := toMessages(req.System, req.Messages)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	tools, err := toTools(req.Tools)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	options := &provider.CompleteOptions{
+		Stop:  req.StopSequences,
+		Tools: tools,
+
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+	}
+
+	if req.Thinking != nil && req.Thinking.Type == "enabled" {
+		options.Effort = toEffort(req.Thinking.BudgetTokens)
+		options.ReasoningBudget = to.Ptr(req.Thinking.BudgetTokens)
+	}
+
+	id := "msg_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+
+	if req.Stream {
+		events, ctx := sse.New(w, r, sse.WithHeartbeat(string(EventTypePing), Event{Type: EventTypePing}))
+		defer events.Close()
+
+		s := &messageStream{
+			events: events,
+		}
+
+		message := MessageResponse{
+			Type: "message",
+
+			ID:   id,
+			Role: MessageRoleAssistant,
+
+			Model: req.Model,
+
+			Content: []ResponseBlock{},
+		}
+
+		if err := s.send(Event{Type: EventTypeMessageStart, Message: &message}); err != nil {
+			return
+		}
+
+		if err := s.send(Event{Type: EventTypePing}); err != nil {
+			return
+		}
+
+		options.Stream = func(ctx context.Context, completion provider.Completion) error {
+			return s.delta(completion.Message)
+		}
+
+		completion, err := completer.Complete(ctx, messages, options)
+
+		if err != nil {
+			// the client is gone, so there is no one left to report to
+			if ctx.Err() != nil {
+				return
+			}
+
+			s.send(Event{
+				Type: EventTypeError,
+
+				Error: &Error{
+					Type:    "api_error",
+					Message: err.Error(),
+				},
+			})
+
+			return
+		}
+
+		s.finish(completion)
+	} else {
+		completion, err := completer.Complete(r.Context(), messages, options)
+
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		result := MessageResponse{
+			Type: "message",
+
+			ID:   id,
+			Role: MessageRoleAssistant,
+
+			Model: req.Model,
+
+			Content: []ResponseBlock{},
+
+			StopReason: toStopReason(completion),
+		}
+
+		if completion.Message.Reasoning != "" {
+			result.Content = append(result.Content, ResponseBlock{
+				Type: ContentTypeThinking,
+
+				Thinking:  to.Ptr(completion.Message.Reasoning),
+				Signature: to.Ptr(completion.Message.ReasoningSignature),
+			})
+		}
+
+		if completion.Message.Content != "" {
+			result.Content = append(result.Content, ResponseBlock{
+				Type: ContentTypeText,
+				Text: to.Ptr(completion.Message.Content),
+			})
+		}
+
+		for _, c := range completion.Message.ToolCalls {
+			result.Content = append(result.Content, toToolUseBlock(c))
+		}
+
+		if completion.Usage != nil {
+			result.Usage = Usage{
+				InputTokens:  completion.Usage.InputTokens,
+				OutputTokens: completion.Usage.OutputTokens,
+			}
+		}
+
+		writeJson(w, result)
+	}
+}
+
+// messageStream writes the server-sent events of a streamed message
+type messageStream struct {
+	events *sse.Stream
+
+	// blocks counts the emitted content blocks, the last one is still open unless current is empty
+	blocks  int
+	current ContentType
+
+	started bool
+}
+
+func (s *messageStream) delta(message provider.Message) error {
+	if message.Reasoning != "" || message.ReasoningSignature != "" {
+		if err := s.open(ContentTypeThinking); err != nil {
+			return err
+		}
+
+		if message.Reasoning != "" {
+			if err := s.send(Event{Type: EventTypeContentBlockDelta, Index: to.Ptr(s.blocks - 1), Delta: &Delta{Type: DeltaTypeThinking, Thinking: message.Reasoning}}); err != nil {
+				return err
+			}
+		}
+
+		if message.ReasoningSignature != "" {
+			if err := s.send(Event{Type: EventTypeContentBlockDelta, Index: to.Ptr(s.blocks - 1), Delta: &Delta{Type: DeltaTypeSignature, Signature: message.ReasoningSignature}}); err != nil {
+				return err
+			}
+		}
+	}
+
+	if message.Content != "" {
+		if err := s.open(ContentTypeText); err != nil {
+			return err
+		}
+
+		if err := s.send(Event{Type: EventTypeContentBlockDelta, Index: to.Ptr(s.blocks - 1), Delta: &Delta{Type: DeltaTypeText, Text: message.Content}}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// open starts a content block of the given type, closing the previous one
+func (s *messageStream) open(t ContentType) error {
+	if s.current == t {
+		return nil
+	}
+
+	if err := s.stop(); err != nil {
+		return err
+	}
+
+	block := ResponseBlock{
+		Type: t,
+	}
+
+	switch t {
+	case ContentTypeThinking:
+		block.Thinking = to.Ptr("")
+		block.Signature = to.Ptr("")
+
+	default:
+		block.Text = to.Ptr("")
+	}
+
+	if err := s.send(Event{Type: EventTypeContentBlockStart, Index: to.Ptr(s.blocks), ContentBlock: &block}); err != nil {
+		return err
+	}
+
+	s.blocks++
+	s.current = t
+	s.started = true
+
+	return nil
+}
+
+func (s *messageStream) stop() error {
+	if s.current == "" {
+		return nil
+	}
+
+	s.current = ""
+
+	return s.send(Event{Type: EventTypeContentBlockStop, Index: to.Ptr(s.blocks - 1)})
+}
+
+func (s *messageStream) finish(completion *provider.Completion) error {
+	// providers not streaming any text still produce the events for the final content
+	if !s.started {
+		if err := s.delta(completion.Message); err != nil {
+			return err
+		}
+	}
+
+	if err := s.stop(); err != nil {
+		return err
+	}
+
+	// tool calls arrive in provider specific fragments, so they are emitted once complete
+	for _, c := range completion.Message.ToolCalls {
+		index := s.blocks
+		s.blocks++
+
+		block := toToolUseBlock(c)
+
+		arguments := string(block.Input)
+		block.Input = json.RawMessage("{}")
+
+		if err := s.send(Event{Type: EventTypeContentBlockStart, Index: to.Ptr(index), ContentBlock: &block}); err != nil {
+			return err
+		}
+
+		if err := s.send(Event{Type: EventTypeContentBlockDelta, Index: to.Ptr(index), Delta: &Delta{Type: DeltaTypeInputJSON, PartialJSON: arguments}}); err != nil {
+			return err
+		}
+
+		if err := s.send(Event{Type: EventTypeContentBlockStop, Index: to.Ptr(index)}); err != nil {
+			return err
+		}
+	}
+
+	event := Event{
+		Type: EventTypeMessageDelta,
+
+		Delta: &Delta{
+			StopReason: toStopReason(completion),
+		},
+
+		Usage: &Usage{},
+	}
+
+	if completion.Usage != nil {
+		event.Usage = &Usage{
+			InputTokens:  completion.Usage.InputTokens,
+			OutputTokens: completion.Usage.OutputTokens,
+		}
+	}
+
+	if err := s.send(event); err != nil {
+		return err
+	}
+
+	return s.send(Event{Type: EventTypeMessageStop})
+}
+
+func (s *messageStream) send(event Event) error {
+	return s.events.Send(string(event.Type), event)
+}
+
+func toMessages(system Content, messages []Message) ([]provider.Message, error) {
+	var result []provider.Message
+
+	if text := toText(system); text != "" {
+		result = append(result, provider.Message{
+			Role:    provider.MessageRoleSystem,
+			Content: text,
+		})
+	}
+
+	for _, m := range messages {
+		switch m.Role {
+		case MessageRoleUser:
+			message := provider.Message{
+				Role: provider.MessageRoleUser,
+			}
+
+			for _, b := range m.Content {
+				switch b.Type {
+				case ContentTypeText:
+					message.Content = joinText(message.Content, b.Text)
+
+				case ContentTypeImage, ContentTypeDocument:
+					file, err := toFile(b.Source)
+
+					if err != nil {
+						return nil, err
+					}
+
+					message.Files = append(message.Files, *file)
+
+				case ContentTypeToolResult:
+					// tool results must directly follow the assistant turn calling the tools
+					result = append(result, provider.Message{
+						Role:    provider.MessageRoleTool,
+						Content: toText(b.Content),
+
+						Tool: b.ToolUseID,
+					})
+
+				default:
+					return nil, errors.New("unsupported content type: " + string(b.Type))
+				}
+			}
+
+			if message.Content != "" || len(message.Files) > 0 {
+				result = append(result, message)
+			}
+
+		case MessageRoleAssistant:
+			message := provider.Message{
+				Role: provider.MessageRoleAssistant,
+			}
+
+			for _, b := range m.Content {
+				switch b.Type {
+				case ContentTypeText:
+					message.Content = joinText(message.Content, b.Text)
+
+				case ContentTypeToolUse:
+					arguments := string(b.Input)
+
+					if arguments == "" {
+						arguments = "{}"
+					}
+
+					message.ToolCalls = append(message.ToolCalls, provider.ToolCall{
+						ID: b.ID,
+
+						Name:      b.Name,
+						Arguments: arguments,
+					})
+
+				case ContentTypeThinking:
+					message.Reasoning = joinText(message.Reasoning, b.Thinking)
+					message.ReasoningSignature = b.Signature
+
+				case ContentTypeRedactedThinking:
+					continue
+
+				default:
+					return nil, errors.New("unsupported content type: " + string(b.Type))
+				}
+			}
+
+			result = append(result, message)
+
+		default:
+			return nil, errors.New("invalid message role: " + string(m.Role))
+		}
+	}
+
+	return result, nil
+}
+
+func toText(content Content) string {
+	var result string
+
+	for _, b := range content {
+		if b.Type == ContentTypeText {
+			result = joinText(result, b.Text)
+		}
+	}
+
+	return result
+}
+
+func joinText(a, b string) string {
+	if a == "" {
+		return b
+	}
+
+	if b == "" {
+		return a
+	}
+
+	return a + "\n\n" + b
+}
+
+func toFile(source *Source) (*provider.File, error) {
+	if source == nil {
+		return nil, errors.New("missing content source")
+	}
+
+	var file provider.File
+
+	switch source.Type {
+	case SourceTypeBase64:
+		data, err := base64.StdEncoding.DecodeString(source.Data)
+
+		if err != nil {
+			return nil, errors.New("invalid data encoding")
+		}
+
+		file = provider.File{
+			Content:     bytes.NewReader(data),
+			ContentType: source.MediaType,
+		}
+
+	case SourceTypeText:
+		file = provider.File{
+			Content:     strings.NewReader(source.Data),
+			ContentType: "text/plain",
+		}
+
+	case SourceTypeURL:
+		resp, err := http.Get(source.URL)
+
+		if err != nil {
+			return nil, err
+		}
+
+		defer resp.Body.Close()
+
+		data, err := io.ReadAll(resp.Body)
+
+		if err != nil {
+			return nil, err
+		}
+
+		file = provider.File{
+			Content:     bytes.NewReader(data),
+			ContentType: resp.Header.Get("Content-Type"),
+		}
+
+	default:
+		return nil, errors.New("unsupported source type: " + string(source.Type))
+	}
+
+	if ext, _ := mime.ExtensionsByType(file.ContentType); len(ext) > 0 {
+		file.Name = uuid.New().String() + ext[0]
+	}
+
+	return &file, nil
+}
+
+func toTools(tools []Tool) ([]provider.Tool, error) {
+	var result []provider.Tool
+
+	for _, t := range tools {
+		if t.Type != "" && t.Type != "custom" {
+			return nil, errors.New("unsupported tool type: " + t.Type)
+		}
+
+		result = append(result, provider.Tool{
+			Name:        t.Name,
+			Description: t.Description,
+
+			Parameters: t.InputSchema,
+		})
+	}
+
+	return result, nil
+}
+
+// toEffort maps an extended thinking budget onto the closest reasoning effort
+func toEffort(budget int) provider.ReasoningEffort {
+	switch {
+	case budget <= 4096:
+		return provider.ReasoningEffortLow
+
+	case budget <= 16384:
+		return provider.ReasoningEffortMedium
+
+	default:
+		return provider.ReasoningEffortHigh
+	}
+}
+
+func toToolUseBlock(call provider.ToolCall) ResponseBlock {
+	input := json.RawMessage(call.Arguments)
+
+	if !json.Valid(input) {
+		input = json.RawMessage("{}")
+	}
+
+	id := call.ID
+
+	if id == "" {
+		id = "toolu_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	}
+
+	return ResponseBlock{
+		Type: ContentTypeToolUse,
+
+		ID:    id,
+		Name:  call.Name,
+		Input: input,
+	}
+}
+
+func toStopReason(completion *provider.Completion) *StopReason {
+	switch completion.Reason {
+	case provider.CompletionReasonLength:
+		return &StopReasonMaxTokens
+
+	case provider.CompletionReasonTool:
+		return &StopReasonToolUse
+
+	case provider.CompletionReasonFilter:
+		return &StopReasonRefusal
+	}
+
+	if len(completion.Message.ToolCalls) > 0 {
+		return &StopReasonToolUse
+	}
+
+	return &StopReasonEndTurn
+}
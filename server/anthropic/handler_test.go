@@ -0,0 +1,335 @@
+package anthropic_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/adrianliechti/wingman/config"
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/server/anthropic"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessages(t *testing.T) {
+	c := &testCompleter{}
+	h := newHandler(t, c)
+
+	resp := post(t, h, `{"model": "test", "max_tokens": 100, "system": "Be brief.", "messages": [{"role": "user", "content": "hi"}]}`)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var result anthropic.MessageResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+
+	require.Equal(t, "message", result.Type)
+	require.Equal(t, "test", result.Model)
+	require.Equal(t, anthropic.StopReasonEndTurn, *result.StopReason)
+	require.Equal(t, anthropic.Usage{InputTokens: 3, OutputTokens: 2}, result.Usage)
+
+	require.Len(t, result.Content, 1)
+	require.Equal(t, anthropic.ContentTypeText, result.Content[0].Type)
+	require.Equal(t, "Hello", *result.Content[0].Text)
+
+	require.Equal(t, []provider.Message{
+		{Role: provider.MessageRoleSystem, Content: "Be brief."},
+		{Role: provider.MessageRoleUser, Content: "hi"},
+	}, c.messages)
+
+	require.Equal(t, 100, *c.options.MaxTokens)
+}
+
+func TestMessagesToolUse(t *testing.T) {
+	c := &testCompleter{}
+	h := newHandler(t, c)
+
+	resp := post(t, h, `{"model": "test", "tools": [{"name": "weather", "input_schema": {"type": "object"}}], "messages": [{"role": "user", "content": "Weather in Bern?"}]}`)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var result anthropic.MessageResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+
+	require.Equal(t, anthropic.StopReasonToolUse, *result.StopReason)
+	require.Len(t, result.Content, 1)
+
+	block := result.Content[0]
+
+	require.Equal(t, anthropic.ContentTypeToolUse, block.Type)
+	require.Equal(t, "toolu_1", block.ID)
+	require.Equal(t, "weather", block.Name)
+	require.JSONEq(t, `{"city": "Bern"}`, string(block.Input))
+
+	require.Equal(t, []provider.Tool{
+		{Name: "weather", Parameters: map[string]any{"type": "object"}},
+	}, c.options.Tools)
+
+	// the tool result is sent back in a user turn, right after the assistant turn calling the tool
+	resp = post(t, h, `{"model": "test", "messages": [
+		{"role": "user", "content": "Weather in Bern?"},
+		{"role": "assistant", "content": [{"type": "tool_use", "id": "toolu_1", "name": "weather", "input": {"city": "Bern"}}]},
+		{"role": "user", "content": [{"type": "tool_result", "tool_use_id": "toolu_1", "content": "sunny"}, {"type": "text", "text": "Thanks"}]}
+	]}`)
+
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+	require.Equal(t, "It is sunny.", *result.Content[0].Text)
+
+	require.Equal(t, []provider.Message{
+		{Role: provider.MessageRoleUser, Content: "Weather in Bern?"},
+		{Role: provider.MessageRoleAssistant, ToolCalls: []provider.ToolCall{{ID: "toolu_1", Name: "weather", Arguments: `{"city": "Bern"}`}}},
+		{Role: provider.MessageRoleTool, Content: "sunny", Tool: "toolu_1"},
+		{Role: provider.MessageRoleUser, Content: "Thanks"},
+	}, c.messages)
+}
+
+func TestMessagesStream(t *testing.T) {
+	h := newHandler(t, &testCompleter{})
+
+	events := stream(t, h, `{"model": "test", "stream": true, "messages": [{"role": "user", "content": "hi"}]}`)
+
+	require.Equal(t, []anthropic.EventType{
+		anthropic.EventTypeMessageStart,
+		anthropic.EventTypePing,
+		anthropic.EventTypeContentBlockStart,
+		anthropic.EventTypeContentBlockDelta,
+		anthropic.EventTypeContentBlockDelta,
+		anthropic.EventTypeContentBlockStop,
+		anthropic.EventTypeMessageDelta,
+		anthropic.EventTypeMessageStop,
+	}, eventTypes(events))
+
+	require.Equal(t, "test", events[0].Message.Model)
+
+	require.Equal(t, anthropic.ContentTypeText, events[2].ContentBlock.Type)
+	require.Equal(t, "Hel", events[3].Delta.Text)
+	require.Equal(t, "lo", events[4].Delta.Text)
+
+	require.Equal(t, anthropic.StopReasonEndTurn, *events[6].Delta.StopReason)
+	require.Equal(t, &anthropic.Usage{InputTokens: 3, OutputTokens: 2}, events[6].Usage)
+}
+
+func TestMessagesStreamToolUse(t *testing.T) {
+	h := newHandler(t, &testCompleter{})
+
+	events := stream(t, h, `{"model": "test", "stream": true, "thinking": {"type": "enabled", "budget_tokens": 1024}, "tools": [{"name": "weather"}], "messages": [{"role": "user", "content": "Weather in Bern?"}]}`)
+
+	require.Equal(t, []anthropic.EventType{
+		anthropic.EventTypeMessageStart,
+		anthropic.EventTypePing,
+		anthropic.EventTypeContentBlockStart, // thinking
+		anthropic.EventTypeContentBlockDelta,
+		anthropic.EventTypeContentBlockDelta,
+		anthropic.EventTypeContentBlockStop,
+		anthropic.EventTypeContentBlockStart, // text
+		anthropic.EventTypeContentBlockDelta,
+		anthropic.EventTypeContentBlockStop,
+		anthropic.EventTypeContentBlockStart, // tool use
+		anthropic.EventTypeContentBlockDelta,
+		anthropic.EventTypeContentBlockStop,
+		anthropic.EventTypeMessageDelta,
+		anthropic.EventTypeMessageStop,
+	}, eventTypes(events))
+
+	require.Equal(t, anthropic.ContentTypeThinking, events[2].ContentBlock.Type)
+	require.Equal(t, anthropic.DeltaTypeThinking, events[3].Delta.Type)
+	require.Equal(t, anthropic.DeltaTypeSignature, events[4].Delta.Type)
+
+	require.Equal(t, 1, *events[6].Index)
+	require.Equal(t, anthropic.ContentTypeText, events[6].ContentBlock.Type)
+
+	tool := events[9]
+
+	require.Equal(t, 2, *tool.Index)
+	require.Equal(t, anthropic.ContentTypeToolUse, tool.ContentBlock.Type)
+	require.Equal(t, "toolu_1", tool.ContentBlock.ID)
+	require.JSONEq(t, `{}`, string(tool.ContentBlock.Input))
+
+	require.Equal(t, anthropic.DeltaTypeInputJSON, events[10].Delta.Type)
+	require.JSONEq(t, `{"city": "Bern"}`, events[10].Delta.PartialJSON)
+
+	require.Equal(t, anthropic.StopReasonToolUse, *events[12].Delta.StopReason)
+}
+
+func TestMessagesErrors(t *testing.T) {
+	h := newHandler(t, &testCompleter{err: errors.New("overloaded")})
+
+	for _, test := range []struct {
+		body string
+
+		code      int
+		errorType string
+	}{
+		{`{"model": `, http.StatusBadRequest, "invalid_request_error"},
+		{`{"model": "other", "messages": [{"role": "user", "content": "hi"}]}`, http.StatusNotFound, "not_found_error"},
+		{`{"model": "test", "messages": [{"role": "system", "content": "hi"}]}`, http.StatusBadRequest, "invalid_request_error"},
+		{`{"model": "test", "tools": [{"type": "bash_20250124", "name": "bash"}], "messages": [{"role": "user", "content": "hi"}]}`, http.StatusBadRequest, "invalid_request_error"},
+		{`{"model": "test", "messages": [{"role": "user", "content": "hi"}]}`, http.StatusBadRequest, "invalid_request_error"},
+	} {
+		resp := post(t, h, test.body)
+		require.Equal(t, test.code, resp.Code, test.body)
+
+		var result anthropic.ErrorResponse
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+
+		require.Equal(t, "error", result.Type)
+		require.Equal(t, test.errorType, result.Error.Type, test.body)
+		require.NotEmpty(t, result.Error.Message)
+	}
+
+	// a failure after the stream started is reported as error event
+	events := stream(t, h, `{"model": "test", "stream": true, "messages": [{"role": "user", "content": "hi"}]}`)
+
+	require.Equal(t, []anthropic.EventType{
+		anthropic.EventTypeMessageStart,
+		anthropic.EventTypePing,
+		anthropic.EventTypeError,
+	}, eventTypes(events))
+
+	require.Equal(t, &anthropic.Error{Type: "api_error", Message: "overloaded"}, events[2].Error)
+}
+
+func newHandler(t *testing.T, c provider.Completer) *anthropic.Handler {
+	cfg := &config.Config{}
+	cfg.RegisterCompleter("test", c)
+
+	h, err := anthropic.New(cfg)
+	require.NoError(t, err)
+
+	return h
+}
+
+func post(t *testing.T, h http.Handler, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/messages", strings.NewReader(body)))
+
+	return resp
+}
+
+// stream returns the events of a streamed message
+func stream(t *testing.T, h http.Handler, body string) []anthropic.Event {
+	t.Helper()
+
+	resp := post(t, h, body)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, "text/event-stream", resp.Header().Get("Content-Type"))
+
+	var result []anthropic.Event
+
+	scanner := bufio.NewScanner(resp.Body)
+
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+
+		if !ok {
+			continue
+		}
+
+		var event anthropic.Event
+		require.NoError(t, json.Unmarshal([]byte(data), &event))
+
+		result = append(result, event)
+	}
+
+	return result
+}
+
+func eventTypes(events []anthropic.Event) []anthropic.EventType {
+	var result []anthropic.EventType
+
+	for _, e := range events {
+		result = append(result, e.Type)
+	}
+
+	return result
+}
+
+// testCompleter answers with text, calls the weather tool when offered, and answers with its result once available
+type testCompleter struct {
+	err error
+
+	messages []provider.Message
+	options  *provider.CompleteOptions
+}
+
+func (c *testCompleter) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	c.messages = messages
+	c.options = options
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	usage := &provider.Usage{InputTokens: 3, OutputTokens: 2}
+
+	for _, m := range messages {
+		if m.Role == provider.MessageRoleTool {
+			return &provider.Completion{
+				Reason: provider.CompletionReasonStop,
+
+				Message: provider.Message{
+					Role:    provider.MessageRoleAssistant,
+					Content: "It is " + m.Content + ".",
+				},
+
+				Usage: usage,
+			}, nil
+		}
+	}
+
+	if len(options.Tools) > 0 {
+		if options.Stream != nil {
+			for _, delta := range []provider.Message{
+				{Reasoning: "The user asks for the weather."},
+				{ReasoningSignature: "c2lnbmF0dXJl"},
+				{Content: "Let me check."},
+			} {
+				delta.Role = provider.MessageRoleAssistant
+
+				if err := options.Stream(ctx, provider.Completion{Message: delta}); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		return &provider.Completion{
+			Reason: provider.CompletionReasonTool,
+
+			Message: provider.Message{
+				Role: provider.MessageRoleAssistant,
+
+				ToolCalls: []provider.ToolCall{
+					{ID: "toolu_1", Name: "weather", Arguments: `{"city": "Bern"}`},
+				},
+			},
+
+			Usage: usage,
+		}, nil
+	}
+
+	if options.Stream != nil {
+		for _, chunk := range []string{"Hel", "lo"} {
+			if err := options.Stream(ctx, provider.Completion{Message: provider.Message{Role: provider.MessageRoleAssistant, Content: chunk}}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &provider.Completion{
+		Reason: provider.CompletionReasonStop,
+
+		Message: provider.Message{
+			Role:    provider.MessageRoleAssistant,
+			Content: "Hello",
+		},
+
+		Usage: usage,
+	}, nil
+}
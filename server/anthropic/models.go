@@ -0,0 +1,242 @@
+package anthropic
+
+import (
+	"encoding/json"
+)
+
+type MessageRole string
+
+var (
+	MessageRoleUser      MessageRole = "user"
+	MessageRoleAssistant MessageRole = "assistant"
+)
+
+// https://docs.anthropic.com/en/api/messages
+type MessageRequest struct {
+	Model string `json:"model"`
+
+	System   Content   `json:"system,omitempty"`
+	Messages []Message `json:"messages"`
+
+	Stream bool   `json:"stream,omitempty"`
+	Tools  []Tool `json:"tools,omitempty"`
+
+	MaxTokens     *int     `json:"max_tokens,omitempty"`
+	Temperature   *float32 `json:"temperature,omitempty"`
+	StopSequences []string `json:"stop_sequences,omitempty"`
+
+	Thinking *Thinking `json:"thinking,omitempty"`
+
+	// tool_choice object
+	// top_k *int
+	// top_p *float32
+
+	// metadata object
+}
+
+type Thinking struct {
+	Type string `json:"type"` // "enabled" | "disabled"
+
+	BudgetTokens int `json:"budget_tokens,omitempty"`
+}
+
+// https://docs.anthropic.com/en/api/messages#body-messages
+type Message struct {
+	Role    MessageRole `json:"role"`
+	Content Content     `json:"content"`
+}
+
+// Content accepts either a plain text or a list of content blocks
+type Content []ContentBlock
+
+func (c *Content) UnmarshalJSON(data []byte) error {
+	var text string
+
+	if err := json.Unmarshal(data, &text); err == nil {
+		*c = Content{
+			{
+				Type: ContentTypeText,
+				Text: text,
+			},
+		}
+
+		return nil
+	}
+
+	var blocks []ContentBlock
+
+	if err := json.Unmarshal(data, &blocks); err != nil {
+		return err
+	}
+
+	*c = blocks
+	return nil
+}
+
+type ContentType string
+
+var (
+	ContentTypeText     ContentType = "text"
+	ContentTypeImage    ContentType = "image"
+	ContentTypeDocument ContentType = "document"
+
+	ContentTypeToolUse    ContentType = "tool_use"
+	ContentTypeToolResult ContentType = "tool_result"
+
+	ContentTypeThinking         ContentType = "thinking"
+	ContentTypeRedactedThinking ContentType = "redacted_thinking"
+)
+
+// https://docs.anthropic.com/en/api/messages#body-messages-content
+type ContentBlock struct {
+	Type ContentType `json:"type"`
+
+	Text string `json:"text,omitempty"`
+
+	Source *Source `json:"source,omitempty"`
+
+	ID    string          `json:"id,omitempty"`
+	Name  string          `json:"name,omitempty"`
+	Input json.RawMessage `json:"input,omitempty"`
+
+	ToolUseID string  `json:"tool_use_id,omitempty"`
+	Content   Content `json:"content,omitempty"`
+	IsError   bool    `json:"is_error,omitempty"`
+
+	Thinking  string `json:"thinking,omitempty"`
+	Signature string `json:"signature,omitempty"`
+}
+
+type SourceType string
+
+var (
+	SourceTypeBase64 SourceType = "base64"
+	SourceTypeURL    SourceType = "url"
+	SourceTypeText   SourceType = "text"
+)
+
+type Source struct {
+	Type SourceType `json:"type"`
+
+	MediaType string `json:"media_type,omitempty"`
+	Data      string `json:"data,omitempty"`
+
+	URL string `json:"url,omitempty"`
+}
+
+// https://docs.anthropic.com/en/api/messages#body-tools
+type Tool struct {
+	Type string `json:"type,omitempty"`
+
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	InputSchema map[string]any `json:"input_schema,omitempty"`
+}
+
+type StopReason string
+
+var (
+	StopReasonEndTurn      StopReason = "end_turn"
+	StopReasonMaxTokens    StopReason = "max_tokens"
+	StopReasonStopSequence StopReason = "stop_sequence"
+	StopReasonToolUse      StopReason = "tool_use"
+	StopReasonRefusal      StopReason = "refusal"
+)
+
+// https://docs.anthropic.com/en/api/messages#response-body
+type MessageResponse struct {
+	Type string `json:"type"` // "message"
+
+	ID   string      `json:"id"`
+	Role MessageRole `json:"role"`
+
+	Model string `json:"model"`
+
+	Content []ResponseBlock `json:"content"`
+
+	StopReason   *StopReason `json:"stop_reason"`
+	StopSequence *string     `json:"stop_sequence"`
+
+	Usage Usage `json:"usage"`
+}
+
+type ResponseBlock struct {
+	Type ContentType `json:"type"`
+
+	Text *string `json:"text,omitempty"`
+
+	Thinking  *string `json:"thinking,omitempty"`
+	Signature *string `json:"signature,omitempty"`
+
+	ID    string          `json:"id,omitempty"`
+	Name  string          `json:"name,omitempty"`
+	Input json.RawMessage `json:"input,omitempty"`
+}
+
+type Usage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+// https://docs.anthropic.com/en/api/messages-streaming
+type EventType string
+
+var (
+	EventTypeMessageStart EventType = "message_start"
+	EventTypeMessageDelta EventType = "message_delta"
+	EventTypeMessageStop  EventType = "message_stop"
+
+	EventTypeContentBlockStart EventType = "content_block_start"
+	EventTypeContentBlockDelta EventType = "content_block_delta"
+	EventTypeContentBlockStop  EventType = "content_block_stop"
+
+	EventTypePing  EventType = "ping"
+	EventTypeError EventType = "error"
+)
+
+type Event struct {
+	Type EventType `json:"type"`
+
+	Message *MessageResponse `json:"message,omitempty"`
+
+	Index        *int           `json:"index,omitempty"`
+	ContentBlock *ResponseBlock `json:"content_block,omitempty"`
+
+	Delta *Delta `json:"delta,omitempty"`
+	Usage *Usage `json:"usage,omitempty"`
+
+	Error *Error `json:"error,omitempty"`
+}
+
+type DeltaType string
+
+var (
+	DeltaTypeText      DeltaType = "text_delta"
+	DeltaTypeInputJSON DeltaType = "input_json_delta"
+	DeltaTypeThinking  DeltaType = "thinking_delta"
+	DeltaTypeSignature DeltaType = "signature_delta"
+)
+
+type Delta struct {
+	Type DeltaType `json:"type,omitempty"`
+
+	Text        string `json:"text,omitempty"`
+	PartialJSON string `json:"partial_json,omitempty"`
+	Thinking    string `json:"thinking,omitempty"`
+	Signature   string `json:"signature,omitempty"`
+
+	StopReason   *StopReason `json:"stop_reason,omitempty"`
+	StopSequence *string     `json:"stop_sequence,omitempty"`
+}
+
+type ErrorResponse struct {
+	Type string `json:"type"` // "error"
+
+	Error Error `json:"error"`
+}
+
+type Error struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
@@ -30,6 +30,21 @@ func (s *Handler) handleQuery(w http.ResponseWriter, r *http.Request) {
 
 	options := &index.QueryOptions{
 		Limit: query.Limit,
+
+		Mode: index.QueryMode(query.Mode),
+
+		Filters: query.Filters,
+	}
+
+	if query.Filter != nil {
+		filter := convertFilter(*query.Filter)
+
+		if err := filter.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		options.Filter = &filter
 	}
 
 	result, err := i.Query(r.Context(), query.Text, options)
@@ -61,3 +76,28 @@ func (s *Handler) handleQuery(w http.ResponseWriter, r *http.Request) {
 
 	writeJson(w, results)
 }
+
+func convertFilter(f Filter) index.Filter {
+	result := index.Filter{
+		Field:    f.Field,
+		Operator: index.FilterOperator(f.Operator),
+
+		Value:  f.Value,
+		Values: f.Values,
+	}
+
+	for _, c := range f.And {
+		result.And = append(result.And, convertFilter(c))
+	}
+
+	for _, c := range f.Or {
+		result.Or = append(result.Or, convertFilter(c))
+	}
+
+	if f.Not != nil {
+		not := convertFilter(*f.Not)
+		result.Not = &not
+	}
+
+	return result
+}
@@ -26,4 +26,23 @@ type Query struct {
 	Text string `json:"text,omitempty"`
 
 	Limit *int `json:"limit,omitempty"`
+
+	// vector (default), keyword or hybrid
+	Mode string `json:"mode,omitempty"`
+
+	Filters map[string]string `json:"filters,omitempty"`
+	Filter  *Filter           `json:"filter,omitempty"`
+}
+
+// Filter is either a condition on a metadata field or a combination of filters
+type Filter struct {
+	Field    string `json:"field,omitempty"`
+	Operator string `json:"op,omitempty"`
+
+	Value  string   `json:"value,omitempty"`
+	Values []string `json:"values,omitempty"`
+
+	And []Filter `json:"and,omitempty"`
+	Or  []Filter `json:"or,omitempty"`
+	Not *Filter  `json:"not,omitempty"`
 }
@@ -0,0 +1,83 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/adrianliechti/wingman/config"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type Handler struct {
+	*config.Config
+	http.Handler
+
+	toolsMu sync.Mutex
+	tools   map[string]*cachedTools
+}
+
+func New(cfg *config.Config) (*Handler, error) {
+	mux := chi.NewMux()
+
+	h := &Handler{
+		Config:  cfg,
+		Handler: mux,
+	}
+
+	h.Attach(mux)
+	return h, nil
+}
+
+func (h *Handler) Attach(r chi.Router) {
+	r.Handle("/mcp", mcp.NewStreamableHTTPHandler(h.newServer, nil))
+}
+
+// newServer publishes the current tools, indexes and chains for each session
+func (h *Handler) newServer(r *http.Request) *mcp.Server {
+	s := mcp.NewServer(&mcp.Implementation{
+		Name:    "wingman",
+		Version: "1.0.0",
+	}, nil)
+
+	ctx := r.Context()
+
+	h.addTools(ctx, s)
+	h.addIndexes(s)
+	h.addChains(s)
+
+	return s
+}
+
+func textResult(v any) (*mcp.CallToolResult, error) {
+	var text string
+
+	switch v := v.(type) {
+	case string:
+		text = v
+
+	default:
+		data, err := json.Marshal(v)
+
+		if err != nil {
+			return nil, err
+		}
+
+		text = string(data)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: text},
+		},
+	}, nil
+}
+
+func errorResult(err error) *mcp.CallToolResult {
+	result := &mcp.CallToolResult{}
+	result.SetError(err)
+
+	return result
+}
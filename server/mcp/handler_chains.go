@@ -0,0 +1,74 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func (h *Handler) addChains(s *mcp.Server) {
+	for _, id := range h.Chains() {
+		completer, err := h.Completer(id)
+
+		if err != nil {
+			continue
+		}
+
+		prompt := &mcp.Prompt{
+			Name: id,
+
+			Arguments: []*mcp.PromptArgument{
+				{
+					Name:        "input",
+					Description: "the input passed to the chain",
+					Required:    true,
+				},
+			},
+		}
+
+		if m, err := h.Model(id); err == nil {
+			prompt.Title = m.Name
+			prompt.Description = m.Description
+		}
+
+		s.AddPrompt(prompt, runChain(completer))
+	}
+}
+
+// runChain completes the input with the chain and returns the conversation as prompt
+func runChain(completer provider.Completer) mcp.PromptHandler {
+	return func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		input := req.Params.Arguments["input"]
+
+		if input == "" {
+			return nil, errors.New("missing input")
+		}
+
+		completion, err := completer.Complete(ctx, []provider.Message{
+			{
+				Role:    provider.MessageRoleUser,
+				Content: input,
+			},
+		}, nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &mcp.GetPromptResult{
+			Messages: []*mcp.PromptMessage{
+				{
+					Role:    "user",
+					Content: &mcp.TextContent{Text: input},
+				},
+				{
+					Role:    "assistant",
+					Content: &mcp.TextContent{Text: completion.Message.Content},
+				},
+			},
+		}, nil
+	}
+}
@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type SearchRequest struct {
+	Query string `json:"query"`
+	Limit *int   `json:"limit,omitempty"`
+}
+
+type SearchResult struct {
+	Title  string `json:"title,omitempty"`
+	Source string `json:"source,omitempty"`
+
+	Content string `json:"content"`
+
+	Score float32 `json:"score,omitempty"`
+}
+
+func (h *Handler) addIndexes(s *mcp.Server) {
+	for _, id := range h.Indexes() {
+		i, err := h.Index(id)
+
+		if err != nil {
+			continue
+		}
+
+		s.AddTool(&mcp.Tool{
+			Name:        "search_" + id,
+			Description: "Search the " + id + " index for documents relevant to a query",
+
+			InputSchema: map[string]any{
+				"type": "object",
+
+				"properties": map[string]any{
+					"query": map[string]any{
+						"type":        "string",
+						"description": "the search query",
+					},
+
+					"limit": map[string]any{
+						"type":        "integer",
+						"description": "the maximum number of results",
+					},
+				},
+
+				"required": []string{"query"},
+			},
+		}, searchIndex(i))
+	}
+}
+
+func searchIndex(i index.Provider) mcp.ToolHandler {
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var search SearchRequest
+
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &search); err != nil {
+				return errorResult(err), nil
+			}
+		}
+
+		if search.Query == "" {
+			return errorResult(errors.New("missing query")), nil
+		}
+
+		results, err := i.Query(ctx, search.Query, &index.QueryOptions{
+			Limit: search.Limit,
+		})
+
+		if err != nil {
+			return errorResult(err), nil
+		}
+
+		result := []SearchResult{}
+
+		for _, r := range results {
+			result = append(result, SearchResult{
+				Title:  r.Title,
+				Source: r.Source,
+
+				Content: r.Content,
+
+				Score: r.Score,
+			})
+		}
+
+		return textResult(result)
+	}
+}
@@ -0,0 +1,152 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/adrianliechti/wingman/config"
+	"github.com/adrianliechti/wingman/pkg/tool"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTools(t *testing.T) {
+	echo := &testTools{}
+
+	cfg := &config.Config{}
+	cfg.RegisterTool("echo", echo)
+	cfg.RegisterTool("other", &testTools{})
+	cfg.RegisterTool("down", &testTools{err: errors.New("connection refused")})
+
+	h, err := New(cfg)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	ctx := context.Background()
+
+	for range 2 {
+		session := connect(t, server.URL+"/mcp")
+
+		list, err := session.ListTools(ctx, nil)
+		require.NoError(t, err)
+
+		var names []string
+
+		for _, t := range list.Tools {
+			names = append(names, t.Name)
+		}
+
+		require.ElementsMatch(t, []string{"echo_echo", "other_echo"}, names)
+
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{
+			Name:      "echo_echo",
+			Arguments: map[string]any{"text": "hello"},
+		})
+
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+		require.Len(t, result.Content, 1)
+		require.Equal(t, `{"text":"hello"}`, result.Content[0].(*mcp.TextContent).Text)
+
+		session.Close()
+	}
+
+	// the tool list is reused by the second session
+	require.EqualValues(t, 1, echo.listed.Load())
+	require.EqualValues(t, 2, echo.executed.Load())
+}
+
+func TestToolsSlowProvider(t *testing.T) {
+	h, err := New(&config.Config{})
+	require.NoError(t, err)
+
+	slow := &testTools{block: make(chan struct{})}
+	defer close(slow.block)
+
+	go h.providerTools(context.Background(), "slow", slow)
+
+	require.Eventually(t, func() bool {
+		return slow.listed.Load() == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// a provider still listing its tools does not hold up the others
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		h.providerTools(context.Background(), "echo", &testTools{})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("tools blocked by another provider")
+	}
+}
+
+func connect(t *testing.T, url string) *mcp.ClientSession {
+	client := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+
+	session, err := client.Connect(context.Background(), &mcp.StreamableClientTransport{
+		Endpoint: url,
+
+		DisableStandaloneSSE: true,
+	}, nil)
+
+	require.NoError(t, err)
+	return session
+}
+
+type testTools struct {
+	err   error
+	block chan struct{}
+
+	listed   atomic.Int32
+	executed atomic.Int32
+}
+
+func (p *testTools) Tools(ctx context.Context) ([]tool.Tool, error) {
+	p.listed.Add(1)
+
+	if p.block != nil {
+		<-p.block
+	}
+
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	return []tool.Tool{
+		{
+			Name:        "echo",
+			Description: "returns its parameters",
+
+			Parameters: map[string]any{
+				"type": "object",
+
+				"properties": map[string]any{
+					"text": map[string]any{"type": "string"},
+				},
+			},
+		},
+	}, nil
+}
+
+func (p *testTools) Execute(ctx context.Context, name string, parameters map[string]any) (any, error) {
+	p.executed.Add(1)
+
+	if name != "echo" {
+		return nil, tool.ErrInvalidTool
+	}
+
+	data, _ := json.Marshal(parameters)
+	return string(data), nil
+}
@@ -0,0 +1,142 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/tool"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// toolsCacheDuration is how long the tools of a provider are reused for new sessions
+const toolsCacheDuration = time.Minute
+
+// cachedTools holds the tool list of a provider, its lock only blocks sessions waiting for the same provider
+type cachedTools struct {
+	mu sync.Mutex
+
+	tools   []tool.Tool
+	updated time.Time
+}
+
+func (h *Handler) addTools(ctx context.Context, s *mcp.Server) {
+	names := map[string]bool{}
+
+	for _, id := range h.Tools() {
+		p, err := h.Tool(id)
+
+		if err != nil {
+			continue
+		}
+
+		// unreachable tool providers should not take the whole server down
+		tools, err := h.providerTools(ctx, id, p)
+
+		if err != nil {
+			slog.WarnContext(ctx, "skipping mcp tools of unavailable provider", "tool", id, "error", err)
+			continue
+		}
+
+		for _, t := range tools {
+			name := id + "_" + t.Name
+
+			if names[name] {
+				continue
+			}
+
+			names[name] = true
+
+			s.AddTool(&mcp.Tool{
+				Name:        name,
+				Description: t.Description,
+
+				InputSchema: toolSchema(t.Parameters),
+			}, executeTool(p, t.Name))
+		}
+	}
+}
+
+// providerTools lists the tools of a provider, reusing the last list for a while,
+// so a session does not query every provider again
+func (h *Handler) providerTools(ctx context.Context, id string, p tool.Provider) ([]tool.Tool, error) {
+	h.toolsMu.Lock()
+
+	if h.tools == nil {
+		h.tools = map[string]*cachedTools{}
+	}
+
+	c, ok := h.tools[id]
+
+	if !ok {
+		c = &cachedTools{}
+		h.tools[id] = c
+	}
+
+	h.toolsMu.Unlock()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.updated.IsZero() && time.Since(c.updated) < toolsCacheDuration {
+		return c.tools, nil
+	}
+
+	tools, err := p.Tools(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	c.tools = tools
+	c.updated = time.Now()
+
+	return tools, nil
+}
+
+func executeTool(p tool.Provider, name string) mcp.ToolHandler {
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var params map[string]any
+
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &params); err != nil {
+				return errorResult(err), nil
+			}
+		}
+
+		if params == nil {
+			params = map[string]any{}
+		}
+
+		result, err := p.Execute(ctx, name, params)
+
+		if err != nil {
+			return errorResult(err), nil
+		}
+
+		return textResult(result)
+	}
+}
+
+// toolSchema ensures an object schema, as required for mcp tool inputs
+func toolSchema(parameters map[string]any) map[string]any {
+	schema := maps.Clone(parameters)
+
+	if schema == nil {
+		schema = map[string]any{}
+	}
+
+	if _, ok := schema["type"]; !ok {
+		schema["type"] = "object"
+	}
+
+	if _, ok := schema["properties"]; !ok {
+		schema["properties"] = map[string]any{}
+	}
+
+	return schema
+}
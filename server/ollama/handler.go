@@ -0,0 +1,79 @@
+package ollama
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/adrianliechti/wingman/config"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	*config.Config
+	http.Handler
+}
+
+func New(cfg *config.Config) (*Handler, error) {
+	mux := chi.NewMux()
+
+	h := &Handler{
+		Config:  cfg,
+		Handler: mux,
+	}
+
+	h.Attach(mux)
+	return h, nil
+}
+
+func (h *Handler) Attach(r chi.Router) {
+	r.Get("/version", h.handleVersion)
+
+	r.Get("/tags", h.handleTags)
+	r.Post("/show", h.handleShow)
+
+	r.Post("/chat", h.handleChat)
+	r.Post("/generate", h.handleGenerate)
+
+	r.Post("/embed", h.handleEmbed)
+	r.Post("/embeddings", h.handleEmbeddings)
+}
+
+func (h *Handler) handleVersion(w http.ResponseWriter, r *http.Request) {
+	writeJson(w, Version{
+		Version: "0.6.0",
+	})
+}
+
+// modelID strips the default tag ollama clients append to model names, unless a model is configured with it
+func (h *Handler) modelID(name string) string {
+	if _, err := h.Model(name); err == nil {
+		return name
+	}
+
+	return strings.TrimSuffix(name, ":latest")
+}
+
+func writeJson(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	resp := ErrorResponse{
+		Error: err.Error(),
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	enc.Encode(resp)
+}
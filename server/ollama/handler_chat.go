@@ -0,0 +1,332 @@
+package ollama
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+func (h *Handler) handleChat(w http.ResponseWriter, r *http.Request) {
+	var req ChatRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	completer, err := h.Completer(h.modelID(req.Model))
+
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	messages, err := toMessages(req.Messages)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	options, err := toCompleteOptions(req.Options, req.Format)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	options.Tools = toTools(req.Tools)
+
+	start := time.Now()
+
+	if req.Stream == nil || *req.Stream {
+		stream, ctx := newChunkStream(w, r)
+		defer stream.Close()
+
+		options.Stream = func(ctx context.Context, completion provider.Completion) error {
+			if completion.Message.Content == "" && completion.Message.Reasoning == "" {
+				return nil
+			}
+
+			return stream.Send(ChatResponse{
+				Model:     req.Model,
+				CreatedAt: time.Now().UTC(),
+
+				Message: Message{
+					Role:    MessageRoleAssistant,
+					Content: completion.Message.Content,
+
+					Thinking: completion.Message.Reasoning,
+				},
+			})
+		}
+
+		completion, err := completer.Complete(ctx, messages, options)
+
+		if err != nil {
+			stream.Send(ErrorResponse{
+				Error: err.Error(),
+			})
+
+			return
+		}
+
+		if len(completion.Message.ToolCalls) > 0 {
+			if err := stream.Send(ChatResponse{
+				Model:     req.Model,
+				CreatedAt: time.Now().UTC(),
+
+				Message: Message{
+					Role:      MessageRoleAssistant,
+					ToolCalls: toToolCalls(completion.Message.ToolCalls),
+				},
+			}); err != nil {
+				return
+			}
+		}
+
+		stream.Send(ChatResponse{
+			Model:     req.Model,
+			CreatedAt: time.Now().UTC(),
+
+			Message: Message{
+				Role: MessageRoleAssistant,
+			},
+
+			Done:       true,
+			DoneReason: toDoneReason(completion.Reason),
+
+			Metrics: toMetrics(start, completion.Usage),
+		})
+	} else {
+		completion, err := completer.Complete(r.Context(), messages, options)
+
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		writeJson(w, ChatResponse{
+			Model:     req.Model,
+			CreatedAt: time.Now().UTC(),
+
+			Message: Message{
+				Role:    MessageRoleAssistant,
+				Content: completion.Message.Content,
+
+				Thinking: completion.Message.Reasoning,
+
+				ToolCalls: toToolCalls(completion.Message.ToolCalls),
+			},
+
+			Done:       true,
+			DoneReason: toDoneReason(completion.Reason),
+
+			Metrics: toMetrics(start, completion.Usage),
+		})
+	}
+}
+
+func toMessages(s []Message) ([]provider.Message, error) {
+	var result []provider.Message
+
+	// ollama has no tool call ids, so tool results are matched to the pending calls by name or order
+	var pending []provider.ToolCall
+
+	for i, m := range s {
+		message := provider.Message{
+			Content: m.Content,
+
+			Reasoning: m.Thinking,
+		}
+
+		switch m.Role {
+		case MessageRoleSystem:
+			message.Role = provider.MessageRoleSystem
+
+		case MessageRoleUser:
+			message.Role = provider.MessageRoleUser
+
+		case MessageRoleAssistant:
+			message.Role = provider.MessageRoleAssistant
+
+			pending = nil
+
+			for j, c := range m.ToolCalls {
+				arguments := string(c.Function.Arguments)
+
+				if arguments == "" || arguments == "null" {
+					arguments = "{}"
+				}
+
+				call := provider.ToolCall{
+					ID: fmt.Sprintf("call_%d_%d", i, j),
+
+					Name:      c.Function.Name,
+					Arguments: arguments,
+				}
+
+				message.ToolCalls = append(message.ToolCalls, call)
+				pending = append(pending, call)
+			}
+
+		case MessageRoleTool:
+			message.Role = provider.MessageRoleTool
+
+			for j, c := range pending {
+				if m.ToolName == "" || m.ToolName == c.Name {
+					message.Tool = c.ID
+					pending = append(pending[:j], pending[j+1:]...)
+					break
+				}
+			}
+
+		default:
+			return nil, errors.New("invalid message role: " + string(m.Role))
+		}
+
+		files, err := toFiles(m.Images)
+
+		if err != nil {
+			return nil, err
+		}
+
+		message.Files = files
+
+		result = append(result, message)
+	}
+
+	return result, nil
+}
+
+func toFiles(images []string) ([]provider.File, error) {
+	var result []provider.File
+
+	for _, image := range images {
+		data, err := base64.StdEncoding.DecodeString(image)
+
+		if err != nil {
+			return nil, errors.New("invalid image encoding")
+		}
+
+		result = append(result, provider.File{
+			Content:     bytes.NewReader(data),
+			ContentType: http.DetectContentType(data),
+		})
+	}
+
+	return result, nil
+}
+
+func toTools(tools []Tool) []provider.Tool {
+	var result []provider.Tool
+
+	for _, t := range tools {
+		result = append(result, provider.Tool{
+			Name:        t.Function.Name,
+			Description: t.Function.Description,
+
+			Parameters: t.Function.Parameters,
+		})
+	}
+
+	return result
+}
+
+func toCompleteOptions(o *Options, format json.RawMessage) (*provider.CompleteOptions, error) {
+	options := &provider.CompleteOptions{}
+
+	if o != nil {
+		options.Stop = o.Stop
+
+		options.MaxTokens = o.NumPredict
+		options.Temperature = o.Temperature
+		options.TopP = o.TopP
+
+		options.PresencePenalty = o.PresencePenalty
+		options.FrequencyPenalty = o.FrequencyPenalty
+
+		options.Seed = o.Seed
+	}
+
+	if len(format) == 0 || string(format) == "null" || string(format) == `""` {
+		return options, nil
+	}
+
+	var name string
+
+	if err := json.Unmarshal(format, &name); err == nil {
+		if name != "json" {
+			return nil, errors.New("invalid format: " + name)
+		}
+
+		options.Format = provider.CompletionFormatJSON
+		return options, nil
+	}
+
+	var schema map[string]any
+
+	if err := json.Unmarshal(format, &schema); err != nil {
+		return nil, errors.New("invalid format")
+	}
+
+	options.Format = provider.CompletionFormatJSON
+
+	options.Schema = &provider.Schema{
+		Name:   "response",
+		Schema: schema,
+	}
+
+	return options, nil
+}
+
+func toToolCalls(calls []provider.ToolCall) []ToolCall {
+	var result []ToolCall
+
+	for _, c := range calls {
+		arguments := json.RawMessage(c.Arguments)
+
+		if !json.Valid(arguments) {
+			arguments = json.RawMessage("{}")
+		}
+
+		result = append(result, ToolCall{
+			Function: ToolCallFunction{
+				Name:      c.Name,
+				Arguments: arguments,
+			},
+		})
+	}
+
+	return result
+}
+
+func toDoneReason(reason provider.CompletionReason) string {
+	switch reason {
+	case provider.CompletionReasonLength:
+		return "length"
+
+	default:
+		return "stop"
+	}
+}
+
+func toMetrics(start time.Time, usage *provider.Usage) Metrics {
+	metrics := Metrics{
+		TotalDuration: time.Since(start),
+	}
+
+	if usage != nil {
+		metrics.PromptEvalCount = usage.InputTokens
+		metrics.EvalCount = usage.OutputTokens
+	}
+
+	return metrics
+}
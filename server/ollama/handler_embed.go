@@ -0,0 +1,98 @@
+package ollama
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
+func (h *Handler) handleEmbed(w http.ResponseWriter, r *http.Request) {
+	var req EmbedRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	embedder, err := h.Embedder(h.modelID(req.Model))
+
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	var inputs []string
+
+	switch v := req.Input.(type) {
+	case string:
+		inputs = []string{v}
+
+	case []any:
+		for _, i := range v {
+			text, ok := i.(string)
+
+			if !ok {
+				writeError(w, http.StatusBadRequest, errors.New("invalid input"))
+				return
+			}
+
+			inputs = append(inputs, text)
+		}
+	}
+
+	if len(inputs) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("no input provided"))
+		return
+	}
+
+	start := time.Now()
+
+	embedding, err := embedder.Embed(r.Context(), inputs)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	result := EmbedResponse{
+		Model: req.Model,
+
+		Embeddings: embedding.Embeddings,
+
+		TotalDuration: time.Since(start),
+	}
+
+	if embedding.Usage != nil {
+		result.PromptEvalCount = embedding.Usage.InputTokens
+	}
+
+	writeJson(w, result)
+}
+
+func (h *Handler) handleEmbeddings(w http.ResponseWriter, r *http.Request) {
+	var req EmbeddingsRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	embedder, err := h.Embedder(h.modelID(req.Model))
+
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	embedding, err := embedder.Embed(r.Context(), []string{req.Prompt})
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJson(w, EmbeddingsResponse{
+		Embedding: embedding.Embeddings[0],
+	})
+}
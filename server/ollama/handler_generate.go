@@ -0,0 +1,128 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+func (h *Handler) handleGenerate(w http.ResponseWriter, r *http.Request) {
+	var req GenerateRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	completer, err := h.Completer(h.modelID(req.Model))
+
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	// an empty prompt loads the model in ollama, there is nothing to load here
+	if req.Prompt == "" && len(req.Images) == 0 {
+		writeJson(w, GenerateResponse{
+			Model:     req.Model,
+			CreatedAt: time.Now().UTC(),
+
+			Done:       true,
+			DoneReason: "load",
+		})
+
+		return
+	}
+
+	var messages []provider.Message
+
+	if req.System != "" {
+		messages = append(messages, provider.Message{
+			Role:    provider.MessageRoleSystem,
+			Content: req.System,
+		})
+	}
+
+	files, err := toFiles(req.Images)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	messages = append(messages, provider.Message{
+		Role:    provider.MessageRoleUser,
+		Content: req.Prompt,
+
+		Files: files,
+	})
+
+	options, err := toCompleteOptions(req.Options, req.Format)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	start := time.Now()
+
+	if req.Stream == nil || *req.Stream {
+		stream, ctx := newChunkStream(w, r)
+		defer stream.Close()
+
+		options.Stream = func(ctx context.Context, completion provider.Completion) error {
+			if completion.Message.Content == "" {
+				return nil
+			}
+
+			return stream.Send(GenerateResponse{
+				Model:     req.Model,
+				CreatedAt: time.Now().UTC(),
+
+				Response: completion.Message.Content,
+			})
+		}
+
+		completion, err := completer.Complete(ctx, messages, options)
+
+		if err != nil {
+			stream.Send(ErrorResponse{
+				Error: err.Error(),
+			})
+
+			return
+		}
+
+		stream.Send(GenerateResponse{
+			Model:     req.Model,
+			CreatedAt: time.Now().UTC(),
+
+			Done:       true,
+			DoneReason: toDoneReason(completion.Reason),
+
+			Metrics: toMetrics(start, completion.Usage),
+		})
+	} else {
+		completion, err := completer.Complete(r.Context(), messages, options)
+
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		writeJson(w, GenerateResponse{
+			Model:     req.Model,
+			CreatedAt: time.Now().UTC(),
+
+			Response: completion.Message.Content,
+
+			Done:       true,
+			DoneReason: toDoneReason(completion.Reason),
+
+			Metrics: toMetrics(start, completion.Usage),
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package ollama
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+func (h *Handler) handleTags(w http.ResponseWriter, r *http.Request) {
+	result := ModelList{
+		Models: []Model{},
+	}
+
+	for _, m := range h.Models() {
+		// only models served by the chat and embedding endpoints are of use to ollama clients
+		if len(h.capabilities(m.ID)) == 0 {
+			continue
+		}
+
+		result.Models = append(result.Models, Model{
+			Name:  m.ID,
+			Model: m.ID,
+
+			ModifiedAt: time.Now().UTC(),
+
+			Digest: digest(m.ID),
+
+			Details: ModelDetails{
+				Format: "gguf",
+
+				Families: []string{},
+			},
+		})
+	}
+
+	writeJson(w, result)
+}
+
+func (h *Handler) handleShow(w http.ResponseWriter, r *http.Request) {
+	var req ShowRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	id := req.Model
+
+	if id == "" {
+		id = req.Name
+	}
+
+	model, err := h.Model(h.modelID(id))
+
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	writeJson(w, ShowResponse{
+		Details: ModelDetails{
+			Format: "gguf",
+
+			Families: []string{},
+		},
+
+		ModelInfo: map[string]any{
+			"general.basename": model.ID,
+		},
+
+		Capabilities: h.capabilities(model.ID),
+
+		ModifiedAt: time.Now().UTC(),
+	})
+}
+
+func (h *Handler) capabilities(id string) []string {
+	result := []string{}
+
+	if _, err := h.Completer(id); err == nil {
+		result = append(result, "completion")
+
+		if m, err := h.Model(id); err == nil {
+			if m.HasCapability(provider.CapabilityTools) {
+				result = append(result, "tools")
+			}
+
+			if m.HasCapability(provider.CapabilityVision) {
+				result = append(result, "vision")
+			}
+		}
+	}
+
+	if _, err := h.Embedder(id); err == nil {
+		result = append(result, "embedding")
+	}
+
+	return result
+}
+
+func digest(id string) string {
+	hash := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,193 @@
+package ollama_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adrianliechti/wingman/config"
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/server/ollama"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChat(t *testing.T) {
+	h := newHandler(t, &staticCompleter{})
+
+	resp := post(t, h, "/chat", `{"model": "test:latest", "stream": false, "messages": [{"role": "user", "content": "hi"}]}`)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var result ollama.ChatResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+
+	require.Equal(t, "test:latest", result.Model)
+	require.Equal(t, "Hello", result.Message.Content)
+	require.True(t, result.Done)
+}
+
+func TestChatStream(t *testing.T) {
+	h := newHandler(t, &staticCompleter{})
+
+	resp := post(t, h, "/chat", `{"model": "test", "messages": [{"role": "user", "content": "hi"}]}`)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, "application/x-ndjson", resp.Header().Get("Content-Type"))
+
+	var content strings.Builder
+	var last ollama.ChatResponse
+
+	scanner := bufio.NewScanner(resp.Body)
+
+	for scanner.Scan() {
+		last = ollama.ChatResponse{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &last))
+
+		content.WriteString(last.Message.Content)
+	}
+
+	require.Equal(t, "Hello", content.String())
+	require.True(t, last.Done)
+	require.Equal(t, "stop", last.DoneReason)
+}
+
+func TestGenerateStreamCancel(t *testing.T) {
+	c := &blockingCompleter{done: make(chan error, 1)}
+	h := newHandler(t, c)
+
+	req := httptest.NewRequest(http.MethodPost, "/generate", strings.NewReader(`{"model": "test:latest", "prompt": "hi"}`))
+	h.ServeHTTP(&brokenWriter{header: http.Header{}}, req)
+
+	select {
+	case err := <-c.done:
+		require.ErrorIs(t, err, context.Canceled)
+
+	case <-time.After(5 * time.Second):
+		t.Fatal("upstream request not cancelled")
+	}
+}
+
+func TestEmbed(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.RegisterEmbedder("embed", &staticEmbedder{})
+
+	h, err := ollama.New(cfg)
+	require.NoError(t, err)
+
+	resp := post(t, h, "/embed", `{"model": "embed:latest", "input": ["a", "b"]}`)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var result ollama.EmbedResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+
+	require.Len(t, result.Embeddings, 2)
+
+	resp = post(t, h, "/embeddings", `{"model": "embed:latest", "prompt": "a"}`)
+	require.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestShow(t *testing.T) {
+	h := newHandler(t, &staticCompleter{})
+
+	resp := post(t, h, "/show", `{"model": "test:latest"}`)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var result ollama.ShowResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+
+	require.Contains(t, result.Capabilities, "completion")
+
+	resp = post(t, h, "/show", `{"model": "other:latest"}`)
+	require.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func newHandler(t *testing.T, c provider.Completer) *ollama.Handler {
+	cfg := &config.Config{}
+	cfg.RegisterCompleter("test", c)
+
+	h, err := ollama.New(cfg)
+	require.NoError(t, err)
+
+	return h
+}
+
+func post(t *testing.T, h http.Handler, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+
+	return resp
+}
+
+type staticCompleter struct{}
+
+func (c *staticCompleter) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	if options.Stream != nil {
+		for _, chunk := range []string{"Hel", "lo"} {
+			if err := options.Stream(ctx, provider.Completion{Message: provider.Message{Role: provider.MessageRoleAssistant, Content: chunk}}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &provider.Completion{
+		Reason: provider.CompletionReasonStop,
+
+		Message: provider.Message{
+			Role:    provider.MessageRoleAssistant,
+			Content: "Hello",
+		},
+	}, nil
+}
+
+// blockingCompleter ignores stream errors and waits for its context, like providers reading ahead of the client
+type blockingCompleter struct {
+	done chan error
+}
+
+func (c *blockingCompleter) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	options.Stream(ctx, provider.Completion{Message: provider.Message{Role: provider.MessageRoleAssistant, Content: "Hello"}})
+
+	select {
+	case <-ctx.Done():
+		c.done <- ctx.Err()
+
+	case <-time.After(5 * time.Second):
+		c.done <- nil
+	}
+
+	return nil, ctx.Err()
+}
+
+type staticEmbedder struct{}
+
+func (e *staticEmbedder) Embed(ctx context.Context, texts []string) (*provider.Embedding, error) {
+	result := &provider.Embedding{}
+
+	for range texts {
+		result.Embeddings = append(result.Embeddings, []float32{1, 0})
+	}
+
+	return result, nil
+}
+
+// brokenWriter fails every write, like a connection closed by the client
+type brokenWriter struct {
+	header http.Header
+}
+
+func (w *brokenWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *brokenWriter) Write([]byte) (int, error) {
+	return 0, errors.New("connection closed")
+}
+
+func (w *brokenWriter) WriteHeader(int) {}
@@ -0,0 +1,217 @@
+package ollama
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// https://github.com/ollama/ollama/blob/main/docs/api.md#version
+type Version struct {
+	Version string `json:"version"`
+}
+
+// https://github.com/ollama/ollama/blob/main/docs/api.md#list-local-models
+type ModelList struct {
+	Models []Model `json:"models"`
+}
+
+type Model struct {
+	Name  string `json:"name"`
+	Model string `json:"model"`
+
+	ModifiedAt time.Time `json:"modified_at"`
+
+	Size   int64  `json:"size"`
+	Digest string `json:"digest"`
+
+	Details ModelDetails `json:"details"`
+}
+
+type ModelDetails struct {
+	Format string `json:"format"`
+	Family string `json:"family"`
+
+	Families []string `json:"families"`
+
+	ParameterSize     string `json:"parameter_size"`
+	QuantizationLevel string `json:"quantization_level"`
+}
+
+// https://github.com/ollama/ollama/blob/main/docs/api.md#show-model-information
+type ShowRequest struct {
+	Model string `json:"model"`
+	Name  string `json:"name"` // deprecated
+}
+
+type ShowResponse struct {
+	Modelfile  string `json:"modelfile"`
+	Parameters string `json:"parameters"`
+	Template   string `json:"template"`
+
+	Details   ModelDetails   `json:"details"`
+	ModelInfo map[string]any `json:"model_info"`
+
+	Capabilities []string `json:"capabilities"`
+
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+// https://github.com/ollama/ollama/blob/main/docs/api.md#generate-a-chat-completion
+type ChatRequest struct {
+	Model string `json:"model"`
+
+	Messages []Message `json:"messages"`
+	Tools    []Tool    `json:"tools,omitempty"`
+
+	Format json.RawMessage `json:"format,omitempty"`
+
+	Stream  *bool    `json:"stream,omitempty"`
+	Options *Options `json:"options,omitempty"`
+
+	// keep_alive string
+	// think *bool
+}
+
+type MessageRole string
+
+var (
+	MessageRoleSystem    MessageRole = "system"
+	MessageRoleUser      MessageRole = "user"
+	MessageRoleAssistant MessageRole = "assistant"
+	MessageRoleTool      MessageRole = "tool"
+)
+
+type Message struct {
+	Role    MessageRole `json:"role"`
+	Content string      `json:"content"`
+
+	Thinking string `json:"thinking,omitempty"`
+
+	Images []string `json:"images,omitempty"`
+
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	ToolName  string     `json:"tool_name,omitempty"`
+}
+
+type Tool struct {
+	Type string `json:"type"`
+
+	Function ToolFunction `json:"function"`
+}
+
+type ToolFunction struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	Parameters map[string]any `json:"parameters,omitempty"`
+}
+
+type ToolCall struct {
+	Function ToolCallFunction `json:"function"`
+}
+
+type ToolCallFunction struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+// https://github.com/ollama/ollama/blob/main/docs/modelfile.md#valid-parameters-and-values
+type Options struct {
+	Temperature *float32 `json:"temperature,omitempty"`
+	NumPredict  *int     `json:"num_predict,omitempty"`
+
+	Stop []string `json:"stop,omitempty"`
+
+	TopP *float32 `json:"top_p,omitempty"`
+	Seed *int     `json:"seed,omitempty"`
+
+	PresencePenalty  *float32 `json:"presence_penalty,omitempty"`
+	FrequencyPenalty *float32 `json:"frequency_penalty,omitempty"`
+
+	// top_k *int
+	// num_ctx *int
+}
+
+type ChatResponse struct {
+	Model     string    `json:"model"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Message Message `json:"message"`
+
+	Done       bool   `json:"done"`
+	DoneReason string `json:"done_reason,omitempty"`
+
+	Metrics
+}
+
+// https://github.com/ollama/ollama/blob/main/docs/api.md#generate-a-completion
+type GenerateRequest struct {
+	Model string `json:"model"`
+
+	Prompt string `json:"prompt"`
+	System string `json:"system,omitempty"`
+
+	Images []string `json:"images,omitempty"`
+
+	Format json.RawMessage `json:"format,omitempty"`
+
+	Stream  *bool    `json:"stream,omitempty"`
+	Options *Options `json:"options,omitempty"`
+
+	// suffix string
+	// template string
+	// raw bool
+	// context []int
+}
+
+type GenerateResponse struct {
+	Model     string    `json:"model"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Response string `json:"response"`
+
+	Done       bool   `json:"done"`
+	DoneReason string `json:"done_reason,omitempty"`
+
+	Metrics
+}
+
+type Metrics struct {
+	TotalDuration time.Duration `json:"total_duration,omitempty"`
+
+	PromptEvalCount int `json:"prompt_eval_count,omitempty"`
+	EvalCount       int `json:"eval_count,omitempty"`
+}
+
+// https://github.com/ollama/ollama/blob/main/docs/api.md#generate-embeddings
+type EmbedRequest struct {
+	Model string `json:"model"`
+
+	Input any `json:"input"`
+
+	// truncate *bool
+	// options *Options
+}
+
+type EmbedResponse struct {
+	Model string `json:"model"`
+
+	Embeddings [][]float32 `json:"embeddings"`
+
+	TotalDuration   time.Duration `json:"total_duration,omitempty"`
+	PromptEvalCount int           `json:"prompt_eval_count,omitempty"`
+}
+
+// https://github.com/ollama/ollama/blob/main/docs/api.md#generate-embedding
+type EmbeddingsRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+}
+
+type EmbeddingsResponse struct {
+	Embedding []float32 `json:"embedding"`
+}
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
@@ -0,0 +1,62 @@
+package ollama
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// chunkStream writes a streamed NDJSON response.
+// Once a write fails the client is gone, so the upstream request is cancelled.
+type chunkStream struct {
+	w  http.ResponseWriter
+	rc *http.ResponseController
+
+	cancel context.CancelFunc
+}
+
+// newChunkStream returns a stream and the context to run the upstream request with
+func newChunkStream(w http.ResponseWriter, r *http.Request) (*chunkStream, context.Context) {
+	ctx, cancel := context.WithCancel(r.Context())
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+
+	return &chunkStream{
+		w:  w,
+		rc: http.NewResponseController(w),
+
+		cancel: cancel,
+	}, ctx
+}
+
+// Send writes one line of the response
+func (s *chunkStream) Send(v any) error {
+	var data bytes.Buffer
+
+	enc := json.NewEncoder(&data)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(s.w, "%s\n", strings.TrimSpace(data.String())); err != nil {
+		s.cancel()
+		return err
+	}
+
+	if err := s.rc.Flush(); err != nil {
+		s.cancel()
+		return err
+	}
+
+	return nil
+}
+
+// Close cancels the upstream request if it is still running
+func (s *chunkStream) Close() {
+	s.cancel()
+}
@@ -0,0 +1,781 @@
+package openai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var errBatchNotFound = errors.New("batch not found")
+
+var batchEndpoints = []string{
+	"/v1/chat/completions",
+	"/v1/embeddings",
+	"/v1/moderations",
+	"/v1/responses",
+}
+
+// batchStore persists batches on disk and runs them in the background against the handler itself,
+// so every request goes through the same completers and limiters as a regular api call.
+// Finished requests are appended to the output and error files of the batch as they complete,
+// which allows resuming interrupted batches after a restart without repeating them.
+// The result files are imported under ids derived from the batch, so an interrupted finalization finds them again.
+type batchStore struct {
+	dir string
+
+	files   *fileStore
+	handler http.Handler
+
+	concurrency int
+
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+func newBatchStore(dir string, files *fileStore, handler http.Handler) *batchStore {
+	return &batchStore{
+		dir: dir,
+
+		files:   files,
+		handler: handler,
+
+		concurrency: 4,
+
+		cancels: make(map[string]context.CancelFunc),
+	}
+}
+
+func (s *batchStore) Create(req BatchRequest) (*Batch, error) {
+	if !slices.Contains(batchEndpoints, req.Endpoint) {
+		return nil, errors.New("unsupported endpoint: " + req.Endpoint)
+	}
+
+	if req.CompletionWindow != "24h" {
+		return nil, errors.New("unsupported completion window: " + req.CompletionWindow)
+	}
+
+	file, err := s.files.Get(req.InputFileID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if file.Purpose != FilePurposeBatch {
+		return nil, errors.New("input file must have purpose batch")
+	}
+
+	now := time.Now()
+
+	batch := Batch{
+		Object: "batch",
+
+		ID: "batch_" + newResponseID(),
+
+		Endpoint:         req.Endpoint,
+		CompletionWindow: req.CompletionWindow,
+
+		Status: BatchStatusValidating,
+
+		InputFileID: req.InputFileID,
+
+		CreatedAt: now.Unix(),
+		ExpiresAt: now.Add(24 * time.Hour).Unix(),
+
+		Metadata: req.Metadata,
+	}
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	err = s.save(batch)
+	s.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.start(batch.ID)
+
+	return &batch, nil
+}
+
+func (s *batchStore) Get(id string) (*Batch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.load(id)
+}
+
+func (s *batchStore) List() ([]Batch, error) {
+	entries, err := os.ReadDir(s.dir)
+
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	result := []Batch{}
+
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+
+		if !ok {
+			continue
+		}
+
+		batch, err := s.Get(id)
+
+		if err != nil {
+			continue
+		}
+
+		result = append(result, *batch)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreatedAt > result[j].CreatedAt
+	})
+
+	return result, nil
+}
+
+func (s *batchStore) Cancel(id string) (*Batch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	batch, err := s.load(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if batch.Status != BatchStatusValidating && batch.Status != BatchStatusInProgress {
+		return batch, nil
+	}
+
+	batch.Status = BatchStatusCancelling
+	batch.CancellingAt = time.Now().Unix()
+
+	if err := s.save(*batch); err != nil {
+		return nil, err
+	}
+
+	if cancel, ok := s.cancels[id]; ok {
+		cancel()
+	}
+
+	return batch, nil
+}
+
+// Resume restarts the batches interrupted by a shutdown
+func (s *batchStore) Resume() error {
+	batches, err := s.List()
+
+	if err != nil {
+		return err
+	}
+
+	for _, b := range batches {
+		switch b.Status {
+		case BatchStatusValidating, BatchStatusInProgress, BatchStatusFinalizing, BatchStatusCancelling:
+			s.start(b.ID)
+		}
+	}
+
+	return nil
+}
+
+func (s *batchStore) start(id string) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s.mu.Lock()
+	s.cancels[id] = cancel
+	s.mu.Unlock()
+
+	go func() {
+		defer func() {
+			s.mu.Lock()
+			delete(s.cancels, id)
+			s.mu.Unlock()
+
+			cancel()
+		}()
+
+		if err := s.run(ctx, id); err != nil {
+			s.update(id, func(b *Batch) {
+				b.Status = BatchStatusFailed
+				b.FailedAt = time.Now().Unix()
+
+				b.Errors = &BatchErrors{
+					Object: "list",
+					Data: []BatchError{
+						{Code: "server_error", Message: err.Error()},
+					},
+				}
+			})
+		}
+	}()
+}
+
+func (s *batchStore) run(ctx context.Context, id string) error {
+	batch, err := s.Get(id)
+
+	if err != nil {
+		return err
+	}
+
+	inputs, errs, err := s.readInputs(batch)
+
+	if err != nil {
+		return err
+	}
+
+	if len(errs) > 0 {
+		return s.update(id, func(b *Batch) {
+			b.Status = BatchStatusFailed
+			b.FailedAt = time.Now().Unix()
+
+			b.Errors = &BatchErrors{
+				Object: "list",
+				Data:   errs,
+			}
+		})
+	}
+
+	// a cancel arriving while the inputs were read has already changed the stored status
+	if err := s.update(id, func(b *Batch) {
+		if b.Status != BatchStatusValidating {
+			return
+		}
+
+		b.Status = BatchStatusInProgress
+		b.InProgressAt = time.Now().Unix()
+
+		b.RequestCounts.Total = len(inputs)
+	}); err != nil {
+		return err
+	}
+
+	if batch, err = s.Get(id); err != nil {
+		return err
+	}
+
+	w, err := s.openWriter(id)
+
+	if err != nil {
+		return err
+	}
+
+	defer w.Close()
+
+	// a batch cancelled right before a shutdown only needs to be finalized
+	if batch.Status == BatchStatusCancelling || batch.Status == BatchStatusFinalizing {
+		return s.finish(id, w, false)
+	}
+
+	expires := time.Unix(batch.ExpiresAt, 0)
+
+	jobs := make(chan BatchInput)
+
+	var wg sync.WaitGroup
+
+	for range s.concurrency {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for input := range jobs {
+				ok, line := s.execute(ctx, input)
+				w.Write(ok, line)
+			}
+		}()
+	}
+
+	expired := false
+
+	for _, input := range inputs {
+		if w.Done(input.CustomID) {
+			continue
+		}
+
+		if time.Now().After(expires) {
+			expired = true
+		}
+
+		if expired {
+			w.Write(false, BatchOutput{
+				ID:       "batch_req_" + newResponseID(),
+				CustomID: input.CustomID,
+
+				Error: &BatchError{
+					Code:    "batch_expired",
+					Message: "This request could not be executed before the completion window expired.",
+				},
+			})
+
+			continue
+		}
+
+		select {
+		case jobs <- input:
+		case <-ctx.Done():
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+
+		s.progress(id, w)
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	return s.finish(id, w, expired)
+}
+
+func (s *batchStore) execute(ctx context.Context, input BatchInput) (bool, BatchOutput) {
+	output := BatchOutput{
+		ID:       "batch_req_" + newResponseID(),
+		CustomID: input.CustomID,
+	}
+
+	body := input.Body
+
+	if body == nil {
+		body = map[string]any{}
+	}
+
+	// batch requests always return the whole result at once
+	delete(body, "stream")
+
+	data, _ := json.Marshal(body)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimPrefix(input.URL, "/v1"), bytes.NewReader(data))
+
+	if err != nil {
+		output.Error = &BatchError{Code: "invalid_request", Message: err.Error()}
+		return false, output
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := &batchResponseWriter{
+		header: make(http.Header),
+		code:   http.StatusOK,
+	}
+
+	s.handler.ServeHTTP(rec, req)
+
+	output.Response = &BatchResponse{
+		StatusCode: rec.code,
+		RequestID:  "req_" + newResponseID(),
+
+		Body: json.RawMessage(bytes.TrimSpace(rec.body.Bytes())),
+	}
+
+	if !json.Valid(output.Response.Body) {
+		output.Response.Body, _ = json.Marshal(rec.body.String())
+	}
+
+	return rec.code < 400, output
+}
+
+// progress saves the request counts from time to time
+func (s *batchStore) progress(id string, w *batchWriter) {
+	completed, failed, changed := w.Counts(time.Second)
+
+	if !changed {
+		return
+	}
+
+	s.update(id, func(b *Batch) {
+		b.RequestCounts.Completed = completed
+		b.RequestCounts.Failed = failed
+	})
+}
+
+func (s *batchStore) finish(id string, w *batchWriter, expired bool) error {
+	completed, failed, _ := w.Counts(0)
+
+	batch, err := s.Get(id)
+
+	if err != nil {
+		return err
+	}
+
+	cancelled := batch.Status == BatchStatusCancelling
+
+	outputID := "file-" + strings.TrimPrefix(id, "batch_") + "_output"
+	errorID := "file-" + strings.TrimPrefix(id, "batch_") + "_error"
+
+	// a finalization interrupted after importing the result files has moved them away from the writer,
+	// so the counts saved before the import are kept
+	if s.files.Exists(outputID) || s.files.Exists(errorID) {
+		completed = batch.RequestCounts.Completed
+		failed = batch.RequestCounts.Failed
+	}
+
+	if err := s.update(id, func(b *Batch) {
+		b.RequestCounts.Completed = completed
+		b.RequestCounts.Failed = failed
+
+		if !cancelled {
+			b.Status = BatchStatusFinalizing
+			b.FinalizingAt = time.Now().Unix()
+		}
+	}); err != nil {
+		return err
+	}
+
+	w.Close()
+
+	if completed > 0 {
+		if _, err := s.files.Import(s.outputPath(id), outputID, id+"_output.jsonl", FilePurposeBatchOutput); err != nil {
+			return err
+		}
+	} else {
+		outputID = ""
+	}
+
+	if failed > 0 {
+		if _, err := s.files.Import(s.errorPath(id), errorID, id+"_error.jsonl", FilePurposeBatchOutput); err != nil {
+			return err
+		}
+	} else {
+		errorID = ""
+	}
+
+	os.Remove(s.outputPath(id))
+	os.Remove(s.errorPath(id))
+
+	return s.update(id, func(b *Batch) {
+		now := time.Now().Unix()
+
+		b.OutputFileID = outputID
+		b.ErrorFileID = errorID
+
+		switch {
+		case cancelled:
+			b.Status = BatchStatusCancelled
+			b.CancelledAt = now
+
+		case expired:
+			b.Status = BatchStatusExpired
+			b.ExpiredAt = now
+
+		default:
+			b.Status = BatchStatusCompleted
+			b.CompletedAt = now
+		}
+	})
+}
+
+// readInputs reads and validates the requests of the input file
+func (s *batchStore) readInputs(batch *Batch) ([]BatchInput, []BatchError, error) {
+	f, err := s.files.Open(batch.InputFileID)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer f.Close()
+
+	var inputs []BatchInput
+	var errs []BatchError
+
+	seen := make(map[string]bool)
+
+	r := bufio.NewReader(f)
+
+	for line := 1; ; line++ {
+		data, err := r.ReadBytes('\n')
+
+		if len(bytes.TrimSpace(data)) > 0 {
+			var input BatchInput
+
+			invalid := func(code, message string) {
+				errs = append(errs, BatchError{Code: code, Message: message, Line: &line})
+			}
+
+			switch {
+			case json.Unmarshal(data, &input) != nil:
+				invalid("invalid_json_line", "This line is not parseable as valid JSON.")
+
+			case input.CustomID == "":
+				invalid("missing_custom_id", "The custom_id for a request is missing.")
+
+			case seen[input.CustomID]:
+				invalid("duplicate_custom_id", "The custom_id for this request is a duplicate of another request.")
+
+			case input.Method != http.MethodPost:
+				invalid("invalid_method", "The method for this request is not supported.")
+
+			case input.URL != batch.Endpoint:
+				invalid("invalid_url", fmt.Sprintf("The URL provided for this request does not match the batch endpoint %s.", batch.Endpoint))
+
+			default:
+				inputs = append(inputs, input)
+			}
+
+			seen[input.CustomID] = true
+		}
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if len(inputs) == 0 && len(errs) == 0 {
+		errs = append(errs, BatchError{Code: "empty_file", Message: "The input file does not contain any requests."})
+	}
+
+	return inputs, errs, nil
+}
+
+func (s *batchStore) openWriter(id string) (*batchWriter, error) {
+	w := &batchWriter{
+		done: make(map[string]bool),
+	}
+
+	var err error
+
+	if w.output, w.completed, err = openResults(s.outputPath(id), w.done); err != nil {
+		return nil, err
+	}
+
+	if w.errors, w.failed, err = openResults(s.errorPath(id), w.done); err != nil {
+		w.output.Close()
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// update modifies a stored batch
+func (s *batchStore) update(id string, fn func(b *Batch)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	batch, err := s.load(id)
+
+	if err != nil {
+		return err
+	}
+
+	fn(batch)
+
+	return s.save(*batch)
+}
+
+func (s *batchStore) load(id string) (*Batch, error) {
+	if !validID(id) {
+		return nil, errBatchNotFound
+	}
+
+	data, err := os.ReadFile(s.batchPath(id))
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errBatchNotFound
+		}
+
+		return nil, err
+	}
+
+	var batch Batch
+
+	if err := json.Unmarshal(data, &batch); err != nil {
+		return nil, err
+	}
+
+	return &batch, nil
+}
+
+func (s *batchStore) save(batch Batch) error {
+	data, err := json.Marshal(batch)
+
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(s.batchPath(batch.ID), data)
+}
+
+func (s *batchStore) batchPath(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+func (s *batchStore) outputPath(id string) string {
+	return filepath.Join(s.dir, id+"_output.jsonl")
+}
+
+func (s *batchStore) errorPath(id string) string {
+	return filepath.Join(s.dir, id+"_error.jsonl")
+}
+
+// batchWriter appends the results of a batch to its output and error files
+type batchWriter struct {
+	mu sync.Mutex
+
+	output *os.File
+	errors *os.File
+
+	done map[string]bool
+
+	completed int
+	failed    int
+
+	saved time.Time
+	dirty bool
+}
+
+func (w *batchWriter) Done(id string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.done[id]
+}
+
+func (w *batchWriter) Write(ok bool, output BatchOutput) error {
+	data, err := json.Marshal(output)
+
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	f := w.output
+
+	if !ok {
+		f = w.errors
+	}
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return err
+	}
+
+	w.done[output.CustomID] = true
+	w.dirty = true
+
+	if ok {
+		w.completed++
+	} else {
+		w.failed++
+	}
+
+	return nil
+}
+
+// Counts returns the number of finished requests and whether they changed since they were last returned, at most once per interval
+func (w *batchWriter) Counts(interval time.Duration) (int, int, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.dirty || time.Since(w.saved) < interval {
+		return w.completed, w.failed, false
+	}
+
+	w.dirty = false
+	w.saved = time.Now()
+
+	return w.completed, w.failed, true
+}
+
+func (w *batchWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return errors.Join(w.output.Close(), w.errors.Close())
+}
+
+// openResults opens a result file for appending and collects the requests it already contains.
+// A line left incomplete by an interrupted write is cut off.
+func openResults(path string, done map[string]bool) (*os.File, int, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var count int
+	var offset int64
+
+	r := bufio.NewReader(f)
+
+	for {
+		data, err := r.ReadBytes('\n')
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			f.Close()
+			return nil, 0, err
+		}
+
+		var output BatchOutput
+
+		if json.Unmarshal(data, &output) != nil {
+			break
+		}
+
+		done[output.CustomID] = true
+
+		count++
+		offset += int64(len(data))
+	}
+
+	if err := f.Truncate(offset); err != nil {
+		f.Close()
+		return nil, 0, err
+	}
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, 0, err
+	}
+
+	return f, count, nil
+}
+
+// batchResponseWriter captures the response of a batch request
+type batchResponseWriter struct {
+	header http.Header
+
+	code int
+	body bytes.Buffer
+}
+
+func (w *batchResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *batchResponseWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *batchResponseWriter) WriteHeader(code int) {
+	w.code = code
+}
@@ -0,0 +1,111 @@
+package openai
+
+import (
+	"bufio"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatch(t *testing.T) {
+	files, batches := newTestBatchStore(t)
+
+	batch, err := batches.Create(newTestBatchRequest(t, files))
+	require.NoError(t, err)
+
+	batch = waitBatch(t, batches, batch.ID)
+
+	require.Equal(t, BatchRequestCounts{Total: 2, Completed: 2}, batch.RequestCounts)
+	require.Equal(t, 2, countLines(t, files, batch.OutputFileID))
+	require.Empty(t, batch.ErrorFileID)
+}
+
+func TestBatchResumeFinalizing(t *testing.T) {
+	files, batches := newTestBatchStore(t)
+
+	batch, err := batches.Create(newTestBatchRequest(t, files))
+	require.NoError(t, err)
+
+	batch = waitBatch(t, batches, batch.ID)
+
+	// rewinds to a crash after the output file was moved into the store, but before it was linked to the batch
+	require.NoError(t, os.Remove(files.metadataPath(batch.OutputFileID)))
+
+	require.NoError(t, batches.update(batch.ID, func(b *Batch) {
+		b.Status = BatchStatusFinalizing
+		b.OutputFileID = ""
+	}))
+
+	require.NoError(t, batches.Resume())
+
+	resumed := waitBatch(t, batches, batch.ID)
+
+	require.Equal(t, batch.OutputFileID, resumed.OutputFileID)
+	require.Equal(t, BatchRequestCounts{Total: 2, Completed: 2}, resumed.RequestCounts)
+	require.Equal(t, 2, countLines(t, files, resumed.OutputFileID))
+}
+
+func newTestBatchStore(t *testing.T) (*fileStore, *batchStore) {
+	dir := t.TempDir()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJson(w, map[string]any{"object": "list"})
+	})
+
+	files := newFileStore(filepath.Join(dir, "files"))
+
+	return files, newBatchStore(filepath.Join(dir, "batches"), files, handler)
+}
+
+func newTestBatchRequest(t *testing.T, files *fileStore) BatchRequest {
+	input := `{"custom_id": "1", "method": "POST", "url": "/v1/embeddings", "body": {"input": "a"}}
+{"custom_id": "2", "method": "POST", "url": "/v1/embeddings", "body": {"input": "b"}}
+`
+
+	file, err := files.Create("input.jsonl", FilePurposeBatch, strings.NewReader(input))
+	require.NoError(t, err)
+
+	return BatchRequest{
+		InputFileID: file.ID,
+
+		Endpoint:         "/v1/embeddings",
+		CompletionWindow: "24h",
+	}
+}
+
+func waitBatch(t *testing.T, batches *batchStore, id string) *Batch {
+	var batch *Batch
+
+	require.Eventually(t, func() bool {
+		b, err := batches.Get(id)
+
+		if err != nil {
+			return false
+		}
+
+		batch = b
+		return b.Status == BatchStatusCompleted
+	}, 5*time.Second, 10*time.Millisecond)
+
+	return batch
+}
+
+func countLines(t *testing.T, files *fileStore, id string) int {
+	f, err := files.Open(id)
+	require.NoError(t, err)
+
+	defer f.Close()
+
+	var count int
+
+	for s := bufio.NewScanner(f); s.Scan(); {
+		count++
+	}
+
+	return count
+}
@@ -0,0 +1,243 @@
+package openai
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var errFileNotFound = errors.New("file not found")
+
+// fileStore keeps uploaded and generated files on disk, each as its content and a json file with its metadata
+type fileStore struct {
+	dir string
+
+	mu sync.Mutex
+}
+
+func newFileStore(dir string) *fileStore {
+	return &fileStore{
+		dir: dir,
+	}
+}
+
+func (s *fileStore) Create(name string, purpose FilePurpose, r io.Reader) (*File, error) {
+	file := File{
+		Object: "file",
+
+		ID: "file-" + newResponseID(),
+
+		CreatedAt: time.Now().Unix(),
+		Filename:  name,
+
+		Purpose: purpose,
+	}
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Create(s.contentPath(file.ID))
+
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := io.Copy(f, r)
+	f.Close()
+
+	if err != nil {
+		os.Remove(s.contentPath(file.ID))
+		return nil, err
+	}
+
+	file.Bytes = n
+
+	if err := s.save(file); err != nil {
+		os.Remove(s.contentPath(file.ID))
+		return nil, err
+	}
+
+	return &file, nil
+}
+
+// Import moves an existing file into the store under the given id.
+// Importing again completes an import interrupted after the content was moved, and keeps the moved content.
+func (s *fileStore) Import(path string, id string, name string, purpose FilePurpose) (*File, error) {
+	if file, err := s.Get(id); err == nil {
+		return file, nil
+	}
+
+	if !validID(id) {
+		return nil, errors.New("invalid file id: " + id)
+	}
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return nil, err
+	}
+
+	if !s.Exists(id) {
+		if err := os.Rename(path, s.contentPath(id)); err != nil {
+			return nil, err
+		}
+	}
+
+	info, err := os.Stat(s.contentPath(id))
+
+	if err != nil {
+		return nil, err
+	}
+
+	file := File{
+		Object: "file",
+
+		ID: id,
+
+		Bytes:     info.Size(),
+		CreatedAt: time.Now().Unix(),
+		Filename:  name,
+
+		Purpose: purpose,
+	}
+
+	if err := s.save(file); err != nil {
+		return nil, err
+	}
+
+	return &file, nil
+}
+
+// Exists reports whether the content of a file is stored, even if its metadata is not yet
+func (s *fileStore) Exists(id string) bool {
+	if !validID(id) {
+		return false
+	}
+
+	_, err := os.Stat(s.contentPath(id))
+	return err == nil
+}
+
+func (s *fileStore) Get(id string) (*File, error) {
+	if !validID(id) {
+		return nil, errFileNotFound
+	}
+
+	data, err := os.ReadFile(s.metadataPath(id))
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errFileNotFound
+		}
+
+		return nil, err
+	}
+
+	var file File
+
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	return &file, nil
+}
+
+func (s *fileStore) List(purpose FilePurpose) ([]File, error) {
+	entries, err := os.ReadDir(s.dir)
+
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	result := []File{}
+
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+
+		if !ok {
+			continue
+		}
+
+		file, err := s.Get(id)
+
+		if err != nil {
+			continue
+		}
+
+		if purpose != "" && file.Purpose != purpose {
+			continue
+		}
+
+		result = append(result, *file)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreatedAt > result[j].CreatedAt
+	})
+
+	return result, nil
+}
+
+func (s *fileStore) Open(id string) (*os.File, error) {
+	if _, err := s.Get(id); err != nil {
+		return nil, err
+	}
+
+	return os.Open(s.contentPath(id))
+}
+
+func (s *fileStore) Delete(id string) error {
+	if _, err := s.Get(id); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.metadataPath(id)); err != nil {
+		return err
+	}
+
+	return os.Remove(s.contentPath(id))
+}
+
+func (s *fileStore) save(file File) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(file)
+
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(s.metadataPath(file.ID), data)
+}
+
+func (s *fileStore) contentPath(id string) string {
+	return filepath.Join(s.dir, id)
+}
+
+func (s *fileStore) metadataPath(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+// validID rejects ids that could escape the store directory
+func validID(id string) bool {
+	return id != "" && !strings.ContainsAny(id, `/\.`)
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	temp := path + ".tmp"
+
+	if err := os.WriteFile(temp, data, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(temp, path)
+}
@@ -3,6 +3,8 @@ package openai
 import (
 	"encoding/json"
 	"net/http"
+	"os"
+	"path/filepath"
 
 	"github.com/adrianliechti/wingman/config"
 	"github.com/go-chi/chi/v5"
@@ -11,6 +13,11 @@ import (
 type Handler struct {
 	*config.Config
 	http.Handler
+
+	responses *responseStore
+
+	files   *fileStore
+	batches *batchStore
 }
 
 func New(cfg *config.Config) (*Handler, error) {
@@ -19,9 +26,26 @@ func New(cfg *config.Config) (*Handler, error) {
 	h := &Handler{
 		Config:  cfg,
 		Handler: mux,
+
+		responses: newResponseStore(1000),
 	}
 
+	dir := cfg.Data
+
+	// configs not loaded from a file have no data directory
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "wingman")
+	}
+
+	h.files = newFileStore(filepath.Join(dir, "files"))
+	h.batches = newBatchStore(filepath.Join(dir, "batches"), h.files, mux)
+
 	h.Attach(mux)
+
+	if err := h.batches.Resume(); err != nil {
+		return nil, err
+	}
+
 	return h, nil
 }
 
@@ -30,13 +54,31 @@ func (h *Handler) Attach(r chi.Router) {
 	r.Get("/models/{id}", h.handleModel)
 
 	r.Post("/embeddings", h.handleEmbeddings)
+	r.Post("/moderations", h.handleModerations)
 
 	r.Post("/chat/completions", h.handleChatCompletion)
 
+	r.Post("/responses", h.handleResponseCreate)
+	r.Get("/responses/{id}", h.handleResponse)
+	r.Delete("/responses/{id}", h.handleResponseDelete)
+
 	r.Post("/audio/speech", h.handleAudioSpeech)
 	r.Post("/audio/transcriptions", h.handleAudioTranscription)
 
+	r.Get("/realtime", h.handleRealtime)
+
 	r.Post("/images/generations", h.handleImageGeneration)
+
+	r.Get("/files", h.handleFiles)
+	r.Post("/files", h.handleFileUpload)
+	r.Get("/files/{id}", h.handleFile)
+	r.Get("/files/{id}/content", h.handleFileContent)
+	r.Delete("/files/{id}", h.handleFileDelete)
+
+	r.Get("/batches", h.handleBatches)
+	r.Post("/batches", h.handleBatchCreate)
+	r.Get("/batches/{id}", h.handleBatch)
+	r.Post("/batches/{id}/cancel", h.handleBatchCancel)
 }
 
 func writeJson(w http.ResponseWriter, v any) {
@@ -0,0 +1,103 @@
+package openai
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *Handler) handleBatches(w http.ResponseWriter, r *http.Request) {
+	batches, err := h.batches.List()
+
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if after := r.URL.Query().Get("after"); after != "" {
+		for i, b := range batches {
+			if b.ID == after {
+				batches = batches[i+1:]
+				break
+			}
+		}
+	}
+
+	limit := 20
+
+	if val := r.URL.Query().Get("limit"); val != "" {
+		if l, err := strconv.Atoi(val); err == nil && l > 0 {
+			limit = min(l, 100)
+		}
+	}
+
+	result := BatchList{
+		Object: "list",
+
+		Batches: batches,
+	}
+
+	if len(batches) > limit {
+		result.Batches = batches[:limit]
+		result.HasMore = true
+	}
+
+	if len(result.Batches) > 0 {
+		result.FirstID = result.Batches[0].ID
+		result.LastID = result.Batches[len(result.Batches)-1].ID
+	}
+
+	writeJson(w, result)
+}
+
+func (h *Handler) handleBatch(w http.ResponseWriter, r *http.Request) {
+	batch, err := h.batches.Get(chi.URLParam(r, "id"))
+
+	if err != nil {
+		writeBatchError(w, err)
+		return
+	}
+
+	writeJson(w, batch)
+}
+
+func (h *Handler) handleBatchCreate(w http.ResponseWriter, r *http.Request) {
+	var req BatchRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	batch, err := h.batches.Create(req)
+
+	if err != nil {
+		writeBatchError(w, err)
+		return
+	}
+
+	writeJson(w, batch)
+}
+
+func (h *Handler) handleBatchCancel(w http.ResponseWriter, r *http.Request) {
+	batch, err := h.batches.Cancel(chi.URLParam(r, "id"))
+
+	if err != nil {
+		writeBatchError(w, err)
+		return
+	}
+
+	writeJson(w, batch)
+}
+
+func writeBatchError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errBatchNotFound) || errors.Is(err, errFileNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	writeError(w, http.StatusBadRequest, err)
+}
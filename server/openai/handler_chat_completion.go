@@ -5,15 +5,18 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime"
 	"net/http"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/server/sse"
 
 	"github.com/google/uuid"
 )
@@ -47,6 +50,17 @@ func (h *Handler) handleChatCompletion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	choices := 1
+
+	if req.N != nil {
+		if *req.N < 1 || *req.N > 128 {
+			writeError(w, http.StatusBadRequest, errors.New("n must be between 1 and 128"))
+			return
+		}
+
+		choices = *req.N
+	}
+
 	var stops []string
 
 	switch v := req.Stop.(type) {
@@ -61,8 +75,20 @@ func (h *Handler) handleChatCompletion(w http.ResponseWriter, r *http.Request) {
 		Stop:  stops,
 		Tools: tools,
 
+		ToolChoice:        toToolChoice(req.ToolChoice),
+		ParallelToolCalls: req.ParallelToolCalls,
+
 		MaxTokens:   req.MaxTokens,
 		Temperature: req.Temperature,
+		TopP:        req.TopP,
+
+		PresencePenalty:  req.PresencePenalty,
+		FrequencyPenalty: req.FrequencyPenalty,
+
+		Seed: req.Seed,
+
+		Logprobs:    req.Logprobs,
+		TopLogprobs: req.TopLogprobs,
 	}
 
 	switch req.ReasoningEffort {
@@ -93,72 +119,77 @@ func (h *Handler) handleChatCompletion(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if req.Stream {
-		w.Header().Set("Content-Type", "text/event-stream")
-		w.Header().Set("Cache-Control", "no-cache")
-		w.Header().Set("Connection", "keep-alive")
+		s, ctx := sse.New(w, r)
+		defer s.Close()
 
-		rc := http.NewResponseController(w)
+		completions, err := completeChoices(ctx, completer, messages, options, choices, func(index int, options *provider.CompleteOptions) {
+			options.Stream = func(ctx context.Context, completion provider.Completion) error {
+				result := ChatCompletion{
+					Object: "chat.completion.chunk",
 
-		options.Stream = func(ctx context.Context, completion provider.Completion) error {
-			result := ChatCompletion{
-				Object: "chat.completion.chunk",
+					ID: completion.ID,
 
-				ID: completion.ID,
+					Model:   req.Model,
+					Created: time.Now().Unix(),
 
-				Model:   req.Model,
-				Created: time.Now().Unix(),
+					Choices: []ChatCompletionChoice{
+						{
+							Index: index,
+
+							FinishReason: oaiFinishReason(completion.Reason),
 
-				Choices: []ChatCompletionChoice{
-					{
-						FinishReason: oaiFinishReason(completion.Reason),
+							Delta: &ChatCompletionMessage{
+								Role:    oaiMessageRole(completion.Message.Role),
+								Content: completion.Message.Content,
 
-						Delta: &ChatCompletionMessage{
-							Role:    oaiMessageRole(completion.Message.Role),
-							Content: completion.Message.Content,
+								ReasoningContent: completion.Message.Reasoning,
 
-							ToolCalls:  oaiToolCalls(completion.Message.ToolCalls),
-							ToolCallID: completion.Message.Tool,
+								ToolCalls:  oaiToolCalls(completion.Message.ToolCalls),
+								ToolCallID: completion.Message.Tool,
+							},
+
+							Logprobs: oaiLogprobs(completion.Logprobs),
 						},
 					},
-				},
+				}
+
+				return s.Send("", result)
 			}
+		})
 
-			if completion.Usage != nil {
-				result.Usage = &Usage{
-					PromptTokens:     completion.Usage.InputTokens,
-					CompletionTokens: completion.Usage.OutputTokens,
-					TotalTokens:      completion.Usage.InputTokens + completion.Usage.OutputTokens,
-				}
+		if err != nil {
+			// the client is gone, so there is no one left to report to
+			if ctx.Err() != nil {
+				return
 			}
 
-			var data bytes.Buffer
+			writeStreamError(s, err)
+			return
+		}
+
+		// usage is only reported in a final chunk without choices, as requested by the client
+		if req.StreamOptions != nil && req.StreamOptions.IncludeUsage {
+			result := ChatCompletion{
+				Object: "chat.completion.chunk",
 
-			enc := json.NewEncoder(&data)
-			enc.SetEscapeHTML(false)
-			enc.Encode(result)
+				ID: completions[0].ID,
 
-			event := strings.TrimSpace(data.String())
+				Model:   req.Model,
+				Created: time.Now().Unix(),
 
-			if _, err := fmt.Fprintf(w, "data: %s\n\n", event); err != nil {
-				return err
-			}
+				Choices: []ChatCompletionChoice{},
 
-			if err := rc.Flush(); err != nil {
-				return err
+				Usage: oaiUsage(sumUsage(completions)),
 			}
 
-			return nil
-		}
-
-		if _, err := completer.Complete(r.Context(), messages, options); err != nil {
-			writeError(w, http.StatusBadRequest, err)
-			return
+			if err := s.Send("", result); err != nil {
+				return
+			}
 		}
 
-		fmt.Fprintf(w, "data: [DONE]\n\n")
-		rc.Flush()
+		s.SendData("", "[DONE]")
 	} else {
-		completion, err := completer.Complete(r.Context(), messages, options)
+		completions, err := completeChoices(r.Context(), completer, messages, options, choices, nil)
 
 		if err != nil {
 			writeError(w, http.StatusBadRequest, err)
@@ -168,38 +199,131 @@ func (h *Handler) handleChatCompletion(w http.ResponseWriter, r *http.Request) {
 		result := ChatCompletion{
 			Object: "chat.completion",
 
-			ID: completion.ID,
+			ID: completions[0].ID,
 
 			Model:   req.Model,
 			Created: time.Now().Unix(),
 
-			Choices: []ChatCompletionChoice{
-				{
-					FinishReason: oaiFinishReason(completion.Reason),
+			Choices: []ChatCompletionChoice{},
+		}
 
-					Message: &ChatCompletionMessage{
-						Role:    oaiMessageRole(completion.Message.Role),
-						Content: completion.Message.Content,
+		for i, completion := range completions {
+			choice := ChatCompletionChoice{
+				Index: i,
 
-						ToolCalls:  oaiToolCalls(completion.Message.ToolCalls),
-						ToolCallID: completion.Message.Tool,
-					},
+				FinishReason: oaiFinishReason(completion.Reason),
+
+				Message: &ChatCompletionMessage{
+					Role:    oaiMessageRole(completion.Message.Role),
+					Content: completion.Message.Content,
+
+					ReasoningContent: completion.Message.Reasoning,
+
+					ToolCalls:  oaiToolCalls(completion.Message.ToolCalls),
+					ToolCallID: completion.Message.Tool,
 				},
-			},
-		}
+			}
+
+			if req.Logprobs {
+				choice.Logprobs = oaiLogprobs(completion.Logprobs)
 
-		if completion.Usage != nil {
-			result.Usage = &Usage{
-				PromptTokens:     completion.Usage.InputTokens,
-				CompletionTokens: completion.Usage.OutputTokens,
-				TotalTokens:      completion.Usage.InputTokens + completion.Usage.OutputTokens,
+				if choice.Logprobs == nil {
+					choice.Logprobs = &Logprobs{Content: []TokenLogprob{}}
+				}
 			}
+
+			result.Choices = append(result.Choices, choice)
+		}
+
+		if usage := sumUsage(completions); usage != nil {
+			result.Usage = oaiUsage(usage)
 		}
 
 		writeJson(w, result)
 	}
 }
 
+// completeChoices runs a completion per requested choice concurrently, as the providers return a single choice each
+func completeChoices(ctx context.Context, completer provider.Completer, messages []provider.Message, options *provider.CompleteOptions, n int, prepare func(index int, options *provider.CompleteOptions)) ([]*provider.Completion, error) {
+	if n == 1 {
+		if prepare != nil {
+			prepare(0, options)
+		}
+
+		completion, err := completer.Complete(ctx, messages, options)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return []*provider.Completion{completion}, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	result := make([]*provider.Completion, n)
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+
+	for i := range n {
+		choiceOptions := *options
+
+		if prepare != nil {
+			prepare(i, &choiceOptions)
+		}
+
+		// file contents are readers, so each choice needs its own
+		choiceMessages := cloneMessages(messages)
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			result[i], errs[i] = completer.Complete(ctx, choiceMessages, &choiceOptions)
+
+			if errs[i] != nil {
+				cancel()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil && !errors.Is(err, context.Canceled) {
+			return nil, err
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func sumUsage(completions []*provider.Completion) *provider.Usage {
+	var result *provider.Usage
+
+	for _, c := range completions {
+		if c.Usage == nil {
+			continue
+		}
+
+		if result == nil {
+			result = &provider.Usage{}
+		}
+
+		result.InputTokens += c.Usage.InputTokens
+		result.OutputTokens += c.Usage.OutputTokens
+	}
+
+	return result
+}
+
 func toMessages(s []ChatCompletionMessage) ([]provider.Message, error) {
 	result := make([]provider.Message, 0)
 
@@ -242,6 +366,8 @@ func toMessages(s []ChatCompletionMessage) ([]provider.Message, error) {
 			Role:    toMessageRole(m.Role),
 			Content: content,
 
+			Reasoning: m.ReasoningContent,
+
 			Files: files,
 
 			Tool:      m.ToolCallID,
@@ -255,7 +381,7 @@ func toMessages(s []ChatCompletionMessage) ([]provider.Message, error) {
 
 func toMessageRole(r MessageRole) provider.MessageRole {
 	switch r {
-	case MessageRoleSystem:
+	case MessageRoleSystem, MessageRoleDeveloper:
 		return provider.MessageRoleSystem
 
 	case MessageRoleUser:
@@ -387,6 +513,86 @@ func oaiMessageRole(r provider.MessageRole) MessageRole {
 	}
 }
 
+func toToolChoice(c *ToolChoice) *provider.ToolChoice {
+	if c == nil {
+		return nil
+	}
+
+	if c.Function != nil {
+		return &provider.ToolChoice{
+			Mode: provider.ToolChoiceModeRequired,
+			Name: c.Function.Name,
+		}
+	}
+
+	switch c.Mode {
+	case ToolChoiceModeNone:
+		return &provider.ToolChoice{Mode: provider.ToolChoiceModeNone}
+
+	case ToolChoiceModeRequired:
+		return &provider.ToolChoice{Mode: provider.ToolChoiceModeRequired}
+
+	default:
+		return &provider.ToolChoice{Mode: provider.ToolChoiceModeAuto}
+	}
+}
+
+func oaiLogprobs(logprobs []provider.Logprob) *Logprobs {
+	if len(logprobs) == 0 {
+		return nil
+	}
+
+	result := &Logprobs{
+		Content: []TokenLogprob{},
+	}
+
+	for _, l := range logprobs {
+		logprob := TokenLogprob{
+			Token:   l.Token,
+			Logprob: l.Logprob,
+
+			Bytes: tokenBytes(l.Token),
+
+			TopLogprobs: []TopLogprob{},
+		}
+
+		for _, t := range l.TopLogprobs {
+			logprob.TopLogprobs = append(logprob.TopLogprobs, TopLogprob{
+				Token:   t.Token,
+				Logprob: t.Logprob,
+
+				Bytes: tokenBytes(t.Token),
+			})
+		}
+
+		result.Content = append(result.Content, logprob)
+	}
+
+	return result
+}
+
+func tokenBytes(token string) []int {
+	result := make([]int, 0, len(token))
+
+	for _, b := range []byte(token) {
+		result = append(result, int(b))
+	}
+
+	return result
+}
+
+func oaiUsage(usage *provider.Usage) *Usage {
+	if usage == nil {
+		return &Usage{}
+	}
+
+	return &Usage{
+		PromptTokens:     usage.InputTokens,
+		CompletionTokens: usage.OutputTokens,
+		TotalTokens:      usage.InputTokens + usage.OutputTokens,
+	}
+}
+
 func oaiFinishReason(val provider.CompletionReason) *FinishReason {
 	switch val {
 	case provider.CompletionReasonStop:
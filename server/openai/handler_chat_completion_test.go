@@ -0,0 +1,113 @@
+package openai_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/adrianliechti/wingman/config"
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/server/openai"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatCompletionStream(t *testing.T) {
+	chunks := streamChatCompletion(t, `{"model": "test", "stream": true, "messages": [{"role": "user", "content": "hi"}]}`)
+
+	require.Len(t, chunks, 2)
+
+	for _, c := range chunks {
+		require.Nil(t, c.Usage)
+		require.Len(t, c.Choices, 1)
+	}
+}
+
+func TestChatCompletionStreamUsage(t *testing.T) {
+	chunks := streamChatCompletion(t, `{"model": "test", "stream": true, "stream_options": {"include_usage": true}, "messages": [{"role": "user", "content": "hi"}]}`)
+
+	require.Len(t, chunks, 3)
+
+	for _, c := range chunks[:2] {
+		require.Nil(t, c.Usage)
+	}
+
+	last := chunks[2]
+
+	require.Empty(t, last.Choices)
+	require.Equal(t, &openai.Usage{PromptTokens: 3, CompletionTokens: 2, TotalTokens: 5}, last.Usage)
+}
+
+// streamChatCompletion returns the chunks of a streamed chat completion
+func streamChatCompletion(t *testing.T, body string) []openai.ChatCompletion {
+	cfg := &config.Config{
+		Data: t.TempDir(),
+	}
+
+	cfg.RegisterCompleter("test", &usageCompleter{})
+
+	h, err := openai.New(cfg)
+	require.NoError(t, err)
+
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/chat/completions", strings.NewReader(body)))
+
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var result []openai.ChatCompletion
+
+	scanner := bufio.NewScanner(resp.Body)
+
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+
+		if !ok || data == "[DONE]" {
+			continue
+		}
+
+		var chunk openai.ChatCompletion
+		require.NoError(t, json.Unmarshal([]byte(data), &chunk))
+
+		result = append(result, chunk)
+	}
+
+	return result
+}
+
+// usageCompleter streams usage with every delta, like providers reporting running totals
+type usageCompleter struct{}
+
+func (c *usageCompleter) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	usage := &provider.Usage{InputTokens: 3, OutputTokens: 2}
+
+	for _, content := range []string{"Hel", "lo"} {
+		if err := options.Stream(ctx, provider.Completion{
+			ID: "1",
+
+			Message: provider.Message{
+				Role:    provider.MessageRoleAssistant,
+				Content: content,
+			},
+
+			Usage: usage,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return &provider.Completion{
+		ID:     "1",
+		Reason: provider.CompletionReasonStop,
+
+		Message: provider.Message{
+			Role:    provider.MessageRoleAssistant,
+			Content: "Hello",
+		},
+
+		Usage: usage,
+	}, nil
+}
@@ -0,0 +1,100 @@
+package openai
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *Handler) handleFiles(w http.ResponseWriter, r *http.Request) {
+	files, err := h.files.List(FilePurpose(r.URL.Query().Get("purpose")))
+
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJson(w, FileList{
+		Object: "list",
+		Files:  files,
+	})
+}
+
+func (h *Handler) handleFile(w http.ResponseWriter, r *http.Request) {
+	file, err := h.files.Get(chi.URLParam(r, "id"))
+
+	if err != nil {
+		writeFileError(w, err)
+		return
+	}
+
+	writeJson(w, file)
+}
+
+func (h *Handler) handleFileContent(w http.ResponseWriter, r *http.Request) {
+	f, err := h.files.Open(chi.URLParam(r, "id"))
+
+	if err != nil {
+		writeFileError(w, err)
+		return
+	}
+
+	defer f.Close()
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	io.Copy(w, f)
+}
+
+func (h *Handler) handleFileUpload(w http.ResponseWriter, r *http.Request) {
+	purpose := FilePurpose(r.FormValue("purpose"))
+
+	if purpose == "" {
+		writeError(w, http.StatusBadRequest, errors.New("purpose is required"))
+		return
+	}
+
+	f, header, err := r.FormFile("file")
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	defer f.Close()
+
+	file, err := h.files.Create(header.Filename, purpose, f)
+
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJson(w, file)
+}
+
+func (h *Handler) handleFileDelete(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if err := h.files.Delete(id); err != nil {
+		writeFileError(w, err)
+		return
+	}
+
+	writeJson(w, FileDeleted{
+		Object: "file",
+
+		ID:      id,
+		Deleted: true,
+	})
+}
+
+func writeFileError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errFileNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	writeError(w, http.StatusInternalServerError, err)
+}
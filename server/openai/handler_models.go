@@ -2,22 +2,33 @@ package openai
 
 import (
 	"net/http"
+	"slices"
+	"strings"
 	"time"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
 )
 
 func (h *Handler) handleModels(w http.ResponseWriter, r *http.Request) {
+	types := queryValues(r, "type")
+	capabilities := queryValues(r, "capability")
+
 	result := &ModelList{
 		Object: "list",
+
+		Models: []Model{},
 	}
 
 	for _, m := range h.Models() {
-		result.Models = append(result.Models, Model{
-			Object: "model",
+		if len(types) > 0 && !slices.Contains(types, string(m.Type)) {
+			continue
+		}
 
-			ID:      m.ID,
-			Created: time.Now().Unix(),
-			OwnedBy: "openai",
-		})
+		if !hasCapabilities(m, capabilities) {
+			continue
+		}
+
+		result.Models = append(result.Models, toModel(m))
 	}
 
 	writeJson(w, result)
@@ -31,13 +42,57 @@ func (h *Handler) handleModel(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result := &Model{
+	result := toModel(*model)
+
+	writeJson(w, result)
+}
+
+func toModel(m provider.Model) Model {
+	result := Model{
 		Object: "model",
 
-		ID:      model.ID,
+		ID:      m.ID,
 		Created: time.Now().Unix(),
-		OwnedBy: "openai",
+		OwnedBy: m.Provider,
+
+		Type: string(m.Type),
+
+		Name:        m.Name,
+		Description: m.Description,
 	}
 
-	writeJson(w, result)
+	if result.OwnedBy == "" {
+		result.OwnedBy = "openai"
+	}
+
+	for _, c := range m.Capabilities {
+		result.Capabilities = append(result.Capabilities, string(c))
+	}
+
+	return result
+}
+
+// queryValues returns the values of a query parameter, given either repeated or comma separated
+func queryValues(r *http.Request, key string) []string {
+	var result []string
+
+	for _, v := range r.URL.Query()[key] {
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				result = append(result, strings.ToLower(s))
+			}
+		}
+	}
+
+	return result
+}
+
+func hasCapabilities(m provider.Model, capabilities []string) bool {
+	for _, c := range capabilities {
+		if !m.HasCapability(provider.Capability(c)) {
+			return false
+		}
+	}
+
+	return true
 }
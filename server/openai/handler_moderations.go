@@ -0,0 +1,58 @@
+package openai
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+
+	"github.com/google/uuid"
+)
+
+func (h *Handler) handleModerations(w http.ResponseWriter, r *http.Request) {
+	var req ModerationRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	moderator, err := h.Moderator(req.Model)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if len(req.Input) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("no input provided"))
+		return
+	}
+
+	moderations, err := moderator.Moderate(r.Context(), req.Input, &provider.ModerateOptions{})
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	result := ModerationResponse{
+		ID: "modr-" + strings.ReplaceAll(uuid.NewString(), "-", ""),
+
+		Model:   req.Model,
+		Results: []ModerationResult{},
+	}
+
+	for _, m := range moderations {
+		result.Results = append(result.Results, ModerationResult{
+			Flagged: m.Flagged,
+
+			Categories:     m.Categories,
+			CategoryScores: m.Scores,
+		})
+	}
+
+	writeJson(w, result)
+}
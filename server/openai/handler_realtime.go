@@ -0,0 +1,610 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/to"
+
+	"github.com/coder/websocket"
+	"github.com/google/uuid"
+)
+
+// realtimeReadLimit allows audio appends of up to 15 MiB as base64
+const realtimeReadLimit = 24 << 20
+
+func (h *Handler) handleRealtime(w http.ResponseWriter, r *http.Request) {
+	model := r.URL.Query().Get("model")
+
+	completer, err := h.Completer(model)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
+		Subprotocols: []string{"realtime"},
+
+		// same as the cors policy of the server
+		OriginPatterns: []string{"*"},
+	})
+
+	if err != nil {
+		return
+	}
+
+	defer conn.CloseNow()
+
+	conn.SetReadLimit(realtimeReadLimit)
+
+	s := newRealtimeSession(h, conn, model, completer)
+	s.run(r.Context())
+
+	conn.Close(websocket.StatusNormalClosure, "")
+}
+
+// realtimeSession holds the conversation of a realtime connection.
+// Client events are handled in the read loop, while transcriptions and responses run one after another in the background,
+// so audio keeps flowing and a response can be interrupted by the next turn.
+type realtimeSession struct {
+	*Handler
+
+	conn      *websocket.Conn
+	completer provider.Completer
+
+	ctx context.Context
+
+	mu sync.Mutex
+
+	session RealtimeSession
+	items   []*RealtimeItem
+
+	buffer []byte
+	vad    *voiceDetector
+	speech string
+
+	// cancel stops the running response
+	cancel context.CancelCauseFunc
+
+	queueMu sync.Mutex
+	queue   chan struct{}
+}
+
+// realtimeCancelReason tells why a response was cancelled
+type realtimeCancelReason string
+
+func (r realtimeCancelReason) Error() string {
+	return string(r)
+}
+
+const (
+	realtimeCancelTurnDetected    realtimeCancelReason = "turn_detected"
+	realtimeCancelClientCancelled realtimeCancelReason = "client_cancelled"
+)
+
+func newRealtimeSession(h *Handler, conn *websocket.Conn, model string, completer provider.Completer) *realtimeSession {
+	modalities := []RealtimeModality{RealtimeModalityText}
+
+	if _, err := h.Synthesizer(""); err == nil {
+		modalities = append(modalities, RealtimeModalityAudio)
+	}
+
+	session := RealtimeSession{
+		ID:     realtimeID("sess_"),
+		Object: "realtime.session",
+
+		Model: model,
+
+		Modalities: modalities,
+
+		InputAudioFormat:  "pcm16",
+		OutputAudioFormat: "pcm16",
+
+		TurnDetection: &RealtimeTurnDetection{
+			Type: "server_vad",
+
+			Threshold:         to.Ptr(0.5),
+			PrefixPaddingMs:   to.Ptr(300),
+			SilenceDurationMs: to.Ptr(500),
+
+			CreateResponse:    to.Ptr(true),
+			InterruptResponse: to.Ptr(true),
+		},
+
+		Tools:      []RealtimeTool{},
+		ToolChoice: "auto",
+	}
+
+	return &realtimeSession{
+		Handler: h,
+
+		conn:      conn,
+		completer: completer,
+
+		session: session,
+		vad:     newVoiceDetector(session.TurnDetection),
+	}
+}
+
+func (s *realtimeSession) run(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	s.ctx = ctx
+
+	s.mu.Lock()
+	s.send(RealtimeEvent{Type: RealtimeEventTypeSessionCreated, Session: to.Ptr(s.session)})
+	s.mu.Unlock()
+
+	for {
+		_, data, err := s.conn.Read(ctx)
+
+		if err != nil {
+			break
+		}
+
+		var event RealtimeEvent
+
+		if err := json.Unmarshal(data, &event); err != nil {
+			s.fail("", err)
+			continue
+		}
+
+		if err := s.handle(event, data); err != nil {
+			s.fail(event.ID, err)
+		}
+	}
+
+	cancel()
+
+	// wait for the background jobs to stop writing
+	s.queueMu.Lock()
+	queue := s.queue
+	s.queueMu.Unlock()
+
+	if queue != nil {
+		<-queue
+	}
+}
+
+func (s *realtimeSession) handle(event RealtimeEvent, data []byte) error {
+	switch event.Type {
+	case RealtimeEventTypeSessionUpdate:
+		var update struct {
+			Session json.RawMessage `json:"session"`
+		}
+
+		if err := json.Unmarshal(data, &update); err != nil {
+			return err
+		}
+
+		return s.updateSession(update.Session)
+
+	case RealtimeEventTypeInputAudioBufferAppend:
+		audio, err := base64.StdEncoding.DecodeString(event.Audio)
+
+		if err != nil {
+			return errors.New("invalid audio: " + err.Error())
+		}
+
+		s.appendAudio(audio)
+		return nil
+
+	case RealtimeEventTypeInputAudioBufferCommit:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		// a manual commit takes all of the buffered audio
+		if s.vad != nil {
+			s.vad.Reset()
+		}
+
+		s.speech = ""
+
+		return s.commit(realtimeID("item_"), false)
+
+	case RealtimeEventTypeInputAudioBufferClear:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.buffer = nil
+
+		if s.vad != nil {
+			s.vad.Reset()
+		}
+
+		s.send(RealtimeEvent{Type: RealtimeEventTypeInputAudioBufferCleared})
+		return nil
+
+	case RealtimeEventTypeConversationItemCreate:
+		if event.Item == nil {
+			return errors.New("missing item")
+		}
+
+		return s.createItem(*event.Item, event.PreviousItemID)
+
+	case RealtimeEventTypeConversationItemDelete:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		index := slices.IndexFunc(s.items, func(i *RealtimeItem) bool { return i.ID == event.ItemID })
+
+		if index < 0 {
+			return errors.New("item not found: " + event.ItemID)
+		}
+
+		s.items = slices.Delete(s.items, index, index+1)
+
+		s.send(RealtimeEvent{Type: RealtimeEventTypeConversationItemDeleted, ItemID: event.ItemID})
+		return nil
+
+	case RealtimeEventTypeResponseCreate:
+		options := event.Response
+
+		s.enqueue(func(ctx context.Context) {
+			s.respond(ctx, options)
+		})
+
+		return nil
+
+	case RealtimeEventTypeResponseCancel:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if s.cancel == nil {
+			return errors.New("no active response found")
+		}
+
+		s.cancel(realtimeCancelClientCancelled)
+		return nil
+
+	default:
+		return errors.New("unsupported event type: " + string(event.Type))
+	}
+}
+
+func (s *realtimeSession) updateSession(data json.RawMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// running responses keep their copy, so nothing shared with it is decoded into
+	session := s.session
+
+	session.Modalities = slices.Clone(session.Modalities)
+	session.Tools = slices.Clone(session.Tools)
+
+	if session.InputAudioTranscription != nil {
+		session.InputAudioTranscription = to.Ptr(*session.InputAudioTranscription)
+	}
+
+	if session.OutputAudioSynthesis != nil {
+		session.OutputAudioSynthesis = to.Ptr(*session.OutputAudioSynthesis)
+	}
+
+	if session.TurnDetection != nil {
+		session.TurnDetection = to.Ptr(*session.TurnDetection)
+	}
+
+	if session.Temperature != nil {
+		session.Temperature = to.Ptr(*session.Temperature)
+	}
+
+	if session.MaxResponseOutputTokens != nil {
+		session.MaxResponseOutputTokens = to.Ptr(*session.MaxResponseOutputTokens)
+	}
+
+	if err := json.Unmarshal(data, &session); err != nil {
+		return err
+	}
+
+	if session.InputAudioFormat != "pcm16" || session.OutputAudioFormat != "pcm16" {
+		return errors.New("unsupported audio format, only pcm16 is supported")
+	}
+
+	for _, m := range session.Modalities {
+		if m != RealtimeModalityText && m != RealtimeModalityAudio {
+			return errors.New("unsupported modality: " + string(m))
+		}
+	}
+
+	if d := session.TurnDetection; d != nil && d.Type != "server_vad" {
+		return errors.New("unsupported turn detection: " + d.Type)
+	}
+
+	s.session = session
+
+	s.vad = nil
+	s.speech = ""
+
+	if session.TurnDetection != nil {
+		s.vad = newVoiceDetector(session.TurnDetection)
+	}
+
+	s.send(RealtimeEvent{Type: RealtimeEventTypeSessionUpdated, Session: to.Ptr(s.session)})
+	return nil
+}
+
+func (s *realtimeSession) appendAudio(audio []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.buffer = append(s.buffer, audio...)
+
+	if s.vad == nil {
+		return
+	}
+
+	detection := s.session.TurnDetection
+
+	for event := s.vad.Write(audio); event != voiceEventNone; event = s.vad.Write(nil) {
+		switch event {
+		case voiceEventStarted:
+			s.speech = realtimeID("item_")
+
+			start := max(s.vad.Position()-pcmMillis(s.vad.prefix), 0)
+
+			s.send(RealtimeEvent{Type: RealtimeEventTypeInputAudioBufferSpeechStarted, ItemID: s.speech, AudioStartMs: to.Ptr(start)})
+
+			if s.cancel != nil && (detection.InterruptResponse == nil || *detection.InterruptResponse) {
+				s.cancel(realtimeCancelTurnDetected)
+			}
+
+		case voiceEventStopped:
+			s.send(RealtimeEvent{Type: RealtimeEventTypeInputAudioBufferSpeechStopped, ItemID: s.speech, AudioEndMs: to.Ptr(s.vad.Position())})
+
+			if err := s.commit(s.speech, detection.CreateResponse == nil || *detection.CreateResponse); err != nil {
+				s.fail("", err)
+			}
+
+			s.speech = ""
+		}
+	}
+
+	// only the padding before the next turn is kept while nobody speaks
+	if s.speech == "" {
+		if keep := s.vad.Idle(); len(s.buffer) > keep {
+			s.buffer = slices.Clone(s.buffer[len(s.buffer)-keep:])
+		}
+	}
+}
+
+// commit turns the buffered audio into a user message, which is transcribed and optionally answered in the background
+func (s *realtimeSession) commit(id string, respond bool) error {
+	// audio not yet seen by the voice detector belongs to the next turn
+	var rest []byte
+
+	if s.vad != nil {
+		n := len(s.vad.pending)
+
+		rest = slices.Clone(s.buffer[len(s.buffer)-n:])
+		s.buffer = s.buffer[:len(s.buffer)-n]
+	}
+
+	audio := s.buffer
+	s.buffer = rest
+
+	if len(audio) < pcmBytes(100) {
+		return errors.New("buffer too small, expected at least 100ms of audio")
+	}
+
+	previous := ""
+
+	if len(s.items) > 0 {
+		previous = s.items[len(s.items)-1].ID
+	}
+
+	item := &RealtimeItem{
+		ID:     id,
+		Object: "realtime.item",
+
+		Type:   RealtimeItemTypeMessage,
+		Status: "completed",
+
+		Role: MessageRoleUser,
+
+		Content: []RealtimeContent{
+			{
+				Type: RealtimeContentTypeInputAudio,
+			},
+		},
+	}
+
+	s.items = append(s.items, item)
+
+	s.send(RealtimeEvent{Type: RealtimeEventTypeInputAudioBufferCommitted, ItemID: id, PreviousItemID: previous})
+	s.send(RealtimeEvent{Type: RealtimeEventTypeConversationItemCreated, Item: item, PreviousItemID: previous})
+
+	s.enqueue(func(ctx context.Context) {
+		s.transcribe(ctx, id, audio)
+	})
+
+	if respond {
+		s.enqueue(func(ctx context.Context) {
+			s.respond(ctx, nil)
+		})
+	}
+
+	return nil
+}
+
+func (s *realtimeSession) createItem(item RealtimeItem, previous string) error {
+	switch item.Type {
+	case RealtimeItemTypeMessage:
+		if item.Role != MessageRoleSystem && item.Role != MessageRoleUser && item.Role != MessageRoleAssistant {
+			return errors.New("invalid message role: " + string(item.Role))
+		}
+
+	case RealtimeItemTypeFunctionCall, RealtimeItemTypeFunctionCallOutput:
+		if item.CallID == "" {
+			return errors.New("missing call_id")
+		}
+
+	default:
+		return errors.New("invalid item type: " + string(item.Type))
+	}
+
+	if item.ID == "" {
+		item.ID = realtimeID("item_")
+	}
+
+	item.Object = "realtime.item"
+	item.Status = "completed"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	index := len(s.items)
+
+	if previous != "" {
+		index = slices.IndexFunc(s.items, func(i *RealtimeItem) bool { return i.ID == previous })
+
+		if index < 0 {
+			return errors.New("item not found: " + previous)
+		}
+
+		index++
+	}
+
+	if index > 0 {
+		previous = s.items[index-1].ID
+	}
+
+	s.items = slices.Insert(s.items, index, &item)
+
+	s.send(RealtimeEvent{Type: RealtimeEventTypeConversationItemCreated, Item: &item, PreviousItemID: previous})
+	return nil
+}
+
+func (s *realtimeSession) transcribe(ctx context.Context, id string, audio []byte) {
+	s.mu.Lock()
+	config := s.session.InputAudioTranscription
+	s.mu.Unlock()
+
+	var model, language string
+
+	if config != nil {
+		model = config.Model
+		language = config.Language
+	}
+
+	text, err := func() (string, error) {
+		transcriber, err := s.Transcriber(model)
+
+		if err != nil {
+			return "", err
+		}
+
+		transcription, err := transcriber.Transcribe(ctx, provider.File{
+			Name:        "audio.wav",
+			ContentType: "audio/wav",
+			Content:     bytes.NewReader(encodeWAV(audio)),
+		}, &provider.TranscribeOptions{
+			Language: language,
+		})
+
+		if err != nil {
+			return "", err
+		}
+
+		return strings.TrimSpace(transcription.Text), nil
+	}()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// the transcript is set even on failure, so the turn is not waited for again
+	for _, item := range s.items {
+		if item.ID == id {
+			item.Content[0].Transcript = to.Ptr(text)
+		}
+	}
+
+	if err != nil {
+		s.send(RealtimeEvent{
+			Type: RealtimeEventTypeConversationItemTranscriptionFailed,
+
+			ItemID:       id,
+			ContentIndex: to.Ptr(0),
+
+			Error: &RealtimeError{
+				Type:    "transcription_error",
+				Message: err.Error(),
+			},
+		})
+
+		return
+	}
+
+	s.send(RealtimeEvent{
+		Type: RealtimeEventTypeConversationItemTranscriptionCompleted,
+
+		ItemID:       id,
+		ContentIndex: to.Ptr(0),
+
+		Transcript: to.Ptr(text),
+	})
+}
+
+// enqueue runs a job after the previously queued ones
+func (s *realtimeSession) enqueue(job func(ctx context.Context)) {
+	s.queueMu.Lock()
+
+	previous := s.queue
+	done := make(chan struct{})
+
+	s.queue = done
+
+	s.queueMu.Unlock()
+
+	go func() {
+		defer close(done)
+
+		if previous != nil {
+			<-previous
+		}
+
+		if s.ctx.Err() != nil {
+			return
+		}
+
+		job(s.ctx)
+	}()
+}
+
+func (s *realtimeSession) send(event RealtimeEvent) error {
+	event.ID = realtimeID("event_")
+
+	data, err := json.Marshal(event)
+
+	if err != nil {
+		return err
+	}
+
+	return s.conn.Write(s.ctx, websocket.MessageText, data)
+}
+
+func (s *realtimeSession) fail(id string, err error) {
+	s.send(RealtimeEvent{
+		Type: RealtimeEventTypeError,
+
+		Error: &RealtimeError{
+			Type:    "invalid_request_error",
+			Message: err.Error(),
+
+			EventID: id,
+		},
+	})
+}
+
+func realtimeID(prefix string) string {
+	return prefix + strings.ReplaceAll(uuid.NewString(), "-", "")
+}
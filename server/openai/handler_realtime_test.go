@@ -0,0 +1,251 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adrianliechti/wingman/config"
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/server/openai"
+
+	"github.com/coder/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRealtime(t *testing.T) {
+	transcriber := &testTranscriber{text: " What time is it? "}
+	completer := &testCompleter{}
+
+	cfg := &config.Config{
+		Data: t.TempDir(),
+	}
+
+	cfg.RegisterCompleter("test", completer)
+	cfg.RegisterTranscriber("whisper", transcriber)
+
+	h, err := openai.New(cfg)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(server.URL, "http")+"/realtime?model=test", &websocket.DialOptions{
+		Subprotocols: []string{"realtime"},
+	})
+
+	require.NoError(t, err)
+	defer conn.CloseNow()
+
+	read := func() openai.RealtimeEvent {
+		_, data, err := conn.Read(ctx)
+		require.NoError(t, err)
+
+		var event openai.RealtimeEvent
+		require.NoError(t, json.Unmarshal(data, &event))
+
+		require.NotEqual(t, openai.RealtimeEventTypeError, event.Type, "%+v", event.Error)
+
+		return event
+	}
+
+	write := func(v any) {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+
+		require.NoError(t, conn.Write(ctx, websocket.MessageText, data))
+	}
+
+	created := read()
+	require.Equal(t, openai.RealtimeEventTypeSessionCreated, created.Type)
+	require.Equal(t, "test", created.Session.Model)
+	require.NotNil(t, created.Session.TurnDetection)
+
+	write(map[string]any{
+		"type": "session.update",
+
+		"session": map[string]any{
+			"modalities":   []string{"text"},
+			"instructions": "Be brief.",
+		},
+	})
+
+	updated := read()
+	require.Equal(t, openai.RealtimeEventTypeSessionUpdated, updated.Type)
+	require.Equal(t, "Be brief.", updated.Session.Instructions)
+
+	// a spoken turn followed by silence is committed and answered by the server
+	var audio []byte
+
+	for i := range realtimeBytes(400) / 2 {
+		v := int16(0.5 * math.MaxInt16 * math.Sin(2*math.Pi*440*float64(i)/24000))
+		audio = binary.LittleEndian.AppendUint16(audio, uint16(v))
+	}
+
+	audio = append(audio, make([]byte, realtimeBytes(600))...)
+
+	write(openai.RealtimeEvent{
+		Type:  openai.RealtimeEventTypeInputAudioBufferAppend,
+		Audio: base64.StdEncoding.EncodeToString(audio),
+	})
+
+	var events []openai.RealtimeEvent
+
+	for {
+		event := read()
+		events = append(events, event)
+
+		if event.Type == openai.RealtimeEventTypeResponseDone {
+			break
+		}
+	}
+
+	var types []openai.RealtimeEventType
+
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+
+	require.Equal(t, []openai.RealtimeEventType{
+		openai.RealtimeEventTypeInputAudioBufferSpeechStarted,
+		openai.RealtimeEventTypeInputAudioBufferSpeechStopped,
+		openai.RealtimeEventTypeInputAudioBufferCommitted,
+		openai.RealtimeEventTypeConversationItemCreated,
+		openai.RealtimeEventTypeConversationItemTranscriptionCompleted,
+		openai.RealtimeEventTypeResponseCreated,
+		openai.RealtimeEventTypeResponseOutputItemAdded,
+		openai.RealtimeEventTypeResponseContentPartAdded,
+		openai.RealtimeEventTypeResponseTextDelta,
+		openai.RealtimeEventTypeResponseTextDelta,
+		openai.RealtimeEventTypeResponseTextDone,
+		openai.RealtimeEventTypeResponseContentPartDone,
+		openai.RealtimeEventTypeResponseOutputItemDone,
+		openai.RealtimeEventTypeResponseDone,
+	}, types)
+
+	transcription := events[4]
+	require.Equal(t, events[2].ItemID, transcription.ItemID)
+	require.Equal(t, "What time is it?", *transcription.Transcript)
+
+	done := events[len(events)-1]
+	require.Equal(t, "completed", done.Response.Status)
+	require.Len(t, done.Response.Output, 1)
+	require.Equal(t, "It is noon.", done.Response.Output[0].Content[0].Text)
+
+	// the transcriber gets the committed turn as wav audio
+	require.Equal(t, "RIFF", string(transcriber.audio[:4]))
+	require.GreaterOrEqual(t, len(transcriber.audio), 44+realtimeBytes(400))
+
+	require.Equal(t, []provider.Message{
+		{Role: provider.MessageRoleSystem, Content: "Be brief."},
+		{Role: provider.MessageRoleUser, Content: "What time is it?"},
+	}, completer.messages)
+
+	// a text turn is answered on request, with the previous response in the conversation
+	write(map[string]any{
+		"type": "conversation.item.create",
+
+		"item": map[string]any{
+			"type": "message",
+			"role": "user",
+
+			"content": []map[string]any{
+				{"type": "input_text", "text": "Thanks!"},
+			},
+		},
+	})
+
+	require.Equal(t, openai.RealtimeEventTypeConversationItemCreated, read().Type)
+
+	write(openai.RealtimeEvent{Type: openai.RealtimeEventTypeResponseCreate})
+
+	for {
+		if read().Type == openai.RealtimeEventTypeResponseDone {
+			break
+		}
+	}
+
+	require.Equal(t, []provider.Message{
+		{Role: provider.MessageRoleSystem, Content: "Be brief."},
+		{Role: provider.MessageRoleUser, Content: "What time is it?"},
+		{Role: provider.MessageRoleAssistant, Content: "It is noon."},
+		{Role: provider.MessageRoleUser, Content: "Thanks!"},
+	}, completer.messages)
+
+	require.NoError(t, conn.Close(websocket.StatusNormalClosure, ""))
+}
+
+// realtimeBytes returns the size of 16-bit mono pcm audio at 24kHz
+func realtimeBytes(ms int) int {
+	return 24000 * ms / 1000 * 2
+}
+
+type testTranscriber struct {
+	text string
+
+	audio []byte
+}
+
+func (t *testTranscriber) Transcribe(ctx context.Context, input provider.File, options *provider.TranscribeOptions) (*provider.Transcription, error) {
+	data, err := io.ReadAll(input.Content)
+
+	if err != nil {
+		return nil, err
+	}
+
+	t.audio = data
+
+	return &provider.Transcription{
+		Text: t.text,
+	}, nil
+}
+
+type testCompleter struct {
+	mu sync.Mutex
+
+	messages []provider.Message
+}
+
+func (c *testCompleter) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	c.mu.Lock()
+	c.messages = messages
+	c.mu.Unlock()
+
+	for _, content := range []string{"It is ", "noon."} {
+		if options.Stream == nil {
+			break
+		}
+
+		if err := options.Stream(ctx, provider.Completion{
+			ID: "1",
+
+			Message: provider.Message{
+				Role:    provider.MessageRoleAssistant,
+				Content: content,
+			},
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return &provider.Completion{
+		ID:     "1",
+		Reason: provider.CompletionReasonStop,
+
+		Message: provider.Message{
+			Role:    provider.MessageRoleAssistant,
+			Content: "It is noon.",
+		},
+	}, nil
+}
@@ -0,0 +1,580 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/to"
+	"github.com/adrianliechti/wingman/server/sse"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func (h *Handler) handleResponse(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	item, ok := h.responses.Get(id)
+
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("response not found: "+id))
+		return
+	}
+
+	writeJson(w, item.Response)
+}
+
+func (h *Handler) handleResponseDelete(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if !h.responses.Delete(id) {
+		writeError(w, http.StatusNotFound, errors.New("response not found: "+id))
+		return
+	}
+
+	writeJson(w, ResponseDeleted{
+		Object: "response",
+
+		ID:      id,
+		Deleted: true,
+	})
+}
+
+func (h *Handler) handleResponseCreate(w http.ResponseWriter, r *http.Request) {
+	var req ResponseRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	completer, err := h.Completer(req.Model)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var history []provider.Message
+
+	if req.PreviousResponseID != "" {
+		previous, ok := h.responses.Get(req.PreviousResponseID)
+
+		if !ok {
+			writeError(w, http.StatusNotFound, errors.New("response not found: "+req.PreviousResponseID))
+			return
+		}
+
+		history = previous.Messages
+	}
+
+	input, err := toResponseMessages(req.Input)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	history = append(history, input...)
+
+	// instructions only apply to this response and are not carried over to the next one
+	var messages []provider.Message
+
+	if req.Instructions != "" {
+		messages = append(messages, provider.Message{
+			Role:    provider.MessageRoleSystem,
+			Content: req.Instructions,
+		})
+	}
+
+	messages = append(messages, cloneMessages(history)...)
+
+	tools, err := toResponseTools(req.Tools)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	options := &provider.CompleteOptions{
+		Tools: tools,
+
+		MaxTokens:   req.MaxOutputTokens,
+		Temperature: req.Temperature,
+	}
+
+	if req.Reasoning != nil {
+		switch req.Reasoning.Effort {
+		case ReasoningEffortLow:
+			options.Effort = provider.ReasoningEffortLow
+		case ReasoningEffortMedium:
+			options.Effort = provider.ReasoningEffortMedium
+		case ReasoningEffortHigh:
+			options.Effort = provider.ReasoningEffortHigh
+		}
+	}
+
+	if req.Text != nil && req.Text.Format != nil {
+		format := req.Text.Format
+
+		if format.Type == ResponseFormatJSONObject || format.Type == ResponseFormatJSONSchema {
+			options.Format = provider.CompletionFormatJSON
+		}
+
+		if format.Type == ResponseFormatJSONSchema {
+			options.Schema = &provider.Schema{
+				Name:        format.Name,
+				Description: format.Description,
+
+				Strict: format.Strict,
+				Schema: format.Schema,
+			}
+		}
+	}
+
+	response := Response{
+		Object: "response",
+
+		ID: "resp_" + newResponseID(),
+
+		Model:     req.Model,
+		CreatedAt: time.Now().Unix(),
+
+		Status: ResponseStatusInProgress,
+
+		Instructions:       req.Instructions,
+		PreviousResponseID: req.PreviousResponseID,
+
+		Output: []ResponseOutput{},
+
+		Tools: req.Tools,
+	}
+
+	if response.Tools == nil {
+		response.Tools = []ResponseTool{}
+	}
+
+	store := req.Store == nil || *req.Store
+
+	if req.Stream {
+		events, ctx := sse.New(w, r)
+		defer events.Close()
+
+		s := &responseStream{
+			events: events,
+
+			response: response,
+		}
+
+		if err := s.start(); err != nil {
+			return
+		}
+
+		options.Stream = func(ctx context.Context, completion provider.Completion) error {
+			return s.delta(completion.Message.Content)
+		}
+
+		completion, err := completer.Complete(ctx, messages, options)
+
+		if err != nil {
+			if ctx.Err() == nil {
+				s.fail(err)
+			}
+
+			return
+		}
+
+		if err := s.finish(completion); err != nil {
+			return
+		}
+
+		response = s.response
+	} else {
+		completion, err := completer.Complete(r.Context(), messages, options)
+
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		if completion.Message.Content != "" {
+			response.Output = append(response.Output, newResponseMessage(completion.Message.Content))
+		}
+
+		for _, c := range completion.Message.ToolCalls {
+			response.Output = append(response.Output, newResponseFunctionCall(c))
+		}
+
+		completeResponse(&response, completion)
+	}
+
+	if store {
+		h.responses.Set(response.ID, storedResponse{
+			Response: response,
+
+			Messages: append(history, toResponseOutputMessage(response.Output)),
+		})
+	}
+
+	if !req.Stream {
+		writeJson(w, response)
+	}
+}
+
+// responseStream writes the semantic events of a streamed response
+type responseStream struct {
+	events *sse.Stream
+
+	sequence int
+
+	response Response
+
+	text    strings.Builder
+	message *ResponseOutput
+}
+
+func (s *responseStream) start() error {
+	if err := s.send(ResponseEvent{Type: ResponseEventCreated, Response: to.Ptr(s.response)}); err != nil {
+		return err
+	}
+
+	return s.send(ResponseEvent{Type: ResponseEventInProgress, Response: to.Ptr(s.response)})
+}
+
+func (s *responseStream) delta(text string) error {
+	if text == "" {
+		return nil
+	}
+
+	if s.message == nil {
+		item := newResponseMessage("")
+		item.Status = ResponseStatusInProgress
+		item.Content = []ResponseOutputContent{}
+
+		s.message = &item
+
+		if err := s.send(ResponseEvent{Type: ResponseEventOutputItemAdded, OutputIndex: to.Ptr(0), Item: to.Ptr(item)}); err != nil {
+			return err
+		}
+
+		part := ResponseOutputContent{
+			Type:        ResponseContentTypeOutputText,
+			Annotations: []any{},
+		}
+
+		if err := s.send(ResponseEvent{Type: ResponseEventContentPartAdded, OutputIndex: to.Ptr(0), ContentIndex: to.Ptr(0), ItemID: item.ID, Part: &part}); err != nil {
+			return err
+		}
+	}
+
+	s.text.WriteString(text)
+
+	return s.send(ResponseEvent{Type: ResponseEventOutputTextDelta, OutputIndex: to.Ptr(0), ContentIndex: to.Ptr(0), ItemID: s.message.ID, Delta: text})
+}
+
+func (s *responseStream) finish(completion *provider.Completion) error {
+	// providers not streaming any text still produce the events for the final content
+	if s.message == nil {
+		if err := s.delta(completion.Message.Content); err != nil {
+			return err
+		}
+	}
+
+	if s.message != nil {
+		text := s.text.String()
+
+		if err := s.send(ResponseEvent{Type: ResponseEventOutputTextDone, OutputIndex: to.Ptr(0), ContentIndex: to.Ptr(0), ItemID: s.message.ID, Text: text}); err != nil {
+			return err
+		}
+
+		part := ResponseOutputContent{
+			Type: ResponseContentTypeOutputText,
+			Text: text,
+
+			Annotations: []any{},
+		}
+
+		if err := s.send(ResponseEvent{Type: ResponseEventContentPartDone, OutputIndex: to.Ptr(0), ContentIndex: to.Ptr(0), ItemID: s.message.ID, Part: &part}); err != nil {
+			return err
+		}
+
+		item := *s.message
+		item.Status = ResponseStatusCompleted
+		item.Content = []ResponseOutputContent{part}
+
+		if err := s.send(ResponseEvent{Type: ResponseEventOutputItemDone, OutputIndex: to.Ptr(0), Item: &item}); err != nil {
+			return err
+		}
+
+		s.response.Output = append(s.response.Output, item)
+	}
+
+	// tool calls arrive in provider specific fragments, so they are emitted once complete
+	for _, c := range completion.Message.ToolCalls {
+		index := len(s.response.Output)
+
+		item := newResponseFunctionCall(c)
+
+		added := item
+		added.Status = ResponseStatusInProgress
+		added.Arguments = ""
+
+		if err := s.send(ResponseEvent{Type: ResponseEventOutputItemAdded, OutputIndex: to.Ptr(index), Item: &added}); err != nil {
+			return err
+		}
+
+		if err := s.send(ResponseEvent{Type: ResponseEventFunctionCallArgumentsDelta, OutputIndex: to.Ptr(index), ItemID: item.ID, Delta: item.Arguments}); err != nil {
+			return err
+		}
+
+		if err := s.send(ResponseEvent{Type: ResponseEventFunctionCallArgumentsDone, OutputIndex: to.Ptr(index), ItemID: item.ID, Arguments: item.Arguments}); err != nil {
+			return err
+		}
+
+		if err := s.send(ResponseEvent{Type: ResponseEventOutputItemDone, OutputIndex: to.Ptr(index), Item: to.Ptr(item)}); err != nil {
+			return err
+		}
+
+		s.response.Output = append(s.response.Output, item)
+	}
+
+	completeResponse(&s.response, completion)
+
+	event := ResponseEventCompleted
+
+	if s.response.Status == ResponseStatusIncomplete {
+		event = ResponseEventIncomplete
+	}
+
+	return s.send(ResponseEvent{Type: event, Response: to.Ptr(s.response)})
+}
+
+func (s *responseStream) fail(err error) error {
+	s.response.Status = ResponseStatusFailed
+
+	s.response.Error = &ResponseError{
+		Code:    "server_error",
+		Message: err.Error(),
+	}
+
+	return s.send(ResponseEvent{Type: ResponseEventFailed, Response: to.Ptr(s.response)})
+}
+
+func (s *responseStream) send(event ResponseEvent) error {
+	event.SequenceNumber = s.sequence
+	s.sequence++
+
+	return s.events.Send(string(event.Type), event)
+}
+
+func completeResponse(response *Response, completion *provider.Completion) {
+	response.Status = ResponseStatusCompleted
+
+	switch completion.Reason {
+	case provider.CompletionReasonLength:
+		response.Status = ResponseStatusIncomplete
+		response.IncompleteDetails = &ResponseIncompleteDetails{Reason: "max_output_tokens"}
+
+	case provider.CompletionReasonFilter:
+		response.Status = ResponseStatusIncomplete
+		response.IncompleteDetails = &ResponseIncompleteDetails{Reason: "content_filter"}
+	}
+
+	if completion.Usage != nil {
+		response.Usage = &ResponseUsage{
+			InputTokens:  completion.Usage.InputTokens,
+			OutputTokens: completion.Usage.OutputTokens,
+			TotalTokens:  completion.Usage.InputTokens + completion.Usage.OutputTokens,
+		}
+	}
+}
+
+func newResponseMessage(text string) ResponseOutput {
+	return ResponseOutput{
+		Type: ResponseItemTypeMessage,
+
+		ID:     "msg_" + newResponseID(),
+		Status: ResponseStatusCompleted,
+
+		Role: MessageRoleAssistant,
+
+		Content: []ResponseOutputContent{
+			{
+				Type: ResponseContentTypeOutputText,
+				Text: text,
+
+				Annotations: []any{},
+			},
+		},
+	}
+}
+
+func newResponseFunctionCall(call provider.ToolCall) ResponseOutput {
+	id := call.ID
+
+	if id == "" {
+		id = "call_" + newResponseID()
+	}
+
+	return ResponseOutput{
+		Type: ResponseItemTypeFunctionCall,
+
+		ID:     "fc_" + newResponseID(),
+		Status: ResponseStatusCompleted,
+
+		CallID:    id,
+		Name:      call.Name,
+		Arguments: call.Arguments,
+	}
+}
+
+func newResponseID() string {
+	return strings.ReplaceAll(uuid.NewString(), "-", "")
+}
+
+func toResponseMessages(items []ResponseInputItem) ([]provider.Message, error) {
+	var result []provider.Message
+
+	for _, item := range items {
+		switch item.Type {
+		case "", ResponseItemTypeMessage:
+			message := provider.Message{
+				Role: toMessageRole(item.Role),
+			}
+
+			if message.Role == "" {
+				return nil, errors.New("invalid message role: " + string(item.Role))
+			}
+
+			for _, c := range item.Content {
+				switch c.Type {
+				case ResponseContentTypeInputText, ResponseContentTypeOutputText, ResponseContentTypeRefusal:
+					text := c.Text
+
+					if c.Type == ResponseContentTypeRefusal {
+						text = c.Refusal
+					}
+
+					if len(message.Content) > 0 {
+						message.Content += "\n\n"
+					}
+
+					message.Content += text
+
+				case ResponseContentTypeInputImage, ResponseContentTypeInputFile:
+					url := c.ImageURL
+
+					if url == "" {
+						url = c.FileURL
+					}
+
+					if url == "" {
+						url = c.FileData
+					}
+
+					file, err := toFile(url)
+
+					if err != nil {
+						return nil, err
+					}
+
+					if c.Filename != "" {
+						file.Name = c.Filename
+					}
+
+					message.Files = append(message.Files, *file)
+
+				default:
+					return nil, errors.New("unsupported content type: " + string(c.Type))
+				}
+			}
+
+			result = append(result, message)
+
+		case ResponseItemTypeFunctionCall:
+			call := provider.ToolCall{
+				ID: item.CallID,
+
+				Name:      item.Name,
+				Arguments: item.Arguments,
+			}
+
+			// consecutive calls belong to the same assistant turn
+			if n := len(result); n > 0 && result[n-1].Role == provider.MessageRoleAssistant {
+				result[n-1].ToolCalls = append(result[n-1].ToolCalls, call)
+				continue
+			}
+
+			result = append(result, provider.Message{
+				Role:      provider.MessageRoleAssistant,
+				ToolCalls: []provider.ToolCall{call},
+			})
+
+		case ResponseItemTypeFunctionCallOutput:
+			result = append(result, provider.Message{
+				Role:    provider.MessageRoleTool,
+				Content: item.Output,
+
+				Tool: item.CallID,
+			})
+
+		case ResponseItemTypeReasoning:
+			continue
+
+		default:
+			return nil, errors.New("unsupported input item type: " + string(item.Type))
+		}
+	}
+
+	return result, nil
+}
+
+// toResponseOutputMessage converts the output items into the assistant message continuing the conversation
+func toResponseOutputMessage(output []ResponseOutput) provider.Message {
+	message := provider.Message{
+		Role: provider.MessageRoleAssistant,
+	}
+
+	for _, item := range output {
+		switch item.Type {
+		case ResponseItemTypeMessage:
+			for _, c := range item.Content {
+				message.Content += c.Text
+			}
+
+		case ResponseItemTypeFunctionCall:
+			message.ToolCalls = append(message.ToolCalls, provider.ToolCall{
+				ID: item.CallID,
+
+				Name:      item.Name,
+				Arguments: item.Arguments,
+			})
+		}
+	}
+
+	return message
+}
+
+func toResponseTools(tools []ResponseTool) ([]provider.Tool, error) {
+	var result []provider.Tool
+
+	for _, t := range tools {
+		if t.Type != ToolTypeFunction {
+			return nil, errors.New("unsupported tool type: " + string(t.Type))
+		}
+
+		result = append(result, provider.Tool{
+			Name:        t.Name,
+			Description: t.Description,
+
+			Parameters: t.Parameters,
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,231 @@
+package openai_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/adrianliechti/wingman/config"
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/server/openai"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponse(t *testing.T) {
+	completer := &testCompleter{}
+	h := newResponsesHandler(t, completer)
+
+	response := createResponse(t, h, `{"model": "test", "instructions": "Be brief.", "input": "What time is it?"}`)
+
+	require.Equal(t, openai.ResponseStatusCompleted, response.Status)
+	require.Len(t, response.Output, 1)
+	require.Equal(t, openai.ResponseItemTypeMessage, response.Output[0].Type)
+	require.Equal(t, "It is noon.", response.Output[0].Content[0].Text)
+
+	require.Equal(t, []provider.Message{
+		{Role: provider.MessageRoleSystem, Content: "Be brief."},
+		{Role: provider.MessageRoleUser, Content: "What time is it?"},
+	}, completer.messages)
+
+	// stored responses can be retrieved and deleted
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/responses/"+response.ID, nil))
+
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodDelete, "/responses/"+response.ID, nil))
+
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/responses/"+response.ID, nil))
+
+	require.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestResponseStream(t *testing.T) {
+	h := newResponsesHandler(t, &testCompleter{})
+
+	events := streamResponse(t, h, `{"model": "test", "stream": true, "input": "What time is it?"}`)
+
+	var types []openai.ResponseEventType
+
+	for i, e := range events {
+		require.Equal(t, i, e.SequenceNumber)
+		types = append(types, e.Type)
+	}
+
+	require.Equal(t, []openai.ResponseEventType{
+		openai.ResponseEventCreated,
+		openai.ResponseEventInProgress,
+		openai.ResponseEventOutputItemAdded,
+		openai.ResponseEventContentPartAdded,
+		openai.ResponseEventOutputTextDelta,
+		openai.ResponseEventOutputTextDelta,
+		openai.ResponseEventOutputTextDone,
+		openai.ResponseEventContentPartDone,
+		openai.ResponseEventOutputItemDone,
+		openai.ResponseEventCompleted,
+	}, types)
+
+	require.Equal(t, "It is ", events[4].Delta)
+	require.Equal(t, "It is noon.", events[6].Text)
+
+	completed := events[len(events)-1].Response
+
+	require.Equal(t, openai.ResponseStatusCompleted, completed.Status)
+	require.Len(t, completed.Output, 1)
+	require.Equal(t, events[2].Item.ID, completed.Output[0].ID)
+	require.Equal(t, "It is noon.", completed.Output[0].Content[0].Text)
+}
+
+func TestResponseStreamToolCall(t *testing.T) {
+	h := newResponsesHandler(t, &toolCompleter{})
+
+	events := streamResponse(t, h, `{"model": "test", "stream": true, "input": "Weather in Bern?", "tools": [{"type": "function", "name": "weather"}]}`)
+
+	var types []openai.ResponseEventType
+
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+
+	require.Equal(t, []openai.ResponseEventType{
+		openai.ResponseEventCreated,
+		openai.ResponseEventInProgress,
+		openai.ResponseEventOutputItemAdded,
+		openai.ResponseEventFunctionCallArgumentsDelta,
+		openai.ResponseEventFunctionCallArgumentsDone,
+		openai.ResponseEventOutputItemDone,
+		openai.ResponseEventCompleted,
+	}, types)
+
+	call := events[5].Item
+
+	require.Equal(t, openai.ResponseItemTypeFunctionCall, call.Type)
+	require.Equal(t, "call_1", call.CallID)
+	require.Equal(t, "weather", call.Name)
+	require.Equal(t, `{"city":"Bern"}`, call.Arguments)
+}
+
+func TestResponsePrevious(t *testing.T) {
+	completer := &toolCompleter{}
+	h := newResponsesHandler(t, completer)
+
+	first := createResponse(t, h, `{"model": "test", "instructions": "Be brief.", "input": "Weather in Bern?", "tools": [{"type": "function", "name": "weather"}]}`)
+
+	require.Len(t, first.Output, 1)
+	require.Equal(t, "call_1", first.Output[0].CallID)
+
+	// the tool output continues the stored conversation, without the instructions of the first response
+	second := createResponse(t, h, `{"model": "test", "previous_response_id": "`+first.ID+`", "input": [{"type": "function_call_output", "call_id": "call_1", "output": "sunny"}]}`)
+
+	require.Equal(t, first.ID, second.PreviousResponseID)
+	require.Equal(t, "It is sunny.", second.Output[0].Content[0].Text)
+
+	require.Equal(t, []provider.Message{
+		{Role: provider.MessageRoleUser, Content: "Weather in Bern?"},
+		{Role: provider.MessageRoleAssistant, ToolCalls: []provider.ToolCall{{ID: "call_1", Name: "weather", Arguments: `{"city":"Bern"}`}}},
+		{Role: provider.MessageRoleTool, Content: "sunny", Tool: "call_1"},
+	}, completer.messages)
+
+	// responses not stored cannot be continued
+	third := createResponse(t, h, `{"model": "test", "store": false, "input": "Weather in Bern?"}`)
+
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/responses", strings.NewReader(`{"model": "test", "previous_response_id": "`+third.ID+`", "input": "Thanks"}`)))
+
+	require.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func newResponsesHandler(t *testing.T, completer provider.Completer) *openai.Handler {
+	cfg := &config.Config{
+		Data: t.TempDir(),
+	}
+
+	cfg.RegisterCompleter("test", completer)
+
+	h, err := openai.New(cfg)
+	require.NoError(t, err)
+
+	return h
+}
+
+func createResponse(t *testing.T, h http.Handler, body string) openai.Response {
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/responses", strings.NewReader(body)))
+
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	var response openai.Response
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &response))
+
+	return response
+}
+
+// streamResponse returns the events of a streamed response
+func streamResponse(t *testing.T, h http.Handler, body string) []openai.ResponseEvent {
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/responses", strings.NewReader(body)))
+
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var result []openai.ResponseEvent
+
+	scanner := bufio.NewScanner(resp.Body)
+
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+
+		if !ok {
+			continue
+		}
+
+		var event openai.ResponseEvent
+		require.NoError(t, json.Unmarshal([]byte(data), &event))
+
+		result = append(result, event)
+	}
+
+	return result
+}
+
+// toolCompleter calls the weather tool, and answers once its result is in the conversation
+type toolCompleter struct {
+	messages []provider.Message
+}
+
+func (c *toolCompleter) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) (*provider.Completion, error) {
+	c.messages = messages
+
+	if last := messages[len(messages)-1]; last.Role == provider.MessageRoleTool {
+		return &provider.Completion{
+			ID:     "2",
+			Reason: provider.CompletionReasonStop,
+
+			Message: provider.Message{
+				Role:    provider.MessageRoleAssistant,
+				Content: "It is " + last.Content + ".",
+			},
+		}, nil
+	}
+
+	return &provider.Completion{
+		ID:     "1",
+		Reason: provider.CompletionReasonTool,
+
+		Message: provider.Message{
+			Role: provider.MessageRoleAssistant,
+
+			ToolCalls: []provider.ToolCall{
+				{ID: "call_1", Name: "weather", Arguments: `{"city":"Bern"}`},
+			},
+		},
+	}, nil
+}
@@ -12,6 +12,13 @@ type Model struct {
 	ID      string `json:"id"`
 	Created int64  `json:"created"`
 	OwnedBy string `json:"owned_by"`
+
+	Type string `json:"type,omitempty"`
+
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	Capabilities []string `json:"capabilities,omitempty"`
 }
 
 // https://platform.openai.com/docs/api-reference/models
@@ -83,10 +90,79 @@ type EmbeddingList struct {
 	Usage *Usage `json:"usage,omitempty"`
 }
 
+// https://platform.openai.com/docs/api-reference/moderations/create
+type ModerationRequest struct {
+	Model string `json:"model"`
+
+	Input []string `json:"-"`
+}
+
+func (r *ModerationRequest) UnmarshalJSON(data []byte) error {
+	var req struct {
+		Model string `json:"model"`
+
+		Input json.RawMessage `json:"input"`
+	}
+
+	if err := json.Unmarshal(data, &req); err != nil {
+		return err
+	}
+
+	*r = ModerationRequest{
+		Model: req.Model,
+	}
+
+	var text string
+
+	if err := json.Unmarshal(req.Input, &text); err == nil {
+		r.Input = []string{text}
+		return nil
+	}
+
+	var texts []string
+
+	if err := json.Unmarshal(req.Input, &texts); err == nil {
+		r.Input = texts
+		return nil
+	}
+
+	var contents []MessageContent
+
+	if err := json.Unmarshal(req.Input, &contents); err != nil {
+		return errors.New("invalid input")
+	}
+
+	for _, c := range contents {
+		if c.Type != MessageContentTypeText {
+			return errors.New("unsupported input type: " + string(c.Type))
+		}
+
+		r.Input = append(r.Input, c.Text)
+	}
+
+	return nil
+}
+
+// https://platform.openai.com/docs/api-reference/moderations/object
+type ModerationResponse struct {
+	ID string `json:"id"`
+
+	Model   string             `json:"model"`
+	Results []ModerationResult `json:"results"`
+}
+
+type ModerationResult struct {
+	Flagged bool `json:"flagged"`
+
+	Categories     map[string]bool    `json:"categories"`
+	CategoryScores map[string]float64 `json:"category_scores"`
+}
+
 type MessageRole string
 
 var (
 	MessageRoleSystem    MessageRole = "system"
+	MessageRoleDeveloper MessageRole = "developer"
 	MessageRoleUser      MessageRole = "user"
 	MessageRoleAssistant MessageRole = "assistant"
 	MessageRoleTool      MessageRole = "tool"
@@ -128,24 +204,88 @@ type ChatCompletionRequest struct {
 
 	ResponseFormat *ChatCompletionResponseFormat `json:"response_format,omitempty"`
 
-	// frequency_penalty *float32
-	// presence_penalty *float32
+	ToolChoice        *ToolChoice `json:"tool_choice,omitempty"`
+	ParallelToolCalls *bool       `json:"parallel_tool_calls,omitempty"`
 
-	// logit_bias
-	// logprobs *bool
-	// top_logprobs *int
+	TopP *float32 `json:"top_p,omitempty"`
 
-	// n *int
+	PresencePenalty  *float32 `json:"presence_penalty,omitempty"`
+	FrequencyPenalty *float32 `json:"frequency_penalty,omitempty"`
 
-	// seed *int
+	Seed *int `json:"seed,omitempty"`
 
-	// top_p *float32
+	Logprobs    bool `json:"logprobs,omitempty"`
+	TopLogprobs *int `json:"top_logprobs,omitempty"`
+
+	N *int `json:"n,omitempty"`
 
-	// tool_choice string: none, auto
+	StreamOptions *StreamOptions `json:"stream_options,omitempty"`
+
+	// logit_bias
 
 	// user string
 }
 
+type StreamOptions struct {
+	IncludeUsage bool `json:"include_usage,omitempty"`
+}
+
+type ToolChoiceMode string
+
+var (
+	ToolChoiceModeNone     ToolChoiceMode = "none"
+	ToolChoiceModeAuto     ToolChoiceMode = "auto"
+	ToolChoiceModeRequired ToolChoiceMode = "required"
+)
+
+// ToolChoice is either a mode or a specific function to call
+type ToolChoice struct {
+	Mode ToolChoiceMode
+
+	Type     ToolType
+	Function *ToolChoiceFunction
+}
+
+type ToolChoiceFunction struct {
+	Name string `json:"name"`
+}
+
+func (c *ToolChoice) UnmarshalJSON(data []byte) error {
+	var mode string
+
+	if err := json.Unmarshal(data, &mode); err == nil {
+		*c = ToolChoice{Mode: ToolChoiceMode(mode)}
+		return nil
+	}
+
+	var value struct {
+		Type     ToolType            `json:"type"`
+		Function *ToolChoiceFunction `json:"function"`
+	}
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	*c = ToolChoice{
+		Type:     value.Type,
+		Function: value.Function,
+	}
+
+	return nil
+}
+
+func (c ToolChoice) MarshalJSON() ([]byte, error) {
+	if c.Function == nil {
+		return json.Marshal(c.Mode)
+	}
+
+	return json.Marshal(struct {
+		Type     ToolType            `json:"type"`
+		Function *ToolChoiceFunction `json:"function"`
+	}{c.Type, c.Function})
+}
+
 type ReasoningEffort string
 
 var (
@@ -190,9 +330,31 @@ type ChatCompletionChoice struct {
 	Delta   *ChatCompletionMessage `json:"delta,omitempty"`
 	Message *ChatCompletionMessage `json:"message,omitempty"`
 
+	Logprobs *Logprobs `json:"logprobs,omitempty"`
+
 	FinishReason *FinishReason `json:"finish_reason"`
 }
 
+type Logprobs struct {
+	Content []TokenLogprob `json:"content"`
+}
+
+type TokenLogprob struct {
+	Token   string  `json:"token"`
+	Logprob float64 `json:"logprob"`
+
+	Bytes []int `json:"bytes"`
+
+	TopLogprobs []TopLogprob `json:"top_logprobs"`
+}
+
+type TopLogprob struct {
+	Token   string  `json:"token"`
+	Logprob float64 `json:"logprob"`
+
+	Bytes []int `json:"bytes"`
+}
+
 // https://platform.openai.com/docs/api-reference/chat/object
 type ChatCompletionMessage struct {
 	Role MessageRole `json:"role,omitempty"`
@@ -200,6 +362,8 @@ type ChatCompletionMessage struct {
 	Content  string           `json:"content"`
 	Contents []MessageContent `json:"-"`
 
+	ReasoningContent string `json:"reasoning_content,omitempty"` // non-standard
+
 	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
 	ToolCallID string     `json:"tool_call_id,omitempty"`
 }
@@ -237,6 +401,8 @@ func (m *ChatCompletionMessage) MarshalJSON() ([]byte, error) {
 			Content  string           `json:"-"`
 			Contents []MessageContent `json:"content,omitempty"`
 
+			ReasoningContent string `json:"reasoning_content,omitempty"`
+
 			ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
 			ToolCallID string     `json:"tool_call_id,omitempty"`
 		}(*m)
@@ -249,6 +415,8 @@ func (m *ChatCompletionMessage) MarshalJSON() ([]byte, error) {
 			Content  string           `json:"content"`
 			Contents []MessageContent `json:"-"`
 
+			ReasoningContent string `json:"reasoning_content,omitempty"`
+
 			ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
 			ToolCallID string     `json:"tool_call_id,omitempty"`
 		}(*m)
@@ -264,6 +432,8 @@ func (m *ChatCompletionMessage) UnmarshalJSON(data []byte) error {
 		Content  string `json:"content"`
 		Contents []MessageContent
 
+		ReasoningContent string `json:"reasoning_content,omitempty"`
+
 		ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
 		ToolCallID string     `json:"tool_call_id,omitempty"`
 	}{}
@@ -279,6 +449,8 @@ func (m *ChatCompletionMessage) UnmarshalJSON(data []byte) error {
 		Content  string
 		Contents []MessageContent `json:"content"`
 
+		ReasoningContent string `json:"reasoning_content,omitempty"`
+
 		ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
 		ToolCallID string     `json:"tool_call_id,omitempty"`
 	}{}
@@ -391,3 +563,695 @@ type Error struct {
 	Type    string `json:"type"`
 	Message string `json:"message"`
 }
+
+// https://platform.openai.com/docs/api-reference/responses/create
+type ResponseRequest struct {
+	Model string `json:"model"`
+
+	Input        ResponseInput `json:"input"`
+	Instructions string        `json:"instructions,omitempty"`
+
+	PreviousResponseID string `json:"previous_response_id,omitempty"`
+
+	Stream bool  `json:"stream,omitempty"`
+	Store  *bool `json:"store,omitempty"`
+
+	Tools []ResponseTool `json:"tools,omitempty"`
+
+	MaxOutputTokens *int     `json:"max_output_tokens,omitempty"`
+	Temperature     *float32 `json:"temperature,omitempty"`
+
+	Reasoning *ResponseReasoning `json:"reasoning,omitempty"`
+	Text      *ResponseText      `json:"text,omitempty"`
+
+	// tool_choice string | object
+	// parallel_tool_calls *bool
+
+	// top_p *float32
+	// truncation string
+
+	// metadata map[string]string
+	// user string
+}
+
+// ResponseInput accepts either a plain text or a list of input items
+type ResponseInput []ResponseInputItem
+
+func (i *ResponseInput) UnmarshalJSON(data []byte) error {
+	var text string
+
+	if err := json.Unmarshal(data, &text); err == nil {
+		*i = ResponseInput{
+			{
+				Type: ResponseItemTypeMessage,
+				Role: MessageRoleUser,
+
+				Content: ResponseInputContent{
+					{
+						Type: ResponseContentTypeInputText,
+						Text: text,
+					},
+				},
+			},
+		}
+
+		return nil
+	}
+
+	var items []ResponseInputItem
+
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+
+	*i = items
+	return nil
+}
+
+type ResponseItemType string
+
+var (
+	ResponseItemTypeMessage            ResponseItemType = "message"
+	ResponseItemTypeFunctionCall       ResponseItemType = "function_call"
+	ResponseItemTypeFunctionCallOutput ResponseItemType = "function_call_output"
+	ResponseItemTypeReasoning          ResponseItemType = "reasoning"
+)
+
+// https://platform.openai.com/docs/api-reference/responses/create#responses-create-input
+type ResponseInputItem struct {
+	Type ResponseItemType `json:"type,omitempty"`
+
+	Role    MessageRole          `json:"role,omitempty"`
+	Content ResponseInputContent `json:"content,omitempty"`
+
+	CallID    string `json:"call_id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
+	Output    string `json:"output,omitempty"`
+}
+
+// ResponseInputContent accepts either a plain text or a list of content parts
+type ResponseInputContent []ResponseInputPart
+
+func (c *ResponseInputContent) UnmarshalJSON(data []byte) error {
+	var text string
+
+	if err := json.Unmarshal(data, &text); err == nil {
+		*c = ResponseInputContent{
+			{
+				Type: ResponseContentTypeInputText,
+				Text: text,
+			},
+		}
+
+		return nil
+	}
+
+	var parts []ResponseInputPart
+
+	if err := json.Unmarshal(data, &parts); err != nil {
+		return err
+	}
+
+	*c = parts
+	return nil
+}
+
+type ResponseContentType string
+
+var (
+	ResponseContentTypeInputText  ResponseContentType = "input_text"
+	ResponseContentTypeInputImage ResponseContentType = "input_image"
+	ResponseContentTypeInputFile  ResponseContentType = "input_file"
+
+	ResponseContentTypeOutputText ResponseContentType = "output_text"
+	ResponseContentTypeRefusal    ResponseContentType = "refusal"
+)
+
+type ResponseInputPart struct {
+	Type ResponseContentType `json:"type"`
+
+	Text    string `json:"text,omitempty"`
+	Refusal string `json:"refusal,omitempty"`
+
+	ImageURL string `json:"image_url,omitempty"`
+
+	FileURL  string `json:"file_url,omitempty"`
+	FileData string `json:"file_data,omitempty"`
+	Filename string `json:"filename,omitempty"`
+}
+
+// https://platform.openai.com/docs/api-reference/responses/create#responses-create-tools
+type ResponseTool struct {
+	Type ToolType `json:"type"`
+
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	Strict *bool `json:"strict,omitempty"`
+
+	Parameters map[string]any `json:"parameters,omitempty"`
+}
+
+type ResponseReasoning struct {
+	Effort ReasoningEffort `json:"effort,omitempty"`
+}
+
+type ResponseText struct {
+	Format *ResponseTextFormat `json:"format,omitempty"`
+}
+
+type ResponseTextFormat struct {
+	Type ResponseFormat `json:"type"`
+
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	Strict *bool `json:"strict,omitempty"`
+
+	Schema map[string]any `json:"schema,omitempty"`
+}
+
+type ResponseStatus string
+
+var (
+	ResponseStatusInProgress ResponseStatus = "in_progress"
+	ResponseStatusCompleted  ResponseStatus = "completed"
+	ResponseStatusIncomplete ResponseStatus = "incomplete"
+	ResponseStatusFailed     ResponseStatus = "failed"
+)
+
+// https://platform.openai.com/docs/api-reference/responses/object
+type Response struct {
+	Object string `json:"object"` // "response"
+
+	ID string `json:"id"`
+
+	Model     string `json:"model"`
+	CreatedAt int64  `json:"created_at"`
+
+	Status ResponseStatus `json:"status"`
+
+	Instructions       string `json:"instructions,omitempty"`
+	PreviousResponseID string `json:"previous_response_id,omitempty"`
+
+	Output []ResponseOutput `json:"output"`
+
+	Tools []ResponseTool `json:"tools"`
+
+	Error             *ResponseError             `json:"error"`
+	IncompleteDetails *ResponseIncompleteDetails `json:"incomplete_details"`
+
+	Usage *ResponseUsage `json:"usage,omitempty"`
+}
+
+// https://platform.openai.com/docs/api-reference/responses/object#responses/object-output
+type ResponseOutput struct {
+	Type ResponseItemType `json:"type"`
+
+	ID     string         `json:"id"`
+	Status ResponseStatus `json:"status"`
+
+	Role    MessageRole             `json:"role,omitempty"`
+	Content []ResponseOutputContent `json:"content,omitempty"`
+
+	CallID    string `json:"call_id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
+}
+
+type ResponseOutputContent struct {
+	Type ResponseContentType `json:"type"`
+
+	Text string `json:"text"`
+
+	Annotations []any `json:"annotations"`
+}
+
+type ResponseError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type ResponseIncompleteDetails struct {
+	Reason string `json:"reason"`
+}
+
+type ResponseUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+	TotalTokens  int `json:"total_tokens"`
+}
+
+// https://platform.openai.com/docs/api-reference/responses/delete
+type ResponseDeleted struct {
+	Object string `json:"object"` // "response"
+
+	ID      string `json:"id"`
+	Deleted bool   `json:"deleted"`
+}
+
+type ResponseEventType string
+
+var (
+	ResponseEventCreated    ResponseEventType = "response.created"
+	ResponseEventInProgress ResponseEventType = "response.in_progress"
+	ResponseEventCompleted  ResponseEventType = "response.completed"
+	ResponseEventIncomplete ResponseEventType = "response.incomplete"
+	ResponseEventFailed     ResponseEventType = "response.failed"
+
+	ResponseEventOutputItemAdded ResponseEventType = "response.output_item.added"
+	ResponseEventOutputItemDone  ResponseEventType = "response.output_item.done"
+
+	ResponseEventContentPartAdded ResponseEventType = "response.content_part.added"
+	ResponseEventContentPartDone  ResponseEventType = "response.content_part.done"
+
+	ResponseEventOutputTextDelta ResponseEventType = "response.output_text.delta"
+	ResponseEventOutputTextDone  ResponseEventType = "response.output_text.done"
+
+	ResponseEventFunctionCallArgumentsDelta ResponseEventType = "response.function_call_arguments.delta"
+	ResponseEventFunctionCallArgumentsDone  ResponseEventType = "response.function_call_arguments.done"
+)
+
+// https://platform.openai.com/docs/api-reference/responses-streaming
+type ResponseEvent struct {
+	Type ResponseEventType `json:"type"`
+
+	SequenceNumber int `json:"sequence_number"`
+
+	Response *Response `json:"response,omitempty"`
+
+	OutputIndex  *int   `json:"output_index,omitempty"`
+	ContentIndex *int   `json:"content_index,omitempty"`
+	ItemID       string `json:"item_id,omitempty"`
+
+	Item *ResponseOutput        `json:"item,omitempty"`
+	Part *ResponseOutputContent `json:"part,omitempty"`
+
+	Delta     string `json:"delta,omitempty"`
+	Text      string `json:"text,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
+}
+
+type FilePurpose string
+
+var (
+	FilePurposeBatch       FilePurpose = "batch"
+	FilePurposeBatchOutput FilePurpose = "batch_output"
+	FilePurposeAssistants  FilePurpose = "assistants"
+	FilePurposeUserData    FilePurpose = "user_data"
+)
+
+// https://platform.openai.com/docs/api-reference/files/object
+type File struct {
+	Object string `json:"object"` // "file"
+
+	ID string `json:"id"`
+
+	Bytes     int64  `json:"bytes"`
+	CreatedAt int64  `json:"created_at"`
+	Filename  string `json:"filename"`
+
+	Purpose FilePurpose `json:"purpose"`
+}
+
+// https://platform.openai.com/docs/api-reference/files/list
+type FileList struct {
+	Object string `json:"object"` // "list"
+
+	Files []File `json:"data"`
+}
+
+// https://platform.openai.com/docs/api-reference/files/delete
+type FileDeleted struct {
+	Object string `json:"object"` // "file"
+
+	ID      string `json:"id"`
+	Deleted bool   `json:"deleted"`
+}
+
+// https://platform.openai.com/docs/api-reference/batch/create
+type BatchRequest struct {
+	InputFileID string `json:"input_file_id"`
+
+	Endpoint         string `json:"endpoint"`
+	CompletionWindow string `json:"completion_window"`
+
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+type BatchStatus string
+
+var (
+	BatchStatusValidating BatchStatus = "validating"
+	BatchStatusFailed     BatchStatus = "failed"
+	BatchStatusInProgress BatchStatus = "in_progress"
+	BatchStatusFinalizing BatchStatus = "finalizing"
+	BatchStatusCompleted  BatchStatus = "completed"
+	BatchStatusExpired    BatchStatus = "expired"
+	BatchStatusCancelling BatchStatus = "cancelling"
+	BatchStatusCancelled  BatchStatus = "cancelled"
+)
+
+// https://platform.openai.com/docs/api-reference/batch/object
+type Batch struct {
+	Object string `json:"object"` // "batch"
+
+	ID string `json:"id"`
+
+	Endpoint         string `json:"endpoint"`
+	CompletionWindow string `json:"completion_window"`
+
+	Status BatchStatus  `json:"status"`
+	Errors *BatchErrors `json:"errors"`
+
+	InputFileID  string `json:"input_file_id"`
+	OutputFileID string `json:"output_file_id,omitempty"`
+	ErrorFileID  string `json:"error_file_id,omitempty"`
+
+	CreatedAt    int64 `json:"created_at"`
+	InProgressAt int64 `json:"in_progress_at,omitempty"`
+	ExpiresAt    int64 `json:"expires_at,omitempty"`
+	FinalizingAt int64 `json:"finalizing_at,omitempty"`
+	CompletedAt  int64 `json:"completed_at,omitempty"`
+	FailedAt     int64 `json:"failed_at,omitempty"`
+	ExpiredAt    int64 `json:"expired_at,omitempty"`
+	CancellingAt int64 `json:"cancelling_at,omitempty"`
+	CancelledAt  int64 `json:"cancelled_at,omitempty"`
+
+	RequestCounts BatchRequestCounts `json:"request_counts"`
+
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+type BatchErrors struct {
+	Object string `json:"object"` // "list"
+
+	Data []BatchError `json:"data"`
+}
+
+type BatchError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+
+	Line *int `json:"line,omitempty"`
+}
+
+type BatchRequestCounts struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+}
+
+// https://platform.openai.com/docs/api-reference/batch/list
+type BatchList struct {
+	Object string `json:"object"` // "list"
+
+	Batches []Batch `json:"data"`
+
+	FirstID string `json:"first_id,omitempty"`
+	LastID  string `json:"last_id,omitempty"`
+	HasMore bool   `json:"has_more"`
+}
+
+// https://platform.openai.com/docs/api-reference/batch/request-input
+type BatchInput struct {
+	CustomID string `json:"custom_id"`
+
+	Method string `json:"method"`
+	URL    string `json:"url"`
+
+	Body map[string]any `json:"body"`
+}
+
+// https://platform.openai.com/docs/api-reference/batch/request-output
+type BatchOutput struct {
+	ID       string `json:"id"`
+	CustomID string `json:"custom_id"`
+
+	Response *BatchResponse `json:"response"`
+	Error    *BatchError    `json:"error"`
+}
+
+type BatchResponse struct {
+	StatusCode int    `json:"status_code"`
+	RequestID  string `json:"request_id"`
+
+	Body json.RawMessage `json:"body"`
+}
+
+// https://platform.openai.com/docs/api-reference/realtime-client-events
+type RealtimeEventType string
+
+var (
+	RealtimeEventTypeError RealtimeEventType = "error"
+
+	RealtimeEventTypeSessionUpdate  RealtimeEventType = "session.update"
+	RealtimeEventTypeSessionCreated RealtimeEventType = "session.created"
+	RealtimeEventTypeSessionUpdated RealtimeEventType = "session.updated"
+
+	RealtimeEventTypeInputAudioBufferAppend        RealtimeEventType = "input_audio_buffer.append"
+	RealtimeEventTypeInputAudioBufferCommit        RealtimeEventType = "input_audio_buffer.commit"
+	RealtimeEventTypeInputAudioBufferClear         RealtimeEventType = "input_audio_buffer.clear"
+	RealtimeEventTypeInputAudioBufferCommitted     RealtimeEventType = "input_audio_buffer.committed"
+	RealtimeEventTypeInputAudioBufferCleared       RealtimeEventType = "input_audio_buffer.cleared"
+	RealtimeEventTypeInputAudioBufferSpeechStarted RealtimeEventType = "input_audio_buffer.speech_started"
+	RealtimeEventTypeInputAudioBufferSpeechStopped RealtimeEventType = "input_audio_buffer.speech_stopped"
+
+	RealtimeEventTypeConversationItemCreate                 RealtimeEventType = "conversation.item.create"
+	RealtimeEventTypeConversationItemCreated                RealtimeEventType = "conversation.item.created"
+	RealtimeEventTypeConversationItemDelete                 RealtimeEventType = "conversation.item.delete"
+	RealtimeEventTypeConversationItemDeleted                RealtimeEventType = "conversation.item.deleted"
+	RealtimeEventTypeConversationItemTranscriptionCompleted RealtimeEventType = "conversation.item.input_audio_transcription.completed"
+	RealtimeEventTypeConversationItemTranscriptionFailed    RealtimeEventType = "conversation.item.input_audio_transcription.failed"
+	RealtimeEventTypeResponseCreate                         RealtimeEventType = "response.create"
+	RealtimeEventTypeResponseCancel                         RealtimeEventType = "response.cancel"
+	RealtimeEventTypeResponseCreated                        RealtimeEventType = "response.created"
+	RealtimeEventTypeResponseDone                           RealtimeEventType = "response.done"
+	RealtimeEventTypeResponseOutputItemAdded                RealtimeEventType = "response.output_item.added"
+	RealtimeEventTypeResponseOutputItemDone                 RealtimeEventType = "response.output_item.done"
+	RealtimeEventTypeResponseContentPartAdded               RealtimeEventType = "response.content_part.added"
+	RealtimeEventTypeResponseContentPartDone                RealtimeEventType = "response.content_part.done"
+	RealtimeEventTypeResponseTextDelta                      RealtimeEventType = "response.text.delta"
+	RealtimeEventTypeResponseTextDone                       RealtimeEventType = "response.text.done"
+	RealtimeEventTypeResponseAudioTranscriptDelta           RealtimeEventType = "response.audio_transcript.delta"
+	RealtimeEventTypeResponseAudioTranscriptDone            RealtimeEventType = "response.audio_transcript.done"
+	RealtimeEventTypeResponseAudioDelta                     RealtimeEventType = "response.audio.delta"
+	RealtimeEventTypeResponseAudioDone                      RealtimeEventType = "response.audio.done"
+	RealtimeEventTypeResponseFunctionCallArgumentsDone      RealtimeEventType = "response.function_call_arguments.done"
+)
+
+// https://platform.openai.com/docs/api-reference/realtime-server-events
+type RealtimeEvent struct {
+	ID   string            `json:"event_id,omitempty"`
+	Type RealtimeEventType `json:"type"`
+
+	Session *RealtimeSession `json:"session,omitempty"`
+
+	Audio string `json:"audio,omitempty"`
+
+	Item           *RealtimeItem `json:"item,omitempty"`
+	ItemID         string        `json:"item_id,omitempty"`
+	PreviousItemID string        `json:"previous_item_id,omitempty"`
+
+	Response   *RealtimeResponse `json:"response,omitempty"`
+	ResponseID string            `json:"response_id,omitempty"`
+
+	OutputIndex  *int             `json:"output_index,omitempty"`
+	ContentIndex *int             `json:"content_index,omitempty"`
+	Part         *RealtimeContent `json:"part,omitempty"`
+
+	Delta      string  `json:"delta,omitempty"`
+	Text       *string `json:"text,omitempty"`
+	Transcript *string `json:"transcript,omitempty"`
+
+	CallID    string `json:"call_id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
+
+	AudioStartMs *int `json:"audio_start_ms,omitempty"`
+	AudioEndMs   *int `json:"audio_end_ms,omitempty"`
+
+	Error *RealtimeError `json:"error,omitempty"`
+}
+
+type RealtimeError struct {
+	Type    string `json:"type"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+
+	EventID string `json:"event_id,omitempty"`
+}
+
+type RealtimeModality string
+
+var (
+	RealtimeModalityText  RealtimeModality = "text"
+	RealtimeModalityAudio RealtimeModality = "audio"
+)
+
+// https://platform.openai.com/docs/api-reference/realtime-sessions/session_object
+type RealtimeSession struct {
+	ID     string `json:"id,omitempty"`
+	Object string `json:"object,omitempty"` // "realtime.session"
+
+	Model string `json:"model,omitempty"`
+
+	Modalities   []RealtimeModality `json:"modalities,omitempty"`
+	Instructions string             `json:"instructions,omitempty"`
+
+	Voice string `json:"voice,omitempty"`
+
+	InputAudioFormat  string `json:"input_audio_format,omitempty"`
+	OutputAudioFormat string `json:"output_audio_format,omitempty"`
+
+	InputAudioTranscription *RealtimeTranscription `json:"input_audio_transcription"`
+	OutputAudioSynthesis    *RealtimeSynthesis     `json:"output_audio_synthesis,omitempty"` // non-standard
+	TurnDetection           *RealtimeTurnDetection `json:"turn_detection"`
+
+	Tools      []RealtimeTool `json:"tools"`
+	ToolChoice string         `json:"tool_choice,omitempty"`
+
+	Temperature             *float32           `json:"temperature,omitempty"`
+	MaxResponseOutputTokens *RealtimeMaxTokens `json:"max_response_output_tokens,omitempty"`
+}
+
+type RealtimeTranscription struct {
+	Model    string `json:"model,omitempty"`
+	Language string `json:"language,omitempty"`
+}
+
+type RealtimeSynthesis struct {
+	Model string `json:"model,omitempty"`
+}
+
+type RealtimeTurnDetection struct {
+	Type string `json:"type"` // "server_vad"
+
+	Threshold         *float64 `json:"threshold,omitempty"`
+	PrefixPaddingMs   *int     `json:"prefix_padding_ms,omitempty"`
+	SilenceDurationMs *int     `json:"silence_duration_ms,omitempty"`
+
+	CreateResponse    *bool `json:"create_response,omitempty"`
+	InterruptResponse *bool `json:"interrupt_response,omitempty"`
+}
+
+type RealtimeTool struct {
+	Type string `json:"type"` // "function"
+
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	Parameters map[string]any `json:"parameters,omitempty"`
+}
+
+// RealtimeMaxTokens is a token limit or "inf"
+type RealtimeMaxTokens struct {
+	Value *int
+}
+
+func (m RealtimeMaxTokens) MarshalJSON() ([]byte, error) {
+	if m.Value == nil {
+		return json.Marshal("inf")
+	}
+
+	return json.Marshal(*m.Value)
+}
+
+func (m *RealtimeMaxTokens) UnmarshalJSON(data []byte) error {
+	var value int
+
+	if err := json.Unmarshal(data, &value); err == nil {
+		m.Value = &value
+		return nil
+	}
+
+	var text string
+
+	if err := json.Unmarshal(data, &text); err != nil || text != "inf" {
+		return errors.New("invalid max tokens")
+	}
+
+	m.Value = nil
+	return nil
+}
+
+type RealtimeItemType string
+
+var (
+	RealtimeItemTypeMessage            RealtimeItemType = "message"
+	RealtimeItemTypeFunctionCall       RealtimeItemType = "function_call"
+	RealtimeItemTypeFunctionCallOutput RealtimeItemType = "function_call_output"
+)
+
+// https://platform.openai.com/docs/api-reference/realtime-client-events/conversation/item/create
+type RealtimeItem struct {
+	ID     string `json:"id,omitempty"`
+	Object string `json:"object,omitempty"` // "realtime.item"
+
+	Type   RealtimeItemType `json:"type"`
+	Status string           `json:"status,omitempty"`
+
+	Role    MessageRole       `json:"role,omitempty"`
+	Content []RealtimeContent `json:"content,omitempty"`
+
+	CallID    string `json:"call_id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
+	Output    string `json:"output,omitempty"`
+}
+
+type RealtimeContentType string
+
+var (
+	RealtimeContentTypeInputText  RealtimeContentType = "input_text"
+	RealtimeContentTypeInputAudio RealtimeContentType = "input_audio"
+	RealtimeContentTypeText       RealtimeContentType = "text"
+	RealtimeContentTypeAudio      RealtimeContentType = "audio"
+)
+
+type RealtimeContent struct {
+	Type RealtimeContentType `json:"type"`
+
+	Text  string `json:"text,omitempty"`
+	Audio string `json:"audio,omitempty"`
+
+	Transcript *string `json:"transcript,omitempty"`
+}
+
+// https://platform.openai.com/docs/api-reference/realtime-server-events/response/created
+type RealtimeResponse struct {
+	ID     string `json:"id,omitempty"`
+	Object string `json:"object,omitempty"` // "realtime.response"
+
+	Status        string                 `json:"status,omitempty"` // "in_progress", "completed", "cancelled", "failed"
+	StatusDetails *RealtimeStatusDetails `json:"status_details,omitempty"`
+
+	Output []RealtimeItem `json:"output,omitempty"`
+	Usage  *RealtimeUsage `json:"usage,omitempty"`
+
+	// options of response.create, overriding the session
+	Modalities   []RealtimeModality `json:"modalities,omitempty"`
+	Instructions string             `json:"instructions,omitempty"`
+
+	Voice string `json:"voice,omitempty"`
+
+	Tools      []RealtimeTool `json:"tools,omitempty"`
+	ToolChoice string         `json:"tool_choice,omitempty"`
+
+	Temperature     *float32           `json:"temperature,omitempty"`
+	MaxOutputTokens *RealtimeMaxTokens `json:"max_output_tokens,omitempty"`
+}
+
+type RealtimeStatusDetails struct {
+	Type   string `json:"type"`
+	Reason string `json:"reason,omitempty"`
+
+	Error *RealtimeError `json:"error,omitempty"`
+}
+
+type RealtimeUsage struct {
+	TotalTokens  int `json:"total_tokens"`
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
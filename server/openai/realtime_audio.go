@@ -0,0 +1,263 @@
+package openai
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// realtime audio is 16-bit little-endian mono pcm at 24kHz
+const (
+	realtimeSampleRate = 24000
+	realtimeFrameSize  = realtimeSampleRate / 50 * 2 // 20ms
+)
+
+// voiceDetector finds turns in an audio stream by the energy of its frames.
+// The threshold between 0 and 1 scales the rms level counting as speech,
+// so the default of 0.5 equals a level of about -32 dBFS.
+type voiceDetector struct {
+	level float64
+
+	prefix  int
+	silence int
+
+	pending  []byte
+	position int
+
+	speaking bool
+	quiet    int
+}
+
+func newVoiceDetector(d *RealtimeTurnDetection) *voiceDetector {
+	threshold := 0.5
+	prefixMs := 300
+	silenceMs := 500
+
+	if d.Threshold != nil {
+		threshold = *d.Threshold
+	}
+
+	if d.PrefixPaddingMs != nil {
+		prefixMs = *d.PrefixPaddingMs
+	}
+
+	if d.SilenceDurationMs != nil {
+		silenceMs = *d.SilenceDurationMs
+	}
+
+	return &voiceDetector{
+		level: threshold * 0.05,
+
+		prefix:  pcmBytes(prefixMs),
+		silence: pcmBytes(silenceMs),
+	}
+}
+
+type voiceEvent int
+
+const (
+	voiceEventNone voiceEvent = iota
+	voiceEventStarted
+	voiceEventStopped
+)
+
+// Write processes appended audio up to the next turn change.
+// Call it again without data to process the audio after the change.
+func (d *voiceDetector) Write(data []byte) voiceEvent {
+	d.pending = append(d.pending, data...)
+
+	for len(d.pending) >= realtimeFrameSize {
+		frame := d.pending[:realtimeFrameSize]
+
+		d.pending = d.pending[realtimeFrameSize:]
+		d.position += realtimeFrameSize
+
+		speech := pcmLevel(frame) >= d.level
+
+		if !d.speaking {
+			if speech {
+				d.speaking = true
+				d.quiet = 0
+
+				return voiceEventStarted
+			}
+
+			continue
+		}
+
+		if speech {
+			d.quiet = 0
+			continue
+		}
+
+		d.quiet += realtimeFrameSize
+
+		if d.quiet >= d.silence {
+			d.speaking = false
+			d.quiet = 0
+
+			return voiceEventStopped
+		}
+	}
+
+	return voiceEventNone
+}
+
+// Idle returns how many trailing bytes of the buffered audio to keep while nobody speaks
+func (d *voiceDetector) Idle() int {
+	return d.prefix + len(d.pending)
+}
+
+// Position returns the stream time of the processed audio in milliseconds
+func (d *voiceDetector) Position() int {
+	return pcmMillis(d.position)
+}
+
+func (d *voiceDetector) Reset() {
+	d.pending = nil
+	d.speaking = false
+	d.quiet = 0
+}
+
+func pcmBytes(ms int) int {
+	return realtimeSampleRate * ms / 1000 * 2
+}
+
+func pcmMillis(n int) int {
+	return n / 2 * 1000 / realtimeSampleRate
+}
+
+// pcmLevel returns the rms of 16-bit samples between 0 and 1
+func pcmLevel(data []byte) float64 {
+	n := len(data) / 2
+
+	if n == 0 {
+		return 0
+	}
+
+	var sum float64
+
+	for i := 0; i < n; i++ {
+		v := float64(int16(binary.LittleEndian.Uint16(data[i*2:]))) / math.MaxInt16
+		sum += v * v
+	}
+
+	return math.Sqrt(sum / float64(n))
+}
+
+// encodeWAV wraps realtime pcm audio for transcribers
+func encodeWAV(data []byte) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(36+len(data)))
+	buf.WriteString("WAVE")
+
+	buf.WriteString("fmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16))
+	binary.Write(&buf, binary.LittleEndian, uint16(1)) // pcm
+	binary.Write(&buf, binary.LittleEndian, uint16(1)) // mono
+	binary.Write(&buf, binary.LittleEndian, uint32(realtimeSampleRate))
+	binary.Write(&buf, binary.LittleEndian, uint32(realtimeSampleRate*2))
+	binary.Write(&buf, binary.LittleEndian, uint16(2))
+	binary.Write(&buf, binary.LittleEndian, uint16(16))
+
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(len(data)))
+	buf.Write(data)
+
+	return buf.Bytes()
+}
+
+// decodeWAV converts 16-bit pcm wav audio, as returned by synthesizers, to realtime pcm audio
+func decodeWAV(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, errors.New("unsupported audio format, expected wav")
+	}
+
+	var channels, bits, format int
+	var rate int
+
+	data = data[12:]
+
+	for len(data) >= 8 {
+		id := string(data[0:4])
+		size := int(binary.LittleEndian.Uint32(data[4:8]))
+
+		data = data[8:]
+
+		// streamed wav files do not know the final size of their data
+		if size < 0 || size > len(data) {
+			size = len(data)
+		}
+
+		chunk := data[:size]
+
+		switch id {
+		case "fmt ":
+			if len(chunk) < 16 {
+				return nil, errors.New("invalid wav format chunk")
+			}
+
+			format = int(binary.LittleEndian.Uint16(chunk[0:2]))
+			channels = int(binary.LittleEndian.Uint16(chunk[2:4]))
+			rate = int(binary.LittleEndian.Uint32(chunk[4:8]))
+			bits = int(binary.LittleEndian.Uint16(chunk[14:16]))
+
+		case "data":
+			// 0xfffe is the extensible format, which is pcm for 16-bit audio
+			if (format != 1 && format != 0xfffe) || bits != 16 || channels < 1 || rate < 1 {
+				return nil, errors.New("unsupported wav encoding, expected 16-bit pcm")
+			}
+
+			return resamplePCM(chunk, channels, rate), nil
+		}
+
+		// chunks are padded to an even size
+		data = data[min(size+size%2, len(data)):]
+	}
+
+	return nil, errors.New("invalid wav file, no data")
+}
+
+// resamplePCM mixes interleaved 16-bit audio down to mono and interpolates it to the realtime sample rate
+func resamplePCM(data []byte, channels, rate int) []byte {
+	frames := len(data) / 2 / channels
+
+	mono := make([]float64, frames)
+
+	for i := range mono {
+		var sum float64
+
+		for c := 0; c < channels; c++ {
+			sum += float64(int16(binary.LittleEndian.Uint16(data[(i*channels+c)*2:])))
+		}
+
+		mono[i] = sum / float64(channels)
+	}
+
+	if frames == 0 {
+		return nil
+	}
+
+	n := frames * realtimeSampleRate / rate
+	result := make([]byte, n*2)
+
+	for i := 0; i < n; i++ {
+		pos := float64(i) * float64(rate) / realtimeSampleRate
+
+		j := int(pos)
+		frac := pos - float64(j)
+
+		v := mono[min(j, frames-1)]
+
+		if j+1 < frames {
+			v += (mono[j+1] - v) * frac
+		}
+
+		binary.LittleEndian.PutUint16(result[i*2:], uint16(int16(math.Round(v))))
+	}
+
+	return result
+}
@@ -0,0 +1,561 @@
+package openai
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/to"
+)
+
+// realtimeAudioChunk is the size of the audio deltas, 200ms of realtime audio
+const realtimeAudioChunk = realtimeSampleRate / 5 * 2
+
+func (s *realtimeSession) respond(ctx context.Context, options *RealtimeResponse) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	s.mu.Lock()
+
+	session := s.session
+	items := slices.Clone(s.items)
+
+	s.cancel = cancel
+
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		s.cancel = nil
+		s.mu.Unlock()
+	}()
+
+	if options != nil {
+		if options.Modalities != nil {
+			session.Modalities = options.Modalities
+		}
+
+		if options.Instructions != "" {
+			session.Instructions = options.Instructions
+		}
+
+		if options.Voice != "" {
+			session.Voice = options.Voice
+		}
+
+		if options.Tools != nil {
+			session.Tools = options.Tools
+		}
+
+		if options.ToolChoice != "" {
+			session.ToolChoice = options.ToolChoice
+		}
+
+		if options.Temperature != nil {
+			session.Temperature = options.Temperature
+		}
+
+		if options.MaxOutputTokens != nil {
+			session.MaxResponseOutputTokens = options.MaxOutputTokens
+		}
+	}
+
+	response := &RealtimeResponse{
+		ID:     realtimeID("resp_"),
+		Object: "realtime.response",
+
+		Status: "in_progress",
+		Output: []RealtimeItem{},
+	}
+
+	s.send(RealtimeEvent{Type: RealtimeEventTypeResponseCreated, Response: to.Ptr(*response)})
+
+	output := &realtimeOutput{
+		session:  s,
+		response: response,
+
+		voice: session.Voice,
+	}
+
+	completion, err := func() (*provider.Completion, error) {
+		s.mu.Lock()
+		messages := realtimeMessages(session.Instructions, items)
+		s.mu.Unlock()
+
+		if slices.Contains(session.Modalities, RealtimeModalityAudio) {
+			model := ""
+
+			if session.OutputAudioSynthesis != nil {
+				model = session.OutputAudioSynthesis.Model
+			}
+
+			synthesizer, err := s.Synthesizer(model)
+
+			if err != nil {
+				return nil, err
+			}
+
+			output.synthesizer = synthesizer
+		}
+
+		completion, err := s.completer.Complete(ctx, messages, &provider.CompleteOptions{
+			Stream: output.stream,
+
+			Tools:      realtimeTools(session.Tools),
+			ToolChoice: realtimeToolChoice(session.ToolChoice),
+
+			MaxTokens:   realtimeMaxTokens(session.MaxResponseOutputTokens),
+			Temperature: session.Temperature,
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		// providers not streaming any text still produce the events for the final content
+		if output.item == nil && completion.Message.Content != "" {
+			if err := output.stream(ctx, *completion); err != nil {
+				return nil, err
+			}
+		}
+
+		return completion, nil
+	}()
+
+	if serr := output.finish(ctx); err == nil {
+		err = serr
+	}
+
+	if err == nil {
+		for _, c := range completion.Message.ToolCalls {
+			output.call(c)
+		}
+	}
+
+	s.mu.Lock()
+
+	for _, item := range response.Output {
+		s.items = append(s.items, to.Ptr(item))
+	}
+
+	s.mu.Unlock()
+
+	response.Status = "completed"
+
+	if ctx.Err() != nil {
+		response.Status = "cancelled"
+
+		details := &RealtimeStatusDetails{
+			Type: "cancelled",
+		}
+
+		var reason realtimeCancelReason
+
+		if errors.As(context.Cause(ctx), &reason) {
+			details.Reason = string(reason)
+		}
+
+		response.StatusDetails = details
+	} else if err != nil {
+		response.Status = "failed"
+
+		response.StatusDetails = &RealtimeStatusDetails{
+			Type: "failed",
+
+			Error: &RealtimeError{
+				Type:    "server_error",
+				Message: err.Error(),
+			},
+		}
+	}
+
+	if completion != nil && completion.Usage != nil {
+		response.Usage = &RealtimeUsage{
+			InputTokens:  completion.Usage.InputTokens,
+			OutputTokens: completion.Usage.OutputTokens,
+
+			TotalTokens: completion.Usage.InputTokens + completion.Usage.OutputTokens,
+		}
+	}
+
+	s.send(RealtimeEvent{Type: RealtimeEventTypeResponseDone, Response: response})
+}
+
+// realtimeOutput streams the assistant message of a response as text or as transcript and synthesized audio.
+// Audio is synthesized sentence by sentence in the background, so the transcript does not wait for it.
+type realtimeOutput struct {
+	session  *realtimeSession
+	response *RealtimeResponse
+
+	synthesizer provider.Synthesizer
+	voice       string
+
+	item *RealtimeItem
+	text strings.Builder
+
+	pending string
+
+	speech chan string
+	done   chan error
+}
+
+func (o *realtimeOutput) stream(ctx context.Context, completion provider.Completion) error {
+	delta := completion.Message.Content
+
+	if delta == "" {
+		return nil
+	}
+
+	if o.item == nil {
+		o.start(ctx)
+	}
+
+	o.text.WriteString(delta)
+
+	event := RealtimeEvent{
+		Type: RealtimeEventTypeResponseTextDelta,
+
+		ResponseID:   o.response.ID,
+		ItemID:       o.item.ID,
+		OutputIndex:  to.Ptr(len(o.response.Output)),
+		ContentIndex: to.Ptr(0),
+
+		Delta: delta,
+	}
+
+	if o.synthesizer != nil {
+		event.Type = RealtimeEventTypeResponseAudioTranscriptDelta
+	}
+
+	if err := o.session.send(event); err != nil {
+		return err
+	}
+
+	if o.synthesizer == nil {
+		return nil
+	}
+
+	var sentences string
+	sentences, o.pending = splitSentences(o.pending + delta)
+
+	if sentences == "" {
+		return nil
+	}
+
+	select {
+	case o.speech <- sentences:
+		return nil
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (o *realtimeOutput) start(ctx context.Context) {
+	o.item = &RealtimeItem{
+		ID:     realtimeID("item_"),
+		Object: "realtime.item",
+
+		Type:   RealtimeItemTypeMessage,
+		Status: "in_progress",
+
+		Role:    MessageRoleAssistant,
+		Content: []RealtimeContent{},
+	}
+
+	part := &RealtimeContent{
+		Type: RealtimeContentTypeText,
+		Text: "",
+	}
+
+	if o.synthesizer != nil {
+		part = &RealtimeContent{
+			Type:       RealtimeContentTypeAudio,
+			Transcript: to.Ptr(""),
+		}
+	}
+
+	o.session.send(RealtimeEvent{Type: RealtimeEventTypeResponseOutputItemAdded, ResponseID: o.response.ID, OutputIndex: to.Ptr(len(o.response.Output)), Item: o.item})
+	o.session.send(RealtimeEvent{Type: RealtimeEventTypeResponseContentPartAdded, ResponseID: o.response.ID, ItemID: o.item.ID, OutputIndex: to.Ptr(len(o.response.Output)), ContentIndex: to.Ptr(0), Part: part})
+
+	if o.synthesizer == nil {
+		return
+	}
+
+	o.speech = make(chan string, 16)
+	o.done = make(chan error, 1)
+
+	go func() {
+		var err error
+
+		for text := range o.speech {
+			if err != nil || ctx.Err() != nil {
+				continue
+			}
+
+			err = o.speak(ctx, text)
+		}
+
+		o.done <- err
+	}()
+}
+
+func (o *realtimeOutput) speak(ctx context.Context, text string) error {
+	synthesis, err := o.synthesizer.Synthesize(ctx, text, &provider.SynthesizeOptions{
+		Voice: o.voice,
+
+		Format: provider.AudioFormatWAV,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	defer synthesis.Reader.Close()
+
+	data, err := io.ReadAll(synthesis.Reader)
+
+	if err != nil {
+		return err
+	}
+
+	audio, err := decodeWAV(data)
+
+	if err != nil {
+		return err
+	}
+
+	for chunk := range slices.Chunk(audio, realtimeAudioChunk) {
+		if err := o.session.send(RealtimeEvent{
+			Type: RealtimeEventTypeResponseAudioDelta,
+
+			ResponseID:   o.response.ID,
+			ItemID:       o.item.ID,
+			OutputIndex:  to.Ptr(len(o.response.Output)),
+			ContentIndex: to.Ptr(0),
+
+			Delta: base64.StdEncoding.EncodeToString(chunk),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// finish completes the assistant message, also when the response was cancelled
+func (o *realtimeOutput) finish(ctx context.Context) error {
+	if o.item == nil {
+		return nil
+	}
+
+	var err error
+
+	if o.synthesizer != nil {
+		if text := strings.TrimSpace(o.pending); text != "" && ctx.Err() == nil {
+			o.speech <- text
+		}
+
+		close(o.speech)
+		err = <-o.done
+	}
+
+	index := len(o.response.Output)
+	text := o.text.String()
+
+	part := RealtimeContent{
+		Type: RealtimeContentTypeText,
+		Text: text,
+	}
+
+	if o.synthesizer != nil {
+		part = RealtimeContent{
+			Type:       RealtimeContentTypeAudio,
+			Transcript: to.Ptr(text),
+		}
+
+		o.session.send(RealtimeEvent{Type: RealtimeEventTypeResponseAudioDone, ResponseID: o.response.ID, ItemID: o.item.ID, OutputIndex: to.Ptr(index), ContentIndex: to.Ptr(0)})
+		o.session.send(RealtimeEvent{Type: RealtimeEventTypeResponseAudioTranscriptDone, ResponseID: o.response.ID, ItemID: o.item.ID, OutputIndex: to.Ptr(index), ContentIndex: to.Ptr(0), Transcript: to.Ptr(text)})
+	} else {
+		o.session.send(RealtimeEvent{Type: RealtimeEventTypeResponseTextDone, ResponseID: o.response.ID, ItemID: o.item.ID, OutputIndex: to.Ptr(index), ContentIndex: to.Ptr(0), Text: to.Ptr(text)})
+	}
+
+	o.session.send(RealtimeEvent{Type: RealtimeEventTypeResponseContentPartDone, ResponseID: o.response.ID, ItemID: o.item.ID, OutputIndex: to.Ptr(index), ContentIndex: to.Ptr(0), Part: &part})
+
+	o.item.Status = "completed"
+	o.item.Content = []RealtimeContent{part}
+
+	if ctx.Err() != nil {
+		o.item.Status = "incomplete"
+	}
+
+	o.session.send(RealtimeEvent{Type: RealtimeEventTypeResponseOutputItemDone, ResponseID: o.response.ID, OutputIndex: to.Ptr(index), Item: o.item})
+
+	o.response.Output = append(o.response.Output, *o.item)
+
+	return err
+}
+
+// call adds a function call for the client to run, which answers with a function_call_output item
+func (o *realtimeOutput) call(call provider.ToolCall) {
+	index := len(o.response.Output)
+
+	item := RealtimeItem{
+		ID:     realtimeID("item_"),
+		Object: "realtime.item",
+
+		Type:   RealtimeItemTypeFunctionCall,
+		Status: "completed",
+
+		CallID:    call.ID,
+		Name:      call.Name,
+		Arguments: call.Arguments,
+	}
+
+	o.session.send(RealtimeEvent{Type: RealtimeEventTypeResponseOutputItemAdded, ResponseID: o.response.ID, OutputIndex: to.Ptr(index), Item: &item})
+	o.session.send(RealtimeEvent{Type: RealtimeEventTypeResponseFunctionCallArgumentsDone, ResponseID: o.response.ID, ItemID: item.ID, OutputIndex: to.Ptr(index), CallID: call.ID, Name: call.Name, Arguments: call.Arguments})
+	o.session.send(RealtimeEvent{Type: RealtimeEventTypeResponseOutputItemDone, ResponseID: o.response.ID, OutputIndex: to.Ptr(index), Item: &item})
+
+	o.response.Output = append(o.response.Output, item)
+}
+
+// splitSentences returns the complete sentences of a text and the rest
+func splitSentences(text string) (string, string) {
+	end := -1
+
+	for i, r := range text {
+		if i+1 >= len(text) {
+			break
+		}
+
+		switch r {
+		case '.', '!', '?', ':', ';', '\n':
+			if next := text[i+1]; next == ' ' || next == '\n' || next == '\t' {
+				end = i + 1
+			}
+		}
+	}
+
+	if end < 0 {
+		return "", text
+	}
+
+	return strings.TrimSpace(text[:end]), text[end:]
+}
+
+func realtimeMessages(instructions string, items []*RealtimeItem) []provider.Message {
+	var result []provider.Message
+
+	if instructions != "" {
+		result = append(result, provider.Message{
+			Role:    provider.MessageRoleSystem,
+			Content: instructions,
+		})
+	}
+
+	for _, item := range items {
+		switch item.Type {
+		case RealtimeItemTypeMessage:
+			var parts []string
+
+			for _, c := range item.Content {
+				switch c.Type {
+				case RealtimeContentTypeInputText, RealtimeContentTypeText:
+					parts = append(parts, c.Text)
+
+				case RealtimeContentTypeInputAudio, RealtimeContentTypeAudio:
+					if c.Transcript != nil {
+						parts = append(parts, *c.Transcript)
+					}
+				}
+			}
+
+			content := strings.TrimSpace(strings.Join(parts, "\n"))
+
+			if content == "" {
+				continue
+			}
+
+			result = append(result, provider.Message{
+				Role:    toMessageRole(item.Role),
+				Content: content,
+			})
+
+		case RealtimeItemTypeFunctionCall:
+			call := provider.ToolCall{
+				ID: item.CallID,
+
+				Name:      item.Name,
+				Arguments: item.Arguments,
+			}
+
+			// calls of the same turn belong to a single assistant message
+			if n := len(result); n > 0 && result[n-1].Role == provider.MessageRoleAssistant {
+				result[n-1].ToolCalls = append(result[n-1].ToolCalls, call)
+				continue
+			}
+
+			result = append(result, provider.Message{
+				Role:      provider.MessageRoleAssistant,
+				ToolCalls: []provider.ToolCall{call},
+			})
+
+		case RealtimeItemTypeFunctionCallOutput:
+			result = append(result, provider.Message{
+				Role:    provider.MessageRoleTool,
+				Content: item.Output,
+
+				Tool: item.CallID,
+			})
+		}
+	}
+
+	return result
+}
+
+func realtimeTools(tools []RealtimeTool) []provider.Tool {
+	var result []provider.Tool
+
+	for _, t := range tools {
+		if t.Type != "" && t.Type != "function" {
+			continue
+		}
+
+		result = append(result, provider.Tool{
+			Name:        t.Name,
+			Description: t.Description,
+
+			Parameters: t.Parameters,
+		})
+	}
+
+	return result
+}
+
+func realtimeToolChoice(choice string) *provider.ToolChoice {
+	switch choice {
+	case "", "auto":
+		return nil
+
+	case "none":
+		return &provider.ToolChoice{Mode: provider.ToolChoiceModeNone}
+
+	case "required":
+		return &provider.ToolChoice{Mode: provider.ToolChoiceModeRequired}
+
+	default:
+		return &provider.ToolChoice{Mode: provider.ToolChoiceModeRequired, Name: choice}
+	}
+}
+
+func realtimeMaxTokens(tokens *RealtimeMaxTokens) *int {
+	if tokens == nil {
+		return nil
+	}
+
+	return tokens.Value
+}
@@ -0,0 +1,468 @@
+package openai
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/to"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVoiceDetector(t *testing.T) {
+	tests := []struct {
+		name string
+
+		detection *RealtimeTurnDetection
+		audio     [][]byte
+
+		want []voiceEvent
+	}{
+		{
+			name: "silence",
+
+			detection: &RealtimeTurnDetection{},
+			audio:     [][]byte{testSilence(1000)},
+		},
+		{
+			name: "quiet tone",
+
+			detection: &RealtimeTurnDetection{},
+			audio:     [][]byte{testTone(1000, 0.01)},
+		},
+		{
+			name: "turn",
+
+			detection: &RealtimeTurnDetection{},
+			audio:     [][]byte{testTone(200, 0.5), testSilence(600)},
+
+			want: []voiceEvent{voiceEventStarted, voiceEventStopped},
+		},
+		{
+			name: "turn in a single write",
+
+			detection: &RealtimeTurnDetection{},
+			audio:     [][]byte{append(testTone(200, 0.5), testSilence(600)...)},
+
+			want: []voiceEvent{voiceEventStarted, voiceEventStopped},
+		},
+		{
+			name: "short pause",
+
+			detection: &RealtimeTurnDetection{},
+			audio:     [][]byte{testTone(200, 0.5), testSilence(300), testTone(200, 0.5)},
+
+			want: []voiceEvent{voiceEventStarted},
+		},
+		{
+			name: "custom silence",
+
+			detection: &RealtimeTurnDetection{SilenceDurationMs: to.Ptr(200)},
+			audio:     [][]byte{testTone(200, 0.5), testSilence(300), testTone(200, 0.5), testSilence(300)},
+
+			want: []voiceEvent{voiceEventStarted, voiceEventStopped, voiceEventStarted, voiceEventStopped},
+		},
+		{
+			name: "custom threshold",
+
+			detection: &RealtimeTurnDetection{Threshold: to.Ptr(0.1)},
+			audio:     [][]byte{testTone(200, 0.01), testSilence(600)},
+
+			want: []voiceEvent{voiceEventStarted, voiceEventStopped},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newVoiceDetector(tt.detection)
+
+			var events []voiceEvent
+
+			for _, data := range tt.audio {
+				for event := d.Write(data); event != voiceEventNone; event = d.Write(nil) {
+					events = append(events, event)
+				}
+			}
+
+			require.Equal(t, tt.want, events)
+		})
+	}
+}
+
+func TestVoiceDetectorPosition(t *testing.T) {
+	d := newVoiceDetector(&RealtimeTurnDetection{})
+
+	require.Equal(t, voiceEventStarted, d.Write(append(testSilence(100), testTone(100, 0.5)...)))
+	require.Equal(t, 120, d.Position())
+
+	require.Equal(t, voiceEventNone, d.Write(nil))
+	require.Equal(t, 200, d.Position())
+
+	// a partial frame is kept for the next write
+	require.Equal(t, voiceEventNone, d.Write(testSilence(10)))
+	require.Equal(t, 200, d.Position())
+	require.Equal(t, pcmBytes(300)+pcmBytes(10), d.Idle())
+
+	d.Reset()
+	require.Equal(t, pcmBytes(300), d.Idle())
+}
+
+func TestDecodeWAV(t *testing.T) {
+	mono := testSamples(0, 1000, -1000, 32767, -32768)
+
+	tests := []struct {
+		name string
+		data []byte
+
+		want []byte
+		err  bool
+	}{
+		{
+			name: "realtime",
+			data: encodeWAV(mono),
+
+			want: mono,
+		},
+		{
+			name: "stereo",
+			data: testWAV(1, 2, realtimeSampleRate, 16, testSamples(100, 300, -100, -300)),
+
+			want: testSamples(200, -200),
+		},
+		{
+			name: "48kHz",
+			data: testWAV(1, 1, 48000, 16, testSamples(0, 100, 200, 300)),
+
+			want: testSamples(0, 200),
+		},
+		{
+			name: "12kHz",
+			data: testWAV(1, 1, 12000, 16, testSamples(0, 100)),
+
+			want: testSamples(0, 50, 100, 100),
+		},
+		{
+			name: "extensible",
+			data: testWAV(0xfffe, 1, realtimeSampleRate, 16, mono),
+
+			want: mono,
+		},
+		{
+			name: "streamed",
+			data: testStreamedWAV(mono),
+
+			want: mono,
+		},
+		{
+			name: "8-bit",
+			data: testWAV(1, 1, realtimeSampleRate, 8, []byte{0, 1, 2, 3}),
+
+			err: true,
+		},
+		{
+			name: "float",
+			data: testWAV(3, 1, realtimeSampleRate, 16, mono),
+
+			err: true,
+		},
+		{
+			name: "mp3",
+			data: []byte("ID3\x04\x00\x00\x00\x00\x00\x00\x00\x00"),
+
+			err: true,
+		},
+		{
+			name: "no data",
+			data: encodeWAV(nil)[:36],
+
+			err: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := decodeWAV(tt.data)
+
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, result)
+		})
+	}
+}
+
+func TestResamplePCM(t *testing.T) {
+	tests := []struct {
+		name string
+
+		data     []byte
+		channels int
+		rate     int
+
+		want []byte
+	}{
+		{
+			name: "empty",
+
+			channels: 1,
+			rate:     realtimeSampleRate,
+		},
+		{
+			name: "unchanged",
+
+			data:     testSamples(1, 2, 3),
+			channels: 1,
+			rate:     realtimeSampleRate,
+
+			want: testSamples(1, 2, 3),
+		},
+		{
+			name: "mixdown",
+
+			data:     testSamples(10, 20, 30, 10, 20, 30),
+			channels: 3,
+			rate:     realtimeSampleRate,
+
+			want: testSamples(20, 20),
+		},
+		{
+			name: "downsample",
+
+			data:     testSamples(0, 10, 20, 30, 40, 50),
+			channels: 1,
+			rate:     36000,
+
+			want: testSamples(0, 15, 30, 45),
+		},
+		{
+			name: "upsample",
+
+			data:     testSamples(0, 30),
+			channels: 1,
+			rate:     8000,
+
+			want: testSamples(0, 10, 20, 30, 30, 30),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, resamplePCM(tt.data, tt.channels, tt.rate))
+		})
+	}
+}
+
+func TestSplitSentences(t *testing.T) {
+	tests := []struct {
+		text string
+
+		sentence string
+		rest     string
+	}{
+		{"", "", ""},
+		{"Hello", "", "Hello"},
+		{"Hello.", "", "Hello."},
+		{"Hello. ", "Hello.", " "},
+		{"Hello. How", "Hello.", " How"},
+		{"Hello! How are you? I am", "Hello! How are you?", " I am"},
+		{"Version 1.5 is out", "", "Version 1.5 is out"},
+		{"Note: this", "Note:", " this"},
+		{"First line\nSecond", "", "First line\nSecond"},
+		{"First line\n\nSecond", "First line", "\nSecond"},
+		{"Done.\n\nNext", "Done.", "\nNext"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			sentence, rest := splitSentences(tt.text)
+
+			require.Equal(t, tt.sentence, sentence)
+			require.Equal(t, tt.rest, rest)
+		})
+	}
+}
+
+func TestRealtimeMessages(t *testing.T) {
+	tests := []struct {
+		name string
+
+		instructions string
+		items        []*RealtimeItem
+
+		want []provider.Message
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "instructions",
+
+			instructions: "Be brief.",
+
+			want: []provider.Message{
+				{Role: provider.MessageRoleSystem, Content: "Be brief."},
+			},
+		},
+		{
+			name: "text and transcripts",
+
+			items: []*RealtimeItem{
+				{
+					Type: RealtimeItemTypeMessage,
+					Role: MessageRoleUser,
+
+					Content: []RealtimeContent{
+						{Type: RealtimeContentTypeInputText, Text: "Look at this."},
+						{Type: RealtimeContentTypeInputAudio, Transcript: to.Ptr("What is it?")},
+					},
+				},
+				{
+					Type: RealtimeItemTypeMessage,
+					Role: MessageRoleAssistant,
+
+					Content: []RealtimeContent{
+						{Type: RealtimeContentTypeAudio, Transcript: to.Ptr("A cat.")},
+					},
+				},
+			},
+
+			want: []provider.Message{
+				{Role: provider.MessageRoleUser, Content: "Look at this.\nWhat is it?"},
+				{Role: provider.MessageRoleAssistant, Content: "A cat."},
+			},
+		},
+		{
+			name: "pending transcript",
+
+			items: []*RealtimeItem{
+				{
+					Type: RealtimeItemTypeMessage,
+					Role: MessageRoleUser,
+
+					Content: []RealtimeContent{
+						{Type: RealtimeContentTypeInputAudio},
+					},
+				},
+			},
+		},
+		{
+			name: "function calls",
+
+			items: []*RealtimeItem{
+				{
+					Type: RealtimeItemTypeMessage,
+					Role: MessageRoleUser,
+
+					Content: []RealtimeContent{
+						{Type: RealtimeContentTypeInputText, Text: "Weather in Bern and Zurich?"},
+					},
+				},
+				{
+					Type: RealtimeItemTypeMessage,
+					Role: MessageRoleAssistant,
+
+					Content: []RealtimeContent{
+						{Type: RealtimeContentTypeText, Text: "Let me check."},
+					},
+				},
+				{Type: RealtimeItemTypeFunctionCall, CallID: "1", Name: "weather", Arguments: `{"city":"Bern"}`},
+				{Type: RealtimeItemTypeFunctionCall, CallID: "2", Name: "weather", Arguments: `{"city":"Zurich"}`},
+				{Type: RealtimeItemTypeFunctionCallOutput, CallID: "1", Output: "sunny"},
+				{Type: RealtimeItemTypeFunctionCallOutput, CallID: "2", Output: "rainy"},
+				{Type: RealtimeItemTypeFunctionCall, CallID: "3", Name: "forecast", Arguments: `{}`},
+			},
+
+			want: []provider.Message{
+				{Role: provider.MessageRoleUser, Content: "Weather in Bern and Zurich?"},
+				{
+					Role:    provider.MessageRoleAssistant,
+					Content: "Let me check.",
+
+					ToolCalls: []provider.ToolCall{
+						{ID: "1", Name: "weather", Arguments: `{"city":"Bern"}`},
+						{ID: "2", Name: "weather", Arguments: `{"city":"Zurich"}`},
+					},
+				},
+				{Role: provider.MessageRoleTool, Content: "sunny", Tool: "1"},
+				{Role: provider.MessageRoleTool, Content: "rainy", Tool: "2"},
+				{
+					Role: provider.MessageRoleAssistant,
+
+					ToolCalls: []provider.ToolCall{
+						{ID: "3", Name: "forecast", Arguments: `{}`},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, realtimeMessages(tt.instructions, tt.items))
+		})
+	}
+}
+
+func testSamples(samples ...int16) []byte {
+	data := make([]byte, len(samples)*2)
+
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(data[i*2:], uint16(s))
+	}
+
+	return data
+}
+
+// testTone returns a 440Hz sine wave with the given amplitude between 0 and 1
+func testTone(ms int, amplitude float64) []byte {
+	samples := make([]int16, pcmBytes(ms)/2)
+
+	for i := range samples {
+		samples[i] = int16(amplitude * math.MaxInt16 * math.Sin(2*math.Pi*440*float64(i)/realtimeSampleRate))
+	}
+
+	return testSamples(samples...)
+}
+
+func testSilence(ms int) []byte {
+	return make([]byte, pcmBytes(ms))
+}
+
+func testWAV(format, channels, rate, bits int, data []byte) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(36+len(data)))
+	buf.WriteString("WAVE")
+
+	buf.WriteString("fmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16))
+	binary.Write(&buf, binary.LittleEndian, uint16(format))
+	binary.Write(&buf, binary.LittleEndian, uint16(channels))
+	binary.Write(&buf, binary.LittleEndian, uint32(rate))
+	binary.Write(&buf, binary.LittleEndian, uint32(rate*channels*bits/8))
+	binary.Write(&buf, binary.LittleEndian, uint16(channels*bits/8))
+	binary.Write(&buf, binary.LittleEndian, uint16(bits))
+
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(len(data)))
+	buf.Write(data)
+
+	return buf.Bytes()
+}
+
+// testStreamedWAV returns a wav file with the unknown sizes streaming encoders write
+func testStreamedWAV(data []byte) []byte {
+	result := encodeWAV(data)
+
+	binary.LittleEndian.PutUint32(result[4:], math.MaxUint32)
+	binary.LittleEndian.PutUint32(result[40:], math.MaxUint32)
+
+	return result
+}
@@ -0,0 +1,113 @@
+package openai
+
+import (
+	"io"
+	"sync"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+// responseStore keeps recent responses with their conversation, so requests can continue them by previous_response_id.
+// The oldest responses are evicted once the store is full.
+type responseStore struct {
+	size int
+
+	mu    sync.Mutex
+	order []string
+	items map[string]storedResponse
+}
+
+type storedResponse struct {
+	Response Response
+
+	// the conversation including the output of the response, without the instructions
+	Messages []provider.Message
+}
+
+func newResponseStore(size int) *responseStore {
+	return &responseStore{
+		size: size,
+
+		items: make(map[string]storedResponse),
+	}
+}
+
+func (s *responseStore) Get(id string) (storedResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.items[id]
+
+	if !ok {
+		return item, false
+	}
+
+	item.Messages = cloneMessages(item.Messages)
+	return item, true
+}
+
+func (s *responseStore) Set(id string, item storedResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.items[id]; !ok {
+		s.order = append(s.order, id)
+	}
+
+	s.items[id] = item
+
+	for len(s.order) > s.size {
+		delete(s.items, s.order[0])
+		s.order = s.order[1:]
+	}
+}
+
+func (s *responseStore) Delete(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.items[id]; !ok {
+		return false
+	}
+
+	delete(s.items, id)
+
+	for i, o := range s.order {
+		if o == id {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+
+	return true
+}
+
+// cloneMessages returns a copy of the messages whose files can be read independently of the originals
+func cloneMessages(messages []provider.Message) []provider.Message {
+	result := make([]provider.Message, 0, len(messages))
+
+	for _, m := range messages {
+		if len(m.Files) == 0 {
+			result = append(result, m)
+			continue
+		}
+
+		files := make([]provider.File, 0, len(m.Files))
+
+		for _, f := range m.Files {
+			if r, ok := f.Content.(interface {
+				io.ReaderAt
+				Size() int64
+			}); ok {
+				f.Content = io.NewSectionReader(r, 0, r.Size())
+			}
+
+			files = append(files, f)
+		}
+
+		m.Files = files
+		result = append(result, m)
+	}
+
+	return result
+}
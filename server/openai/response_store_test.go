@@ -0,0 +1,71 @@
+package openai
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseStoreEviction(t *testing.T) {
+	s := newResponseStore(2)
+
+	for i := range 3 {
+		s.Set(strconv.Itoa(i), storedResponse{})
+	}
+
+	// the oldest response is evicted once the store is full
+	_, ok := s.Get("0")
+	require.False(t, ok)
+
+	_, ok = s.Get("1")
+	require.True(t, ok)
+
+	// replacing a response does not evict another one
+	s.Set("2", storedResponse{})
+
+	_, ok = s.Get("1")
+	require.True(t, ok)
+
+	// a deleted response frees its slot
+	require.True(t, s.Delete("1"))
+	require.False(t, s.Delete("1"))
+
+	s.Set("3", storedResponse{})
+
+	_, ok = s.Get("2")
+	require.True(t, ok)
+
+	_, ok = s.Get("3")
+	require.True(t, ok)
+}
+
+func TestResponseStoreFiles(t *testing.T) {
+	s := newResponseStore(1)
+
+	s.Set("1", storedResponse{
+		Messages: []provider.Message{
+			{
+				Role: provider.MessageRoleUser,
+
+				Files: []provider.File{
+					{Name: "a.txt", Content: bytes.NewReader([]byte("first"))},
+				},
+			},
+		},
+	})
+
+	// every continuation reads the files of the conversation from the start
+	for range 2 {
+		item, ok := s.Get("1")
+		require.True(t, ok)
+
+		data, err := io.ReadAll(item.Messages[0].Files[0].Content)
+		require.NoError(t, err)
+		require.Equal(t, "first", string(data))
+	}
+}
@@ -0,0 +1,19 @@
+package openai
+
+import (
+	"net/http"
+
+	"github.com/adrianliechti/wingman/server/sse"
+)
+
+// writeStreamError reports a failed request as an error event, or as a plain error response if nothing was sent yet
+func writeStreamError(s *sse.Stream, err error) error {
+	return s.Error("", ErrorResponse{
+		Error: Error{
+			Type:    "server_error",
+			Message: err.Error(),
+		},
+	}, func(w http.ResponseWriter) {
+		writeError(w, http.StatusBadRequest, err)
+	})
+}
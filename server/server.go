@@ -1,11 +1,17 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/adrianliechti/wingman/config"
+	"github.com/adrianliechti/wingman/server/anthropic"
 	"github.com/adrianliechti/wingman/server/api"
 	"github.com/adrianliechti/wingman/server/index"
+	"github.com/adrianliechti/wingman/server/mcp"
+	"github.com/adrianliechti/wingman/server/ollama"
 	"github.com/adrianliechti/wingman/server/openai"
 	"github.com/adrianliechti/wingman/server/unstructured"
 
@@ -20,9 +26,14 @@ type Server struct {
 	*config.Config
 	http.Handler
 
-	api    *api.Handler
-	index  *index.Handler
-	openai *openai.Handler
+	api       *api.Handler
+	index     *index.Handler
+	openai    *openai.Handler
+	anthropic *anthropic.Handler
+
+	mcp *mcp.Handler
+
+	ollama *ollama.Handler
 
 	unstructured *unstructured.Handler
 }
@@ -40,12 +51,30 @@ func New(cfg *config.Config) (*Server, error) {
 		return nil, err
 	}
 
+	anthropic, err := anthropic.New(cfg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ollama, err := ollama.New(cfg)
+
+	if err != nil {
+		return nil, err
+	}
+
 	index, err := index.New(cfg)
 
 	if err != nil {
 		return nil, err
 	}
 
+	mcp, err := mcp.New(cfg)
+
+	if err != nil {
+		return nil, err
+	}
+
 	unstructured, err := unstructured.New(cfg)
 
 	if err != nil {
@@ -58,9 +87,14 @@ func New(cfg *config.Config) (*Server, error) {
 		Config:  cfg,
 		Handler: mux,
 
-		api:    api,
-		index:  index,
-		openai: openai,
+		api:       api,
+		index:     index,
+		openai:    openai,
+		anthropic: anthropic,
+
+		mcp: mcp,
+
+		ollama: ollama,
 
 		unstructured: unstructured,
 	}
@@ -96,6 +130,9 @@ func New(cfg *config.Config) (*Server, error) {
 	mux.Route("/v1", func(r chi.Router) {
 		s.api.Attach(r)
 		s.openai.Attach(r)
+		s.anthropic.Attach(r)
+
+		s.mcp.Attach(r)
 
 		s.unstructured.Attach(r)
 	})
@@ -104,6 +141,10 @@ func New(cfg *config.Config) (*Server, error) {
 		s.index.Attach(r)
 	})
 
+	mux.Route("/api", func(r chi.Router) {
+		s.ollama.Attach(r)
+	})
+
 	for name, handler := range cfg.APIs {
 		mux.Mount("/api/"+name, handler)
 	}
@@ -111,14 +152,38 @@ func New(cfg *config.Config) (*Server, error) {
 	return s, nil
 }
 
-func (s *Server) ListenAndServe() error {
-	return http.ListenAndServe(s.Address, s)
+// ListenAndServe serves until the context is done, then finishes the open requests and closes the configured providers
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	server := &http.Server{
+		Addr:    s.Address,
+		Handler: s,
+	}
+
+	go func() {
+		<-ctx.Done()
+		server.Shutdown(context.Background())
+	}()
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return s.Config.Close()
 }
 
 func (s *Server) handleAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
+		// browsers cannot set headers on websockets, so realtime clients pass the key as subprotocol
+		if r.Header.Get("Authorization") == "" {
+			for _, p := range strings.Split(r.Header.Get("Sec-WebSocket-Protocol"), ",") {
+				if key, ok := strings.CutPrefix(strings.TrimSpace(p), "openai-insecure-api-key."); ok {
+					r.Header.Set("Authorization", "Bearer "+key)
+				}
+			}
+		}
+
 		var authorized = len(s.Authorizers) == 0
 
 		for _, a := range s.Authorizers {
@@ -0,0 +1,195 @@
+package sse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// heartbeatInterval keeps idle streams, e.g. during long tool calls, below common proxy idle timeouts
+const heartbeatInterval = 15 * time.Second
+
+var ErrClosed = errors.New("stream closed")
+
+// Stream writes server-sent events and keeps the connection alive while the upstream is busy.
+// Once a write fails the client is gone, so the stream is closed and the upstream request is cancelled.
+type Stream struct {
+	w  http.ResponseWriter
+	rc *http.ResponseController
+
+	cancel context.CancelFunc
+
+	// ping is written while the stream is idle
+	ping string
+
+	mu sync.Mutex
+
+	err     error
+	started bool
+	written time.Time
+}
+
+type Option func(*Stream)
+
+// WithHeartbeat sends the given event while the stream is idle instead of a comment line
+func WithHeartbeat(name string, v any) Option {
+	return func(s *Stream) {
+		if data, err := encode(name, v); err == nil {
+			s.ping = data
+		}
+	}
+}
+
+// New returns a stream and the context to run the upstream request with
+func New(w http.ResponseWriter, r *http.Request, options ...Option) (*Stream, context.Context) {
+	ctx, cancel := context.WithCancel(r.Context())
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	s := &Stream{
+		w:  w,
+		rc: http.NewResponseController(w),
+
+		cancel: cancel,
+
+		// comment lines are ignored by clients, but count as traffic for proxies
+		ping: ": ping\n\n",
+
+		written: time.Now(),
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	go s.heartbeat(ctx)
+
+	return s, ctx
+}
+
+// Error reports a failed request as an event, or calls fallback to write a plain error response if nothing was sent yet
+func (s *Stream) Error(name string, v any, fallback func(w http.ResponseWriter)) error {
+	s.mu.Lock()
+
+	started := s.started
+
+	if !started {
+		s.err = ErrClosed
+	}
+
+	s.mu.Unlock()
+
+	if !started {
+		fallback(s.w)
+		return nil
+	}
+
+	return s.Send(name, v)
+}
+
+// Send writes an event, omitting the event line if name is empty
+func (s *Stream) Send(name string, v any) error {
+	data, err := encode(name, v)
+
+	if err != nil {
+		return err
+	}
+
+	return s.write(data)
+}
+
+// SendData writes an event with raw data, e.g. a stream terminator
+func (s *Stream) SendData(name string, data string) error {
+	return s.write(format(name, data))
+}
+
+// Close stops the heartbeat and cancels the upstream request if it is still running
+func (s *Stream) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err == nil {
+		s.err = ErrClosed
+	}
+
+	s.cancel()
+}
+
+func (s *Stream) write(data string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+
+	s.started = true
+	s.written = time.Now()
+
+	if _, err := s.w.Write([]byte(data)); err != nil {
+		return s.fail(err)
+	}
+
+	if err := s.rc.Flush(); err != nil {
+		return s.fail(err)
+	}
+
+	return nil
+}
+
+func (s *Stream) fail(err error) error {
+	s.err = err
+	s.cancel()
+
+	return err
+}
+
+func (s *Stream) heartbeat(ctx context.Context) {
+	ticker := time.NewTicker(heartbeatInterval / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			s.mu.Lock()
+			idle := s.err == nil && time.Since(s.written) >= heartbeatInterval
+			s.mu.Unlock()
+
+			if idle {
+				s.write(s.ping)
+			}
+		}
+	}
+}
+
+func encode(name string, v any) (string, error) {
+	var data bytes.Buffer
+
+	enc := json.NewEncoder(&data)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+
+	return format(name, strings.TrimSpace(data.String())), nil
+}
+
+func format(name string, data string) string {
+	if name == "" {
+		return fmt.Sprintf("data: %s\n\n", data)
+	}
+
+	return fmt.Sprintf("event: %s\ndata: %s\n\n", name, data)
+}
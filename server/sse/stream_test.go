@@ -0,0 +1,85 @@
+package sse_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adrianliechti/wingman/server/sse"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSend(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	s, _ := sse.New(w, httptest.NewRequest(http.MethodPost, "/", nil))
+	defer s.Close()
+
+	require.NoError(t, s.Send("", map[string]string{"text": "<hello>"}))
+	require.NoError(t, s.Send("delta", map[string]string{"text": "world"}))
+	require.NoError(t, s.SendData("", "[DONE]"))
+
+	require.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	require.Equal(t, "data: {\"text\":\"<hello>\"}\n\nevent: delta\ndata: {\"text\":\"world\"}\n\ndata: [DONE]\n\n", w.Body.String())
+}
+
+func TestErrorBeforeStart(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	s, _ := sse.New(w, httptest.NewRequest(http.MethodPost, "/", nil))
+	defer s.Close()
+
+	err := s.Error("error", map[string]string{"message": "failed"}, func(w http.ResponseWriter) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Empty(t, w.Body.String())
+
+	require.ErrorIs(t, s.Send("", "late"), sse.ErrClosed)
+}
+
+func TestErrorAfterStart(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	s, _ := sse.New(w, httptest.NewRequest(http.MethodPost, "/", nil))
+	defer s.Close()
+
+	require.NoError(t, s.Send("", "first"))
+
+	err := s.Error("error", map[string]string{"message": "failed"}, func(w http.ResponseWriter) {
+		t.Fatal("fallback called after the stream started")
+	})
+
+	require.NoError(t, err)
+	require.Contains(t, w.Body.String(), "event: error\ndata: {\"message\":\"failed\"}\n\n")
+}
+
+func TestWriteFailure(t *testing.T) {
+	s, ctx := sse.New(&brokenWriter{header: http.Header{}}, httptest.NewRequest(http.MethodPost, "/", nil))
+	defer s.Close()
+
+	require.Error(t, s.Send("", "data"))
+	require.ErrorIs(t, ctx.Err(), context.Canceled)
+
+	require.Error(t, s.Send("", "data"))
+}
+
+// brokenWriter fails every write, like a connection closed by the client
+type brokenWriter struct {
+	header http.Header
+}
+
+func (w *brokenWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *brokenWriter) Write([]byte) (int, error) {
+	return 0, errors.New("connection closed")
+}
+
+func (w *brokenWriter) WriteHeader(int) {}